@@ -14,12 +14,16 @@ import (
 	clientsRepo "agro-monitoring/internal/modules/clients/repository"
 	clientsService "agro-monitoring/internal/modules/clients/service"
 	clientsUsecase "agro-monitoring/internal/modules/clients/usecase"
+	jobsDomain "agro-monitoring/internal/modules/jobs/domain"
 	jobsHandler "agro-monitoring/internal/modules/jobs/handler"
 	jobsRepo "agro-monitoring/internal/modules/jobs/repository"
 	jobsUsecase "agro-monitoring/internal/modules/jobs/usecase"
 	monitoringHandler "agro-monitoring/internal/modules/monitoring/handler"
 	monitoringRepo "agro-monitoring/internal/modules/monitoring/repository"
 	monitoringUsecase "agro-monitoring/internal/modules/monitoring/usecase"
+	schedulesHandler "agro-monitoring/internal/modules/schedules/handler"
+	schedulesRepo "agro-monitoring/internal/modules/schedules/repository"
+	schedulesUsecase "agro-monitoring/internal/modules/schedules/usecase"
 	userHandler "agro-monitoring/internal/modules/user/handler"
 	"agro-monitoring/internal/services/csv"
 	"agro-monitoring/internal/services/queue"
@@ -28,13 +32,14 @@ import (
 
 // Application contém todas as dependências
 type Application struct {
-	Env         *Env
-	DB          *sql.DB
-	Redis       *redis.Client
-	QueueSvc    queue.Service
-	Router      http.Handler
-	JobsUseCase jobsUsecase.JobUseCase
-	Auth        *sharedMiddleware.Authenticator
+	Env              *Env
+	DB               *sql.DB
+	Redis            *redis.Client
+	QueueSvc         queue.Service
+	Router           http.Handler
+	JobsUseCase      jobsUsecase.JobUseCase
+	SchedulesUseCase schedulesUsecase.ScheduleUseCase
+	Auth             *sharedMiddleware.Authenticator
 }
 
 // NewApplication cria a aplicação com todas as dependências
@@ -59,6 +64,12 @@ func NewApplication() (*Application, error) {
 		DB:       env.RedisDB,
 	})
 
+	// Storage dos arquivos enviados
+	fileStorage, err := NewStorage(env)
+	if err != nil {
+		return nil, err
+	}
+
 	// Queue Service
 	queueSvc := queue.NewRedisQueueService(&redis.Options{
 		Addr:     env.RedisAddr(),
@@ -70,12 +81,18 @@ func NewApplication() (*Application, error) {
 		return uuid.New().String()
 	}
 
-	// Repositories
-	monRepo := monitoringRepo.NewPostgresRepository(db)
-	areaRepository := areaRepo.NewPostgresRepository(db)
-	jobRepository := jobsRepo.NewPostgresRepository(db)
-	clientRepository := clientsRepo.NewPostgresRepository(db)
-	clientUserRepository := clientsRepo.NewClientUserPostgresRepository(db)
+	// Repositories (transações com app.client_id para RLS)
+	tenantDB := NewTenantDatabase(db)
+	monRepo := monitoringRepo.NewPostgresRepository(tenantDB)
+	monErroRepo := monitoringRepo.NewPostgresErroRepository(tenantDB)
+	monProfileRepo := monitoringRepo.NewPostgresProfileRepository(tenantDB)
+	areaRepository := areaRepo.NewPostgresRepository(tenantDB)
+	areaCatalogRepository := areaRepo.NewAreaPostgresRepository(tenantDB)
+	jobRepository := jobsRepo.NewPostgresRepository(tenantDB)
+	clientRepository := clientsRepo.NewPostgresRepository(tenantDB)
+	clientUserRepository := clientsRepo.NewClientUserPostgresRepository(tenantDB)
+	clientInviteRepository := clientsRepo.NewClientInvitePostgresRepository(tenantDB)
+	scheduleRepository := schedulesRepo.NewPostgresRepository(tenantDB)
 
 	// Parser
 	csvParser := csv.NewParser(uuidGen)
@@ -84,34 +101,39 @@ func NewApplication() (*Application, error) {
 	keycloakSvc := clientsService.NewKeycloakService(env)
 
 	// Use cases
-	monUC := monitoringUsecase.NewMonitoringUseCase(monRepo, areaRepository, csvParser, uuidGen)
-	areaUC := areaUsecase.NewAreaQueryUseCase(areaRepository)
 	jobUC := jobsUsecase.NewJobUseCase(jobsUsecase.Config{
 		UUIDGenerator: uuidGen,
 		JobRepo:       jobRepository,
 		AreaRepo:      areaRepository,
 		Queue:         queueSvc,
 	})
-	clientUC := clientsUsecase.NewClientUseCase(clientRepository, clientUserRepository, keycloakSvc, uuidGen)
+	monUC := monitoringUsecase.NewMonitoringUseCase(monRepo, areaRepository, areaCatalogRepository, monErroRepo, monProfileRepo, csvParser, uuidGen, jobUC, fileStorage)
+	jobUC.RegisterProcessor(jobsDomain.JobTypeCSVImport, monUC.ProcessCSVImport)
+	areaUC := areaUsecase.NewAreaQueryUseCase(areaRepository)
+	clientUC := clientsUsecase.NewClientUseCase(clientRepository, clientUserRepository, clientInviteRepository, keycloakSvc, uuidGen, fileStorage)
+	scheduleUC := schedulesUsecase.NewScheduleUseCase(scheduleRepository, uuidGen)
+	RegisterScheduledTasks(scheduleUC, clientUC, jobUC)
 
 	// Handlers
-	monHandler := monitoringHandler.NewHandler(monUC)
+	monHandler := monitoringHandler.NewHandler(monUC, clientUC)
 	areaHdlr := areaHandler.NewHandler(areaUC)
 	jobHdlr := jobsHandler.NewHandler(jobUC)
 	userHdlr := userHandler.NewUserHandler()
 	clientsHdlr := clientsHandler.NewHandler(clientUC, env)
+	schedulesHdlr := schedulesHandler.NewHandler(scheduleUC)
 
 	// Router
-	router := SetupRoutes(monHandler, areaHdlr, jobHdlr, userHdlr, clientsHdlr, auth)
+	router := SetupRoutes(monHandler, areaHdlr, jobHdlr, userHdlr, clientsHdlr, schedulesHdlr, auth)
 
 	return &Application{
-		Env:         env,
-		DB:          db,
-		Redis:       redisClient,
-		QueueSvc:    queueSvc,
-		Router:      router,
-		JobsUseCase: jobUC,
-		Auth:        auth,
+		Env:              env,
+		DB:               db,
+		Redis:            redisClient,
+		QueueSvc:         queueSvc,
+		Router:           router,
+		JobsUseCase:      jobUC,
+		SchedulesUseCase: scheduleUC,
+		Auth:             auth,
 	}, nil
 }
 
@@ -6,6 +6,8 @@ import (
 	"time"
 
 	_ "github.com/lib/pq"
+
+	"agro-monitoring/internal/shared/database"
 )
 
 // NewDatabase cria conexão com PostgreSQL
@@ -27,3 +29,8 @@ func NewDatabase(env *Env) (*sql.DB, error) {
 
 	return db, nil
 }
+
+// NewTenantDatabase envolve a conexão para transações com escopo de tenant (RLS)
+func NewTenantDatabase(db *sql.DB) *database.TenantDB {
+	return database.NewTenantDB(db)
+}
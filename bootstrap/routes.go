@@ -10,6 +10,7 @@ import (
 	clientsHandler "agro-monitoring/internal/modules/clients/handler"
 	jobsHandler "agro-monitoring/internal/modules/jobs/handler"
 	monitoringHandler "agro-monitoring/internal/modules/monitoring/handler"
+	schedulesHandler "agro-monitoring/internal/modules/schedules/handler"
 	userHandler "agro-monitoring/internal/modules/user/handler"
 	sharedMiddleware "agro-monitoring/internal/shared/middleware"
 )
@@ -21,6 +22,7 @@ func SetupRoutes(
 	jobHdlr *jobsHandler.Handler,
 	userHdlr *userHandler.UserHandler,
 	clientsHdlr *clientsHandler.Handler,
+	schedulesHdlr *schedulesHandler.Handler,
 	auth *sharedMiddleware.Authenticator,
 ) http.Handler {
 	r := chi.NewRouter()
@@ -48,17 +50,22 @@ func SetupRoutes(
 		// Rotas clients (/v1/clients/me, /v1/register/{slug})
 		clientsHdlr.RegisterRoutes(r)
 
-		// Rotas existentes (com multi-tenancy)
-		// TODO: Adicionar middleware RequireClient quando estiver pronto
-		monHandler.RegisterRoutes(r)
-		areaHdlr.RegisterRoutes(r)
-		jobHdlr.RegisterRoutes(r)
+		// Rotas com dados do tenant (exigem client_id no token)
+		r.Group(func(r chi.Router) {
+			r.Use(sharedMiddleware.RequireClient)
+			monHandler.RegisterRoutes(r)
+			areaHdlr.RegisterRoutes(r)
+			jobHdlr.RegisterRoutes(r)
+		})
+
 		userHdlr.RegisterRoutes(r)
 
-		// Rotas admin (futuramente com middleware RequireAdminRole)
+		// Rotas admin da plataforma (role KEYCLOAK_ADMIN_ROLE)
 		r.Route("/admin", func(r chi.Router) {
-			// TODO: Adicionar middleware RequireAdminRole
+			r.Use(auth.RequireAdminRole)
 			clientsHdlr.RegisterAdminRoutes(r)
+			jobHdlr.RegisterAdminRoutes(r)
+			schedulesHdlr.RegisterAdminRoutes(r)
 		})
 	})
 
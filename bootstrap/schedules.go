@@ -0,0 +1,83 @@
+package bootstrap
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	clientsUsecase "agro-monitoring/internal/modules/clients/usecase"
+	jobsUsecase "agro-monitoring/internal/modules/jobs/usecase"
+	schedulesDomain "agro-monitoring/internal/modules/schedules/domain"
+	schedulesUsecase "agro-monitoring/internal/modules/schedules/usecase"
+)
+
+const (
+	// defaultJobRetentionDays retenção dos jobs finalizados quando o payload de cleanup_jobs não informa
+	defaultJobRetentionDays = 30
+	// defaultStaleJobHours horas sem atualização até cleanup_jobs marcar um job pending/processing como failed
+	defaultStaleJobHours = 24
+	// defaultReportDays dias cobertos pelo relatório de estatísticas quando o payload não informa
+	defaultReportDays = 7
+)
+
+// RegisterScheduledTasks registra as tarefas que os agendamentos podem executar
+func RegisterScheduledTasks(uc schedulesUsecase.ScheduleUseCase, clientUC clientsUsecase.ClientUseCase, jobUC jobsUsecase.JobUseCase) {
+	uc.RegisterTask(schedulesDomain.TaskSnapshotClientStats, func(ctx context.Context, _ json.RawMessage) error {
+		saved, err := clientUC.SnapshotStats(ctx)
+		log.Printf("Estatísticas do dia gravadas para %d clients", saved)
+		return err
+	})
+
+	uc.RegisterTask(schedulesDomain.TaskPurgeDeletedClients, func(ctx context.Context, _ json.RawMessage) error {
+		purged, err := clientUC.PurgeDeletedClients(ctx)
+		log.Printf("%d clients removidos apagados definitivamente", purged)
+		return err
+	})
+
+	// Payload: {"days": 7}
+	uc.RegisterTask(schedulesDomain.TaskGenerateStatsReports, func(ctx context.Context, payload json.RawMessage) error {
+		var params struct {
+			Days int `json:"days"`
+		}
+		if err := json.Unmarshal(payload, &params); err != nil {
+			return fmt.Errorf("payload inválido: %w", err)
+		}
+		if params.Days < 1 {
+			params.Days = defaultReportDays
+		}
+
+		generated, err := clientUC.GenerateStatsReports(ctx, params.Days)
+		log.Printf("Relatório de estatísticas dos últimos %d dias gerado para %d clients", params.Days, generated)
+		return err
+	})
+
+	// Jobs parados em pending/processing viram failed; os finalizados são apagados após a retenção.
+	// Payload: {"stale_after_hours": 24, "older_than_days": 30}
+	uc.RegisterTask(schedulesDomain.TaskCleanupJobs, func(ctx context.Context, payload json.RawMessage) error {
+		var params struct {
+			StaleAfterHours int `json:"stale_after_hours"`
+			OlderThanDays   int `json:"older_than_days"`
+		}
+		if err := json.Unmarshal(payload, &params); err != nil {
+			return fmt.Errorf("payload inválido: %w", err)
+		}
+		if params.StaleAfterHours < 1 {
+			params.StaleAfterHours = defaultStaleJobHours
+		}
+		if params.OlderThanDays < 1 {
+			params.OlderThanDays = defaultJobRetentionDays
+		}
+
+		failed, err := jobUC.FailStaleJobs(ctx, time.Duration(params.StaleAfterHours)*time.Hour)
+		log.Printf("%d jobs parados há mais de %d horas marcados como failed", failed, params.StaleAfterHours)
+		if err != nil {
+			return err
+		}
+
+		deleted, err := jobUC.CleanupJobs(ctx, time.Duration(params.OlderThanDays)*24*time.Hour)
+		log.Printf("%d jobs finalizados há mais de %d dias apagados", deleted, params.OlderThanDays)
+		return err
+	})
+}
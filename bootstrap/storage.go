@@ -0,0 +1,28 @@
+package bootstrap
+
+import (
+	"fmt"
+
+	"agro-monitoring/internal/services/storage"
+)
+
+// NewStorage cria o storage dos arquivos enviados conforme STORAGE_DRIVER
+func NewStorage(env *Env) (storage.Service, error) {
+	switch env.StorageDriver {
+	case "local":
+		return storage.NewLocalStorage(env.UploadDir), nil
+	case "s3":
+		if env.S3Bucket == "" || env.S3AccessKey == "" || env.S3SecretKey == "" {
+			return nil, fmt.Errorf("storage s3 requer S3_BUCKET, S3_ACCESS_KEY e S3_SECRET_KEY")
+		}
+		return storage.NewS3Storage(storage.S3Config{
+			Endpoint:  env.S3Endpoint,
+			Bucket:    env.S3Bucket,
+			Region:    env.S3Region,
+			AccessKey: env.S3AccessKey,
+			SecretKey: env.S3SecretKey,
+		}), nil
+	default:
+		return nil, fmt.Errorf("STORAGE_DRIVER inválido: %q (use local ou s3)", env.StorageDriver)
+	}
+}
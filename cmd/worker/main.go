@@ -33,6 +33,9 @@ func main() {
 		cancel()
 	}()
 
+	// Agendamentos recorrentes (stats, purge, limpeza de jobs)
+	go app.SchedulesUseCase.RunScheduler(ctx)
+
 	// Inicia processamento de jobs
 	log.Println("Worker rodando. Pressione Ctrl+C para encerrar.")
 	app.JobsUseCase.RegisterAndProcessJobs(ctx)
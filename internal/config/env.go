@@ -32,8 +32,17 @@ type Env struct {
 	KeycloakClientID          string
 	KeycloakAdminClientID     string
 	KeycloakAdminClientSecret string
+	KeycloakAdminRole         string
 	// App
 	AppBaseURL string
+	UploadDir  string
+	// Storage dos arquivos enviados: "local" (UploadDir) ou "s3"
+	StorageDriver string
+	S3Endpoint    string
+	S3Bucket      string
+	S3Region      string
+	S3AccessKey   string
+	S3SecretKey   string
 }
 
 // NewEnv carrega as variáveis de ambiente
@@ -57,8 +66,17 @@ func NewEnv() *Env {
 		KeycloakClientID:          getEnv("KEYCLOAK_CLIENT_ID", "agro-api"),
 		KeycloakAdminClientID:     getEnv("KEYCLOAK_ADMIN_CLIENT_ID", "agro-admin"),
 		KeycloakAdminClientSecret: getEnv("KEYCLOAK_ADMIN_CLIENT_SECRET", ""),
+		KeycloakAdminRole:         getEnv("KEYCLOAK_ADMIN_ROLE", "admin"),
 		// App
 		AppBaseURL: getEnv("APP_BASE_URL", "http://localhost:8080"),
+		UploadDir:  getEnv("UPLOAD_DIR", "./data/uploads"),
+		// Storage
+		StorageDriver: getEnv("STORAGE_DRIVER", "local"),
+		S3Endpoint:    getEnv("S3_ENDPOINT", "http://localhost:9000"),
+		S3Bucket:      getEnv("S3_BUCKET", "agro-uploads"),
+		S3Region:      getEnv("S3_REGION", "us-east-1"),
+		S3AccessKey:   getEnv("S3_ACCESS_KEY", ""),
+		S3SecretKey:   getEnv("S3_SECRET_KEY", ""),
 	}
 }
 
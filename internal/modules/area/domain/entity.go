@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"strings"
 	"time"
 )
 
@@ -8,6 +9,10 @@ import (
 type AreaMonitoramento struct {
 	ID              string
 	MonitoramentoID string
+	ClientID        string
+	UserID          string
+	ExternalKey     string // chave estável da área no arquivo de origem (ver ExternalKeyFor)
+	AreaID          string // área física vinculada; vazio quando importada sem modo upsert
 	Setor           string
 	Setor2          string
 	CodFazenda      string
@@ -26,6 +31,46 @@ type AreaMonitoramento struct {
 	UpdatedAt       time.Time
 }
 
+// Area representa a área física (talhão) acompanhada ao longo dos monitoramentos
+type Area struct {
+	ID          string
+	ClientID    string
+	ExternalKey string
+	CodFazenda  string
+	Quadra      string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// NewArea cria uma área física a partir de uma observação com chave externa
+func NewArea(id string, obs *AreaMonitoramento) *Area {
+	now := time.Now()
+	return &Area{
+		ID:          id,
+		ExternalKey: obs.ExternalKey,
+		CodFazenda:  obs.CodFazenda,
+		Quadra:      obs.Quadra,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
+
+// ExternalKeyFor monta a chave externa: o "Id" do arquivo quando preenchido,
+// senão "CodFazenda/Quadra". Retorna vazio se não houver dados suficientes.
+func ExternalKeyFor(sourceID, codFazenda, quadra string) string {
+	sourceID = strings.TrimSpace(sourceID)
+	if sourceID != "" {
+		return sourceID
+	}
+
+	codFazenda = strings.TrimSpace(codFazenda)
+	quadra = strings.TrimSpace(quadra)
+	if codFazenda == "" || quadra == "" {
+		return ""
+	}
+	return codFazenda + "/" + quadra
+}
+
 // AplicacaoHerbicidaJson representa uma aplicação de herbicida em uma área
 type AplicacaoHerbicidaJson struct {
 	Posicao   int       `json:"posicao"`
@@ -48,3 +48,10 @@ func TestAreaMonitoramento_AddPraga(t *testing.T) {
 	assert.True(t, area.PragasData.HasPraga("Vassoura"))
 	assert.False(t, area.PragasData.HasPraga("Tiririca"))
 }
+
+func TestExternalKeyFor(t *testing.T) {
+	assert.Equal(t, "123", ExternalKeyFor(" 123 ", "FAZ001", "Q1"))
+	assert.Equal(t, "FAZ001/Q1", ExternalKeyFor("", "FAZ001", " Q1"))
+	assert.Equal(t, "", ExternalKeyFor("", "FAZ001", ""))
+	assert.Equal(t, "", ExternalKeyFor("", "", ""))
+}
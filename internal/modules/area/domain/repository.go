@@ -7,7 +7,16 @@ type AreaMonitoramentoRepository interface {
 	CreateBatch(ctx context.Context, areas []*AreaMonitoramento) error
 	GetByID(ctx context.Context, id string) (*AreaMonitoramento, error)
 	GetByMonitoramentoID(ctx context.Context, monitoramentoID string, limit, offset int) ([]*AreaMonitoramento, int, error)
+	GetByAreaID(ctx context.Context, areaID string, limit, offset int) ([]*AreaMonitoramento, int, error)
 	SearchByFazenda(ctx context.Context, codFazenda string, limit, offset int) ([]*AreaMonitoramento, int, error)
 	SearchByPraga(ctx context.Context, nomePraga string, limit, offset int) ([]*AreaMonitoramento, int, error)
 	UpdatePragasData(ctx context.Context, id string, pragasData PragasData) error
+	DeleteByMonitoramentoID(ctx context.Context, monitoramentoID string) (int, error)
+}
+
+// AreaRepository define as operações de persistência das áreas físicas
+type AreaRepository interface {
+	// UpsertBatch cria as áreas que ainda não existem para o tenant (por ExternalKey)
+	// e preenche o ID de cada item com o da área existente ou criada
+	UpsertBatch(ctx context.Context, areas []*Area) error
 }
@@ -8,22 +8,25 @@ import (
 
 // AreaResponse resposta de área
 type AreaResponse struct {
-	ID              string                 `json:"id"`
-	MonitoramentoID string                 `json:"monitoramento_id"`
-	Setor           string                 `json:"setor"`
-	Setor2          string                 `json:"setor2"`
-	CodFazenda      string                 `json:"cod_fazenda"`
-	DescFazenda     string                 `json:"desc_fazenda"`
-	Quadra          string                 `json:"quadra"`
-	Corte           int                    `json:"corte"`
-	AreaTotal       float64                `json:"area_total"`
-	DescTexturaSolo string                 `json:"desc_textura_solo"`
-	CorteAtual      int                    `json:"corte_atual"`
-	Reforma         string                 `json:"reforma"`
-	MesColheita     string                 `json:"mes_colheita"`
-	Restricao       string                 `json:"restricao"`
-	PragasData      map[string]interface{} `json:"pragas_data"`
-	CreatedAt       time.Time              `json:"created_at"`
+	ID              string                          `json:"id"`
+	MonitoramentoID string                          `json:"monitoramento_id"`
+	ExternalKey     string                          `json:"external_key,omitempty"`
+	AreaID          string                          `json:"area_id,omitempty"`
+	Setor           string                          `json:"setor"`
+	Setor2          string                          `json:"setor2"`
+	CodFazenda      string                          `json:"cod_fazenda"`
+	DescFazenda     string                          `json:"desc_fazenda"`
+	Quadra          string                          `json:"quadra"`
+	Corte           int                             `json:"corte"`
+	AreaTotal       float64                         `json:"area_total"`
+	DescTexturaSolo string                          `json:"desc_textura_solo"`
+	CorteAtual      int                             `json:"corte_atual"`
+	Reforma         string                          `json:"reforma"`
+	MesColheita     string                          `json:"mes_colheita"`
+	Restricao       string                          `json:"restricao"`
+	PragasData      map[string]interface{}          `json:"pragas_data"`
+	Aplicacoes      []domain.AplicacaoHerbicidaJson `json:"aplicacoes"`
+	CreatedAt       time.Time                       `json:"created_at"`
 }
 
 // ListAreasResponse resposta paginada de áreas
@@ -55,6 +58,8 @@ func ToAreaResponse(a *domain.AreaMonitoramento) AreaResponse {
 	return AreaResponse{
 		ID:              a.ID,
 		MonitoramentoID: a.MonitoramentoID,
+		ExternalKey:     a.ExternalKey,
+		AreaID:          a.AreaID,
 		Setor:           a.Setor,
 		Setor2:          a.Setor2,
 		CodFazenda:      a.CodFazenda,
@@ -68,6 +73,7 @@ func ToAreaResponse(a *domain.AreaMonitoramento) AreaResponse {
 		MesColheita:     a.MesColheita,
 		Restricao:       a.Restricao,
 		PragasData:      pragasMap,
+		Aplicacoes:      a.Aplicacoes,
 		CreatedAt:       a.CreatedAt,
 	}
 }
@@ -30,6 +30,7 @@ func (h *Handler) RegisterRoutes(r chi.Router) {
 		r.Get("/search/fazenda", h.SearchByFazenda)
 		r.Get("/search/praga", h.SearchByPraga)
 		r.Get("/{id}", h.GetByID)
+		r.Get("/{id}/historico", h.Historico)
 		r.Post("/{id}/aplicacao", h.AddAplicacao)
 	})
 }
@@ -51,6 +52,25 @@ func (h *Handler) GetByID(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, http.StatusOK, dto.ToAreaResponse(area))
 }
 
+// Historico lista as observações da mesma área física ao longo dos monitoramentos
+func (h *Handler) Historico(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	page := getQueryInt(r, "page", 1)
+	pageSize := getQueryInt(r, "page_size", 10)
+
+	items, total, err := h.uc.GetHistorico(r.Context(), id, page, pageSize)
+	if err != nil {
+		if err == sharedErrors.ErrAreaMonitoramentoNotFound {
+			respondError(w, http.StatusNotFound, "Área não encontrada")
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "Erro ao buscar histórico")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, dto.ToListAreasResponse(items, page, pageSize, total))
+}
+
 // ListByMonitoramento lista áreas de um monitoramento
 func (h *Handler) ListByMonitoramento(w http.ResponseWriter, r *http.Request) {
 	monitoramentoID := r.URL.Query().Get("monitoramento_id")
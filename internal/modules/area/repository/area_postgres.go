@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"agro-monitoring/internal/modules/area/domain"
+	sharedContext "agro-monitoring/internal/shared/context"
+	"agro-monitoring/internal/shared/database"
+)
+
+// AreaPostgresRepository implementação PostgreSQL das áreas físicas
+type AreaPostgresRepository struct {
+	db *database.TenantDB
+}
+
+// NewAreaPostgresRepository cria um novo repository de áreas físicas
+func NewAreaPostgresRepository(db *database.TenantDB) *AreaPostgresRepository {
+	return &AreaPostgresRepository{db: db}
+}
+
+func (r *AreaPostgresRepository) UpsertBatch(ctx context.Context, areas []*domain.Area) error {
+	if len(areas) == 0 {
+		return nil
+	}
+
+	clientID, err := sharedContext.RequireClientID(ctx)
+	if err != nil {
+		return err
+	}
+
+	// O DO UPDATE (em vez de DO NOTHING) faz o RETURNING devolver também o id das áreas existentes
+	query := `
+		INSERT INTO areas (id, client_id, external_key, cod_fazenda, quadra, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (client_id, external_key) DO UPDATE
+		SET cod_fazenda = EXCLUDED.cod_fazenda, quadra = EXCLUDED.quadra, updated_at = EXCLUDED.updated_at
+		RETURNING id
+	`
+
+	return r.db.InTenant(ctx, clientID, func(tx *sql.Tx) error {
+		stmt, err := tx.PrepareContext(ctx, query)
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
+
+		for _, a := range areas {
+			a.ClientID = clientID
+			err := stmt.QueryRowContext(ctx,
+				a.ID,
+				a.ClientID,
+				a.ExternalKey,
+				a.CodFazenda,
+				a.Quadra,
+				a.CreatedAt,
+				a.UpdatedAt,
+			).Scan(&a.ID)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
@@ -2,10 +2,12 @@ package repository
 
 import (
 	"context"
+	"sort"
 	"strings"
 	"sync"
 
 	"agro-monitoring/internal/modules/area/domain"
+	sharedContext "agro-monitoring/internal/shared/context"
 	sharedErrors "agro-monitoring/internal/shared/errors"
 )
 
@@ -23,87 +25,125 @@ func NewInMemoryRepository() *InMemoryRepository {
 }
 
 func (r *InMemoryRepository) CreateBatch(ctx context.Context, areas []*domain.AreaMonitoramento) error {
+	clientID, err := sharedContext.RequireClientID(ctx)
+	if err != nil {
+		return err
+	}
+	userID, _ := sharedContext.GetUserID(ctx)
+
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
 	for _, a := range areas {
+		a.ClientID = clientID
+		a.UserID = userID
 		r.items[a.ID] = a
 	}
 	return nil
 }
 
 func (r *InMemoryRepository) GetByID(ctx context.Context, id string) (*domain.AreaMonitoramento, error) {
+	clientID, err := sharedContext.RequireClientID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
 	a, ok := r.items[id]
-	if !ok {
+	if !ok || a.ClientID != clientID {
 		return nil, sharedErrors.ErrAreaMonitoramentoNotFound
 	}
 	return a, nil
 }
 
 func (r *InMemoryRepository) GetByMonitoramentoID(ctx context.Context, monitoramentoID string, limit, offset int) ([]*domain.AreaMonitoramento, int, error) {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
+	return r.filter(ctx, limit, offset, func(a *domain.AreaMonitoramento) bool {
+		return a.MonitoramentoID == monitoramentoID
+	})
+}
 
-	var result []*domain.AreaMonitoramento
-	for _, a := range r.items {
-		if a.MonitoramentoID == monitoramentoID {
-			result = append(result, a)
-		}
-	}
+func (r *InMemoryRepository) GetByAreaID(ctx context.Context, areaID string, limit, offset int) ([]*domain.AreaMonitoramento, int, error) {
+	return r.filter(ctx, limit, offset, func(a *domain.AreaMonitoramento) bool {
+		return a.AreaID == areaID
+	})
+}
 
-	total := len(result)
+func (r *InMemoryRepository) SearchByFazenda(ctx context.Context, codFazenda string, limit, offset int) ([]*domain.AreaMonitoramento, int, error) {
+	return r.filter(ctx, limit, offset, func(a *domain.AreaMonitoramento) bool {
+		return strings.Contains(strings.ToLower(a.CodFazenda), strings.ToLower(codFazenda))
+	})
+}
 
-	if offset >= len(result) {
-		return []*domain.AreaMonitoramento{}, total, nil
+func (r *InMemoryRepository) SearchByPraga(ctx context.Context, nomePraga string, limit, offset int) ([]*domain.AreaMonitoramento, int, error) {
+	return r.filter(ctx, limit, offset, func(a *domain.AreaMonitoramento) bool {
+		return a.PragasData.HasPraga(nomePraga)
+	})
+}
+
+func (r *InMemoryRepository) UpdatePragasData(ctx context.Context, id string, pragasData domain.PragasData) error {
+	clientID, err := sharedContext.RequireClientID(ctx)
+	if err != nil {
+		return err
 	}
-	result = result[offset:]
 
-	if limit > 0 && limit < len(result) {
-		result = result[:limit]
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	a, ok := r.items[id]
+	if !ok || a.ClientID != clientID {
+		return sharedErrors.ErrAreaMonitoramentoNotFound
 	}
 
-	return result, total, nil
+	a.PragasData = pragasData
+	return nil
 }
 
-func (r *InMemoryRepository) SearchByFazenda(ctx context.Context, codFazenda string, limit, offset int) ([]*domain.AreaMonitoramento, int, error) {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
-
-	var result []*domain.AreaMonitoramento
-	for _, a := range r.items {
-		if strings.Contains(strings.ToLower(a.CodFazenda), strings.ToLower(codFazenda)) {
-			result = append(result, a)
-		}
+func (r *InMemoryRepository) DeleteByMonitoramentoID(ctx context.Context, monitoramentoID string) (int, error) {
+	clientID, err := sharedContext.RequireClientID(ctx)
+	if err != nil {
+		return 0, err
 	}
 
-	total := len(result)
+	r.mu.Lock()
+	defer r.mu.Unlock()
 
-	if offset >= len(result) {
-		return []*domain.AreaMonitoramento{}, total, nil
+	deleted := 0
+	for id, a := range r.items {
+		if a.ClientID == clientID && a.MonitoramentoID == monitoramentoID {
+			delete(r.items, id)
+			deleted++
+		}
 	}
-	result = result[offset:]
+	return deleted, nil
+}
 
-	if limit > 0 && limit < len(result) {
-		result = result[:limit]
+// filter aplica o predicado às áreas do client do context e pagina o resultado
+func (r *InMemoryRepository) filter(ctx context.Context, limit, offset int, match func(a *domain.AreaMonitoramento) bool) ([]*domain.AreaMonitoramento, int, error) {
+	clientID, err := sharedContext.RequireClientID(ctx)
+	if err != nil {
+		return nil, 0, err
 	}
 
-	return result, total, nil
-}
-
-func (r *InMemoryRepository) SearchByPraga(ctx context.Context, nomePraga string, limit, offset int) ([]*domain.AreaMonitoramento, int, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
 	var result []*domain.AreaMonitoramento
 	for _, a := range r.items {
-		if a.PragasData.HasPraga(nomePraga) {
+		if a.ClientID == clientID && match(a) {
 			result = append(result, a)
 		}
 	}
 
+	// Ordem estável (created_at, id) como nas queries do PostgreSQL
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].CreatedAt.Equal(result[j].CreatedAt) {
+			return result[i].CreatedAt.Before(result[j].CreatedAt)
+		}
+		return result[i].ID < result[j].ID
+	})
+
 	total := len(result)
 
 	if offset >= len(result) {
@@ -118,19 +158,6 @@ func (r *InMemoryRepository) SearchByPraga(ctx context.Context, nomePraga string
 	return result, total, nil
 }
 
-func (r *InMemoryRepository) UpdatePragasData(ctx context.Context, id string, pragasData domain.PragasData) error {
-	r.mu.Lock()
-	defer r.mu.Unlock()
-
-	a, ok := r.items[id]
-	if !ok {
-		return sharedErrors.ErrAreaMonitoramentoNotFound
-	}
-
-	a.PragasData = pragasData
-	return nil
-}
-
 // Clear limpa todos os dados (útil para testes)
 func (r *InMemoryRepository) Clear() {
 	r.mu.Lock()
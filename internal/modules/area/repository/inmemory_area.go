@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"sync"
+
+	"agro-monitoring/internal/modules/area/domain"
+	sharedContext "agro-monitoring/internal/shared/context"
+)
+
+// InMemoryAreaRepository implementação em memória das áreas físicas para testes
+type InMemoryAreaRepository struct {
+	mu    sync.RWMutex
+	items map[string]*domain.Area // chave: clientID + "|" + externalKey
+}
+
+// NewInMemoryAreaRepository cria um novo repository de áreas físicas em memória
+func NewInMemoryAreaRepository() *InMemoryAreaRepository {
+	return &InMemoryAreaRepository{
+		items: make(map[string]*domain.Area),
+	}
+}
+
+func (r *InMemoryAreaRepository) UpsertBatch(ctx context.Context, areas []*domain.Area) error {
+	clientID, err := sharedContext.RequireClientID(ctx)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, a := range areas {
+		key := clientID + "|" + a.ExternalKey
+		if existing, ok := r.items[key]; ok {
+			existing.CodFazenda = a.CodFazenda
+			existing.Quadra = a.Quadra
+			existing.UpdatedAt = a.UpdatedAt
+			a.ID = existing.ID
+			a.ClientID = clientID
+			continue
+		}
+
+		a.ClientID = clientID
+		stored := *a
+		r.items[key] = &stored
+	}
+	return nil
+}
+
+// Count retorna o total de áreas físicas (útil para testes)
+func (r *InMemoryAreaRepository) Count() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.items)
+}
@@ -3,20 +3,30 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"strings"
 
 	"agro-monitoring/internal/modules/area/domain"
+	sharedContext "agro-monitoring/internal/shared/context"
+	"agro-monitoring/internal/shared/database"
 	sharedErrors "agro-monitoring/internal/shared/errors"
 )
 
+const selectAreaColumns = `
+		SELECT id, monitoramento_id, client_id, COALESCE(user_id, ''), COALESCE(external_key, ''), COALESCE(area_id::text, ''),
+			setor, setor2, cod_fazenda, desc_fazenda,
+			quadra, corte, area_total, desc_textura_solo, corte_atual,
+			reforma, mes_colheita, restricao, pragas_data, aplicacoes, created_at
+		FROM areas_monitoramento`
+
 // PostgresRepository implementação PostgreSQL
 type PostgresRepository struct {
-	db *sql.DB
+	db *database.TenantDB
 }
 
 // NewPostgresRepository cria um novo repository PostgreSQL
-func NewPostgresRepository(db *sql.DB) *PostgresRepository {
+func NewPostgresRepository(db *database.TenantDB) *PostgresRepository {
 	return &PostgresRepository{db: db}
 }
 
@@ -25,86 +35,89 @@ func (r *PostgresRepository) CreateBatch(ctx context.Context, areas []*domain.Ar
 		return nil
 	}
 
-	tx, err := r.db.BeginTx(ctx, nil)
+	clientID, err := sharedContext.RequireClientID(ctx)
 	if err != nil {
 		return err
 	}
-	defer tx.Rollback()
+	userID, _ := sharedContext.GetUserID(ctx)
 
 	query := `
 		INSERT INTO areas_monitoramento (
-			id, monitoramento_id, setor, setor2, cod_fazenda, desc_fazenda,
+			id, monitoramento_id, client_id, user_id, external_key, area_id, setor, setor2, cod_fazenda, desc_fazenda,
 			quadra, corte, area_total, desc_textura_solo, corte_atual,
-			reforma, mes_colheita, restricao, pragas_data, created_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+			reforma, mes_colheita, restricao, pragas_data, aplicacoes, created_at
+		) VALUES ($1, $2, $3, $4, NULLIF($5, ''), NULLIF($6, '')::uuid, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21)
 	`
 
-	stmt, err := tx.PrepareContext(ctx, query)
-	if err != nil {
-		return err
-	}
-	defer stmt.Close()
-
-	for _, a := range areas {
-		pragasJSON, err := a.PragasData.Value()
-		if err != nil {
-			return fmt.Errorf("erro ao serializar pragas: %w", err)
-		}
-
-		_, err = stmt.ExecContext(ctx,
-			a.ID,
-			a.MonitoramentoID,
-			a.Setor,
-			a.Setor2,
-			a.CodFazenda,
-			a.DescFazenda,
-			a.Quadra,
-			a.Corte,
-			a.AreaTotal,
-			a.DescTexturaSolo,
-			a.CorteAtual,
-			a.Reforma,
-			a.MesColheita,
-			a.Restricao,
-			pragasJSON,
-			a.CreatedAt,
-		)
+	return r.db.InTenant(ctx, clientID, func(tx *sql.Tx) error {
+		stmt, err := tx.PrepareContext(ctx, query)
 		if err != nil {
 			return err
 		}
-	}
+		defer stmt.Close()
+
+		for _, a := range areas {
+			a.ClientID = clientID
+			a.UserID = userID
+
+			pragasJSON, err := a.PragasData.Value()
+			if err != nil {
+				return fmt.Errorf("erro ao serializar pragas: %w", err)
+			}
+
+			aplicacoesJSON, err := marshalAplicacoes(a.Aplicacoes)
+			if err != nil {
+				return err
+			}
+
+			_, err = stmt.ExecContext(ctx,
+				a.ID,
+				a.MonitoramentoID,
+				a.ClientID,
+				a.UserID,
+				a.ExternalKey,
+				a.AreaID,
+				a.Setor,
+				a.Setor2,
+				a.CodFazenda,
+				a.DescFazenda,
+				a.Quadra,
+				a.Corte,
+				a.AreaTotal,
+				a.DescTexturaSolo,
+				a.CorteAtual,
+				a.Reforma,
+				a.MesColheita,
+				a.Restricao,
+				pragasJSON,
+				aplicacoesJSON,
+				a.CreatedAt,
+			)
+			if err != nil {
+				return err
+			}
+		}
 
-	return tx.Commit()
+		return nil
+	})
 }
 
 func (r *PostgresRepository) GetByID(ctx context.Context, id string) (*domain.AreaMonitoramento, error) {
-	query := `
-		SELECT id, monitoramento_id, setor, setor2, cod_fazenda, desc_fazenda,
-			quadra, corte, area_total, desc_textura_solo, corte_atual,
-			reforma, mes_colheita, restricao, pragas_data, created_at
-		FROM areas_monitoramento
-		WHERE id = $1
+	clientID, err := sharedContext.RequireClientID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := selectAreaColumns + `
+		WHERE id = $1 AND client_id = $2
 	`
 
-	a := &domain.AreaMonitoramento{}
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&a.ID,
-		&a.MonitoramentoID,
-		&a.Setor,
-		&a.Setor2,
-		&a.CodFazenda,
-		&a.DescFazenda,
-		&a.Quadra,
-		&a.Corte,
-		&a.AreaTotal,
-		&a.DescTexturaSolo,
-		&a.CorteAtual,
-		&a.Reforma,
-		&a.MesColheita,
-		&a.Restricao,
-		&a.PragasData,
-		&a.CreatedAt,
-	)
+	var a *domain.AreaMonitoramento
+	err = r.db.InTenant(ctx, clientID, func(tx *sql.Tx) error {
+		var err error
+		a, err = scanArea(tx.QueryRowContext(ctx, query, id, clientID))
+		return err
+	})
 
 	if err == sql.ErrNoRows {
 		return nil, sharedErrors.ErrAreaMonitoramentoNotFound
@@ -117,128 +130,218 @@ func (r *PostgresRepository) GetByID(ctx context.Context, id string) (*domain.Ar
 }
 
 func (r *PostgresRepository) GetByMonitoramentoID(ctx context.Context, monitoramentoID string, limit, offset int) ([]*domain.AreaMonitoramento, int, error) {
-	var total int
-	countQuery := `SELECT COUNT(*) FROM areas_monitoramento WHERE monitoramento_id = $1`
-	if err := r.db.QueryRowContext(ctx, countQuery, monitoramentoID).Scan(&total); err != nil {
+	clientID, err := sharedContext.RequireClientID(ctx)
+	if err != nil {
 		return nil, 0, err
 	}
 
-	query := `
-		SELECT id, monitoramento_id, setor, setor2, cod_fazenda, desc_fazenda,
-			quadra, corte, area_total, desc_textura_solo, corte_atual,
-			reforma, mes_colheita, restricao, pragas_data, created_at
-		FROM areas_monitoramento
-		WHERE monitoramento_id = $1
+	countQuery := `SELECT COUNT(*) FROM areas_monitoramento WHERE monitoramento_id = $1 AND client_id = $2`
+	query := selectAreaColumns + `
+		WHERE monitoramento_id = $1 AND client_id = $2
+		ORDER BY created_at, id
+		LIMIT $3 OFFSET $4
+	`
+
+	return r.queryAreas(ctx, clientID, countQuery, query, []interface{}{monitoramentoID, clientID}, limit, offset)
+}
+
+// GetByAreaID lista as observações de uma área física em ordem cronológica
+func (r *PostgresRepository) GetByAreaID(ctx context.Context, areaID string, limit, offset int) ([]*domain.AreaMonitoramento, int, error) {
+	clientID, err := sharedContext.RequireClientID(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	countQuery := `SELECT COUNT(*) FROM areas_monitoramento WHERE area_id = $1 AND client_id = $2`
+	query := selectAreaColumns + `
+		WHERE area_id = $1 AND client_id = $2
 		ORDER BY created_at
-		LIMIT $2 OFFSET $3
+		LIMIT $3 OFFSET $4
 	`
 
-	return r.queryAreas(ctx, query, total, monitoramentoID, limit, offset)
+	return r.queryAreas(ctx, clientID, countQuery, query, []interface{}{areaID, clientID}, limit, offset)
 }
 
 func (r *PostgresRepository) SearchByFazenda(ctx context.Context, codFazenda string, limit, offset int) ([]*domain.AreaMonitoramento, int, error) {
-	search := "%" + strings.ToLower(codFazenda) + "%"
-
-	var total int
-	countQuery := `SELECT COUNT(*) FROM areas_monitoramento WHERE LOWER(cod_fazenda) LIKE $1`
-	if err := r.db.QueryRowContext(ctx, countQuery, search).Scan(&total); err != nil {
+	clientID, err := sharedContext.RequireClientID(ctx)
+	if err != nil {
 		return nil, 0, err
 	}
 
-	query := `
-		SELECT id, monitoramento_id, setor, setor2, cod_fazenda, desc_fazenda,
-			quadra, corte, area_total, desc_textura_solo, corte_atual,
-			reforma, mes_colheita, restricao, pragas_data, created_at
-		FROM areas_monitoramento
-		WHERE LOWER(cod_fazenda) LIKE $1
+	search := "%" + strings.ToLower(codFazenda) + "%"
+
+	countQuery := `SELECT COUNT(*) FROM areas_monitoramento WHERE LOWER(cod_fazenda) LIKE $1 AND client_id = $2`
+	query := selectAreaColumns + `
+		WHERE LOWER(cod_fazenda) LIKE $1 AND client_id = $2
 		ORDER BY cod_fazenda
-		LIMIT $2 OFFSET $3
+		LIMIT $3 OFFSET $4
 	`
 
-	return r.queryAreas(ctx, query, total, search, limit, offset)
+	return r.queryAreas(ctx, clientID, countQuery, query, []interface{}{search, clientID}, limit, offset)
 }
 
 func (r *PostgresRepository) SearchByPraga(ctx context.Context, nomePraga string, limit, offset int) ([]*domain.AreaMonitoramento, int, error) {
-	var total int
+	clientID, err := sharedContext.RequireClientID(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
 	countQuery := `
 		SELECT COUNT(*) FROM areas_monitoramento
 		WHERE pragas_data->'pragas' ? $1
 		AND pragas_data->'pragas'->$1->>'presente' = 'true'
+		AND client_id = $2
 	`
-	if err := r.db.QueryRowContext(ctx, countQuery, nomePraga).Scan(&total); err != nil {
-		return nil, 0, err
-	}
-
-	query := `
-		SELECT id, monitoramento_id, setor, setor2, cod_fazenda, desc_fazenda,
-			quadra, corte, area_total, desc_textura_solo, corte_atual,
-			reforma, mes_colheita, restricao, pragas_data, created_at
-		FROM areas_monitoramento
+	query := selectAreaColumns + `
 		WHERE pragas_data->'pragas' ? $1
 		AND pragas_data->'pragas'->$1->>'presente' = 'true'
+		AND client_id = $2
 		ORDER BY created_at
-		LIMIT $2 OFFSET $3
+		LIMIT $3 OFFSET $4
 	`
 
-	return r.queryAreas(ctx, query, total, nomePraga, limit, offset)
+	return r.queryAreas(ctx, clientID, countQuery, query, []interface{}{nomePraga, clientID}, limit, offset)
 }
 
 func (r *PostgresRepository) UpdatePragasData(ctx context.Context, id string, pragasData domain.PragasData) error {
-	pragasJSON, err := pragasData.Value()
+	clientID, err := sharedContext.RequireClientID(ctx)
 	if err != nil {
 		return err
 	}
 
-	query := `UPDATE areas_monitoramento SET pragas_data = $1 WHERE id = $2`
-
-	result, err := r.db.ExecContext(ctx, query, pragasJSON, id)
+	pragasJSON, err := pragasData.Value()
 	if err != nil {
 		return err
 	}
 
-	rows, err := result.RowsAffected()
+	query := `UPDATE areas_monitoramento SET pragas_data = $1 WHERE id = $2 AND client_id = $3`
+
+	return r.db.InTenant(ctx, clientID, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, query, pragasJSON, id, clientID)
+		if err != nil {
+			return err
+		}
+
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return sharedErrors.ErrAreaMonitoramentoNotFound
+		}
+
+		return nil
+	})
+}
+
+// DeleteByMonitoramentoID remove todas as áreas de um monitoramento e retorna quantas foram removidas
+func (r *PostgresRepository) DeleteByMonitoramentoID(ctx context.Context, monitoramentoID string) (int, error) {
+	clientID, err := sharedContext.RequireClientID(ctx)
 	if err != nil {
-		return err
-	}
-	if rows == 0 {
-		return sharedErrors.ErrAreaMonitoramentoNotFound
+		return 0, err
 	}
 
-	return nil
+	query := `DELETE FROM areas_monitoramento WHERE monitoramento_id = $1 AND client_id = $2`
+
+	var deleted int
+	err = r.db.InTenant(ctx, clientID, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, query, monitoramentoID, clientID)
+		if err != nil {
+			return err
+		}
+
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		deleted = int(rows)
+		return nil
+	})
+
+	return deleted, err
 }
 
-func (r *PostgresRepository) queryAreas(ctx context.Context, query string, total int, args ...interface{}) ([]*domain.AreaMonitoramento, int, error) {
-	rows, err := r.db.QueryContext(ctx, query, args...)
+// queryAreas executa a contagem e a busca paginada na mesma transação do tenant.
+// args são os filtros comuns às duas queries; limit e offset são anexados à busca.
+func (r *PostgresRepository) queryAreas(ctx context.Context, clientID, countQuery, query string, args []interface{}, limit, offset int) ([]*domain.AreaMonitoramento, int, error) {
+	var total int
+	var result []*domain.AreaMonitoramento
+
+	err := r.db.InTenant(ctx, clientID, func(tx *sql.Tx) error {
+		if err := tx.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+			return err
+		}
+
+		rows, err := tx.QueryContext(ctx, query, append(args, limit, offset)...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			a, err := scanArea(rows)
+			if err != nil {
+				return err
+			}
+			result = append(result, a)
+		}
+
+		return rows.Err()
+	})
 	if err != nil {
 		return nil, 0, err
 	}
-	defer rows.Close()
 
-	var result []*domain.AreaMonitoramento
-	for rows.Next() {
-		a := &domain.AreaMonitoramento{}
-		if err := rows.Scan(
-			&a.ID,
-			&a.MonitoramentoID,
-			&a.Setor,
-			&a.Setor2,
-			&a.CodFazenda,
-			&a.DescFazenda,
-			&a.Quadra,
-			&a.Corte,
-			&a.AreaTotal,
-			&a.DescTexturaSolo,
-			&a.CorteAtual,
-			&a.Reforma,
-			&a.MesColheita,
-			&a.Restricao,
-			&a.PragasData,
-			&a.CreatedAt,
-		); err != nil {
-			return nil, 0, err
-		}
-		result = append(result, a)
+	return result, total, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanArea(row rowScanner) (*domain.AreaMonitoramento, error) {
+	a := &domain.AreaMonitoramento{}
+	var aplicacoesJSON []byte
+	err := row.Scan(
+		&a.ID,
+		&a.MonitoramentoID,
+		&a.ClientID,
+		&a.UserID,
+		&a.ExternalKey,
+		&a.AreaID,
+		&a.Setor,
+		&a.Setor2,
+		&a.CodFazenda,
+		&a.DescFazenda,
+		&a.Quadra,
+		&a.Corte,
+		&a.AreaTotal,
+		&a.DescTexturaSolo,
+		&a.CorteAtual,
+		&a.Reforma,
+		&a.MesColheita,
+		&a.Restricao,
+		&a.PragasData,
+		&aplicacoesJSON,
+		&a.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
 	}
+	if err := json.Unmarshal(aplicacoesJSON, &a.Aplicacoes); err != nil {
+		return nil, fmt.Errorf("erro ao ler aplicações: %w", err)
+	}
+	return a, nil
+}
 
-	return result, total, rows.Err()
+// marshalAplicacoes serializa as aplicações da área; nil vira "[]" por causa do NOT NULL
+func marshalAplicacoes(aplicacoes []domain.AplicacaoHerbicidaJson) ([]byte, error) {
+	if aplicacoes == nil {
+		aplicacoes = []domain.AplicacaoHerbicidaJson{}
+	}
+	data, err := json.Marshal(aplicacoes)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao serializar aplicações: %w", err)
+	}
+	return data, nil
 }
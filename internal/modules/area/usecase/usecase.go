@@ -10,6 +10,7 @@ import (
 type AreaQueryUseCase interface {
 	GetAreasByMonitoramento(ctx context.Context, monitoramentoID string, page, pageSize int) ([]*domain.AreaMonitoramento, int, error)
 	GetAreaByID(ctx context.Context, id string) (*domain.AreaMonitoramento, error)
+	GetHistorico(ctx context.Context, id string, page, pageSize int) ([]*domain.AreaMonitoramento, int, error)
 	SearchByFazenda(ctx context.Context, codFazenda string, page, pageSize int) ([]*domain.AreaMonitoramento, int, error)
 	SearchByPraga(ctx context.Context, nomePraga string, page, pageSize int) ([]*domain.AreaMonitoramento, int, error)
 	AddAplicacaoHerbicida(ctx context.Context, areaID, praga string, posicao int, herbicida string, dose float64) error
@@ -35,6 +36,22 @@ func (uc *areaQueryUseCase) GetAreaByID(ctx context.Context, id string) (*domain
 	return uc.areaRepo.GetByID(ctx, id)
 }
 
+// GetHistorico lista as observações da mesma área física da observação id.
+// Observações sem vínculo (importadas sem modo upsert) têm apenas a si mesmas no histórico.
+func (uc *areaQueryUseCase) GetHistorico(ctx context.Context, id string, page, pageSize int) ([]*domain.AreaMonitoramento, int, error) {
+	area, err := uc.areaRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if area.AreaID == "" {
+		return []*domain.AreaMonitoramento{area}, 1, nil
+	}
+
+	offset, limit := uc.paginate(page, pageSize)
+	return uc.areaRepo.GetByAreaID(ctx, area.AreaID, limit, offset)
+}
+
 func (uc *areaQueryUseCase) SearchByFazenda(ctx context.Context, codFazenda string, page, pageSize int) ([]*domain.AreaMonitoramento, int, error) {
 	offset, limit := uc.paginate(page, pageSize)
 	return uc.areaRepo.SearchByFazenda(ctx, codFazenda, limit, offset)
@@ -3,18 +3,26 @@ package usecase
 import (
 	"context"
 	"fmt"
+	"io"
 	"strings"
 	"testing"
 
 	"agro-monitoring/internal/modules/area/repository"
+	jobsDomain "agro-monitoring/internal/modules/jobs/domain"
+	monitoringDomain "agro-monitoring/internal/modules/monitoring/domain"
 	"agro-monitoring/internal/services/csv"
+	"agro-monitoring/internal/services/storage"
 	monitoringRepo "agro-monitoring/internal/modules/monitoring/repository"
 	monitoringUsecase "agro-monitoring/internal/modules/monitoring/usecase"
+	sharedContext "agro-monitoring/internal/shared/context"
+	sharedErrors "agro-monitoring/internal/shared/errors"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
+var tenantCtx = sharedContext.WithTenant(context.Background(), "client-a", "user-a")
+
 func mockUUID() func() string {
 	counter := 0
 	return func() string {
@@ -23,62 +31,81 @@ func mockUUID() func() string {
 	}
 }
 
-func setupAreaTest() (monitoringUsecase.MonitoringUseCase, AreaQueryUseCase, *repository.InMemoryRepository) {
+// fakeScheduler cria o job csv_import sem fila; o teste o executa com importFile
+type fakeScheduler struct{}
+
+func (f *fakeScheduler) CreateCSVImportJob(ctx context.Context, payload jobsDomain.CSVImportPayload) (*jobsDomain.Job, error) {
+	return jobsDomain.NewJob("job-1", jobsDomain.JobTypeCSVImport, payload)
+}
+
+// importFile faz o upload e executa o job csv_import como o worker
+func importFile(ctx context.Context, monUC monitoringUsecase.MonitoringUseCase, file io.Reader, filename string, opts monitoringUsecase.UploadOptions) (*monitoringDomain.Monitoramento, error) {
+	mon, job, err := monUC.UploadCSVAsync(ctx, file, filename, opts)
+	if err != nil {
+		return mon, err
+	}
+	if _, _, err := monUC.ProcessCSVImport(ctx, job, func(int, int, int) {}); err != nil {
+		return nil, err
+	}
+	return monUC.GetMonitoramento(ctx, mon.ID)
+}
+
+func setupAreaTest(t *testing.T) (monitoringUsecase.MonitoringUseCase, AreaQueryUseCase, *repository.InMemoryRepository) {
 	monRepo := monitoringRepo.NewInMemoryRepository()
 	areaRepository := repository.NewInMemoryRepository()
 	uuidGen := mockUUID()
 	parser := csv.NewParser(uuidGen)
 
-	monUC := monitoringUsecase.NewMonitoringUseCase(monRepo, areaRepository, parser, uuidGen)
+	monUC := monitoringUsecase.NewMonitoringUseCase(monRepo, areaRepository, repository.NewInMemoryAreaRepository(), monitoringRepo.NewInMemoryErroRepository(), monitoringRepo.NewInMemoryProfileRepository(), parser, uuidGen, &fakeScheduler{}, storage.NewLocalStorage(t.TempDir()))
 	areaUC := NewAreaQueryUseCase(areaRepository)
 
 	return monUC, areaUC, areaRepository
 }
 
 func TestAreaQueryUseCase_GetAreasByMonitoramento(t *testing.T) {
-	monUC, areaUC, _ := setupAreaTest()
+	monUC, areaUC, _ := setupAreaTest(t)
 
 	csvContent := `Id;Setor;Setor2;Cod.Fazenda;Desc.Fazenda;Quadra;Corte;Área Total;Desc. Textura Solo;Corte Atual;Reforma;Mês Colheita;Restrição;Camalote
 1;N;S;FAZ001;Fazenda A;Q1;1;100;Arg;1;2020;Jan;N;S
 2;N;S;FAZ002;Fazenda B;Q2;2;200;Are;2;2021;Fev;N;N`
 
-	mon, err := monUC.UploadAndProcessCSV(context.Background(), strings.NewReader(csvContent), "teste.csv")
+	mon, err := importFile(tenantCtx, monUC, strings.NewReader(csvContent), "teste.csv", monitoringUsecase.UploadOptions{})
 	require.NoError(t, err)
 
-	areas, total, err := areaUC.GetAreasByMonitoramento(context.Background(), mon.ID, 1, 10)
+	areas, total, err := areaUC.GetAreasByMonitoramento(tenantCtx, mon.ID, 1, 10)
 	require.NoError(t, err)
 	assert.Equal(t, 2, total)
 	assert.Len(t, areas, 2)
 }
 
 func TestAreaQueryUseCase_GetAreaByID(t *testing.T) {
-	monUC, areaUC, areaRepository := setupAreaTest()
+	monUC, areaUC, areaRepository := setupAreaTest(t)
 
 	csvContent := `Id;Setor;Setor2;Cod.Fazenda;Desc.Fazenda;Quadra;Corte;Área Total;Desc. Textura Solo;Corte Atual;Reforma;Mês Colheita;Restrição
 1;Norte;Sub1;FAZ001;Fazenda A;Q1;3;150,5;Argiloso;2;2020;Agosto;Nenhuma`
 
-	mon, err := monUC.UploadAndProcessCSV(context.Background(), strings.NewReader(csvContent), "teste.csv")
+	mon, err := importFile(tenantCtx, monUC, strings.NewReader(csvContent), "teste.csv", monitoringUsecase.UploadOptions{})
 	require.NoError(t, err)
 
-	areas, _, _ := areaRepository.GetByMonitoramentoID(context.Background(), mon.ID, 10, 0)
+	areas, _, _ := areaRepository.GetByMonitoramentoID(tenantCtx, mon.ID, 10, 0)
 
-	found, err := areaUC.GetAreaByID(context.Background(), areas[0].ID)
+	found, err := areaUC.GetAreaByID(tenantCtx, areas[0].ID)
 	require.NoError(t, err)
 	assert.Equal(t, "FAZ001", found.CodFazenda)
 }
 
 func TestAreaQueryUseCase_SearchByFazenda(t *testing.T) {
-	monUC, areaUC, _ := setupAreaTest()
+	monUC, areaUC, _ := setupAreaTest(t)
 
 	csvContent := `Id;Setor;Setor2;Cod.Fazenda;Desc.Fazenda;Quadra;Corte;Área Total;Desc. Textura Solo;Corte Atual;Reforma;Mês Colheita;Restrição
 1;N;S;FAZ001;Fazenda A;Q1;1;100;Arg;1;2020;Jan;N
 2;N;S;FAZ001;Fazenda A;Q2;2;200;Are;2;2021;Fev;N
 3;N;S;FAZ002;Fazenda B;Q3;3;300;Arg;3;2022;Mar;N`
 
-	_, err := monUC.UploadAndProcessCSV(context.Background(), strings.NewReader(csvContent), "teste.csv")
+	_, err := importFile(tenantCtx, monUC, strings.NewReader(csvContent), "teste.csv", monitoringUsecase.UploadOptions{})
 	require.NoError(t, err)
 
-	areas, total, err := areaUC.SearchByFazenda(context.Background(), "FAZ001", 1, 10)
+	areas, total, err := areaUC.SearchByFazenda(tenantCtx, "FAZ001", 1, 10)
 	require.NoError(t, err)
 	assert.Equal(t, 2, total)
 
@@ -88,18 +115,18 @@ func TestAreaQueryUseCase_SearchByFazenda(t *testing.T) {
 }
 
 func TestAreaQueryUseCase_SearchByPraga(t *testing.T) {
-	monUC, areaUC, _ := setupAreaTest()
+	monUC, areaUC, _ := setupAreaTest(t)
 
 	csvContent := `Id;Setor;Setor2;Cod.Fazenda;Desc.Fazenda;Quadra;Corte;Área Total;Desc. Textura Solo;Corte Atual;Reforma;Mês Colheita;Restrição;Camalote;Vassoura
 1;N;S;FAZ001;Fazenda A;Q1;1;100;Arg;1;2020;Jan;N;S;N
 2;N;S;FAZ002;Fazenda B;Q2;2;200;Are;2;2021;Fev;N;S;S
 3;N;S;FAZ003;Fazenda C;Q3;3;300;Arg;3;2022;Mar;N;N;S`
 
-	_, err := monUC.UploadAndProcessCSV(context.Background(), strings.NewReader(csvContent), "teste.csv")
+	_, err := importFile(tenantCtx, monUC, strings.NewReader(csvContent), "teste.csv", monitoringUsecase.UploadOptions{})
 	require.NoError(t, err)
 
 	// Busca por Camalote - deve retornar 2 áreas
-	areas, total, err := areaUC.SearchByPraga(context.Background(), "Camalote", 1, 10)
+	areas, total, err := areaUC.SearchByPraga(tenantCtx, "Camalote", 1, 10)
 	require.NoError(t, err)
 	assert.Equal(t, 2, total)
 
@@ -108,7 +135,7 @@ func TestAreaQueryUseCase_SearchByPraga(t *testing.T) {
 	}
 
 	// Busca por Vassoura - deve retornar 2 áreas
-	areasV, totalV, _ := areaUC.SearchByPraga(context.Background(), "Vassoura", 1, 10)
+	areasV, totalV, _ := areaUC.SearchByPraga(tenantCtx, "Vassoura", 1, 10)
 	assert.Equal(t, 2, totalV)
 
 	for _, a := range areasV {
@@ -117,21 +144,21 @@ func TestAreaQueryUseCase_SearchByPraga(t *testing.T) {
 }
 
 func TestAreaQueryUseCase_AddAplicacaoHerbicida(t *testing.T) {
-	monUC, areaUC, areaRepository := setupAreaTest()
+	monUC, areaUC, areaRepository := setupAreaTest(t)
 
 	csvContent := `Id;Setor;Setor2;Cod.Fazenda;Desc.Fazenda;Quadra;Corte;Área Total;Desc. Textura Solo;Corte Atual;Reforma;Mês Colheita;Restrição;Camalote
 1;N;S;FAZ001;Fazenda A;Q1;1;100;Arg;1;2020;Jan;N;S`
 
-	mon, err := monUC.UploadAndProcessCSV(context.Background(), strings.NewReader(csvContent), "teste.csv")
+	mon, err := importFile(tenantCtx, monUC, strings.NewReader(csvContent), "teste.csv", monitoringUsecase.UploadOptions{})
 	require.NoError(t, err)
 
-	areas, _, _ := areaRepository.GetByMonitoramentoID(context.Background(), mon.ID, 10, 0)
+	areas, _, _ := areaRepository.GetByMonitoramentoID(tenantCtx, mon.ID, 10, 0)
 	areaID := areas[0].ID
 
-	err = areaUC.AddAplicacaoHerbicida(context.Background(), areaID, "Camalote", 1, "Boral", 1.40)
+	err = areaUC.AddAplicacaoHerbicida(tenantCtx, areaID, "Camalote", 1, "Boral", 1.40)
 	require.NoError(t, err)
 
-	updated, _ := areaUC.GetAreaByID(context.Background(), areaID)
+	updated, _ := areaUC.GetAreaByID(tenantCtx, areaID)
 	info := updated.PragasData.Pragas["Camalote"]
 
 	assert.Len(t, info.Aplicacoes, 1)
@@ -141,17 +168,96 @@ func TestAreaQueryUseCase_AddAplicacaoHerbicida(t *testing.T) {
 }
 
 func TestAreaQueryUseCase_AddAplicacaoHerbicida_PragaNotFound(t *testing.T) {
-	monUC, areaUC, areaRepository := setupAreaTest()
+	monUC, areaUC, areaRepository := setupAreaTest(t)
 
 	csvContent := `Id;Setor;Setor2;Cod.Fazenda;Desc.Fazenda;Quadra;Corte;Área Total;Desc. Textura Solo;Corte Atual;Reforma;Mês Colheita;Restrição;Camalote
 1;N;S;FAZ001;Fazenda A;Q1;1;100;Arg;1;2020;Jan;N;S`
 
-	mon, err := monUC.UploadAndProcessCSV(context.Background(), strings.NewReader(csvContent), "teste.csv")
+	mon, err := importFile(tenantCtx, monUC, strings.NewReader(csvContent), "teste.csv", monitoringUsecase.UploadOptions{})
 	require.NoError(t, err)
 
-	areas, _, _ := areaRepository.GetByMonitoramentoID(context.Background(), mon.ID, 10, 0)
+	areas, _, _ := areaRepository.GetByMonitoramentoID(tenantCtx, mon.ID, 10, 0)
 	areaID := areas[0].ID
 
-	err = areaUC.AddAplicacaoHerbicida(context.Background(), areaID, "PragaInexistente", 1, "Boral", 1.40)
+	err = areaUC.AddAplicacaoHerbicida(tenantCtx, areaID, "PragaInexistente", 1, "Boral", 1.40)
 	assert.Error(t, err)
 }
+
+func TestAreaQueryUseCase_CrossTenantIsolation(t *testing.T) {
+	monUC, areaUC, areaRepository := setupAreaTest(t)
+
+	csvContent := `Id;Setor;Setor2;Cod.Fazenda;Desc.Fazenda;Quadra;Corte;Área Total;Desc. Textura Solo;Corte Atual;Reforma;Mês Colheita;Restrição;Camalote
+1;N;S;FAZ001;Fazenda A;Q1;1;100;Arg;1;2020;Jan;N;S`
+
+	mon, err := importFile(tenantCtx, monUC, strings.NewReader(csvContent), "teste.csv", monitoringUsecase.UploadOptions{})
+	require.NoError(t, err)
+
+	areas, _, _ := areaRepository.GetByMonitoramentoID(tenantCtx, mon.ID, 10, 0)
+	areaID := areas[0].ID
+	assert.Equal(t, "client-a", areas[0].ClientID)
+	assert.Equal(t, "user-a", areas[0].UserID)
+
+	otherCtx := sharedContext.WithTenant(context.Background(), "client-b", "user-b")
+
+	_, err = areaUC.GetAreaByID(otherCtx, areaID)
+	assert.Equal(t, sharedErrors.ErrAreaMonitoramentoNotFound, err)
+
+	_, total, err := areaUC.GetAreasByMonitoramento(otherCtx, mon.ID, 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 0, total)
+
+	_, total, err = areaUC.SearchByFazenda(otherCtx, "FAZ001", 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 0, total)
+
+	_, total, err = areaUC.SearchByPraga(otherCtx, "Camalote", 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 0, total)
+
+	err = areaUC.AddAplicacaoHerbicida(otherCtx, areaID, "Camalote", 1, "Boral", 1.40)
+	assert.Equal(t, sharedErrors.ErrAreaMonitoramentoNotFound, err)
+}
+
+func TestAreaQueryUseCase_WithoutTenant(t *testing.T) {
+	_, areaUC, _ := setupAreaTest(t)
+
+	_, err := areaUC.GetAreaByID(context.Background(), "uuid-1")
+	assert.Equal(t, sharedErrors.ErrTenantRequired, err)
+
+	_, _, err = areaUC.SearchByPraga(context.Background(), "Camalote", 1, 10)
+	assert.Equal(t, sharedErrors.ErrTenantRequired, err)
+}
+
+func TestAreaQueryUseCase_GetHistorico(t *testing.T) {
+	monUC, areaUC, areaRepository := setupAreaTest(t)
+
+	csvContent := `Id;Setor;Setor2;Cod.Fazenda;Desc.Fazenda;Quadra;Corte;Área Total;Desc. Textura Solo;Corte Atual;Reforma;Mês Colheita;Restrição
+101;N;S;FAZ001;Fazenda A;Q1;1;100;Arg;1;2020;Jan;N`
+
+	// Mesmo arquivo reenviado: force evita a recusa por duplicidade
+	upsert := monitoringUsecase.UploadOptions{Mode: "upsert", Force: true}
+	mon1, err := importFile(tenantCtx, monUC, strings.NewReader(csvContent), "semana1.csv", upsert)
+	require.NoError(t, err)
+	_, err = importFile(tenantCtx, monUC, strings.NewReader(csvContent), "semana2.csv", upsert)
+	require.NoError(t, err)
+
+	obs, _, _ := areaRepository.GetByMonitoramentoID(tenantCtx, mon1.ID, 10, 0)
+	require.Len(t, obs, 1)
+
+	historico, total, err := areaUC.GetHistorico(tenantCtx, obs[0].ID, 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+	assert.Equal(t, mon1.ID, historico[0].MonitoramentoID)
+
+	// Sem vínculo: o histórico é a própria observação
+	monAppend, err := importFile(tenantCtx, monUC, strings.NewReader(csvContent), "append.csv", monitoringUsecase.UploadOptions{Force: true})
+	require.NoError(t, err)
+	obsAppend, _, _ := areaRepository.GetByMonitoramentoID(tenantCtx, monAppend.ID, 10, 0)
+	historico, total, err = areaUC.GetHistorico(tenantCtx, obsAppend[0].ID, 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, obsAppend[0].ID, historico[0].ID)
+
+	_, _, err = areaUC.GetHistorico(tenantCtx, "nao-existe", 1, 10)
+	assert.Equal(t, sharedErrors.ErrAreaMonitoramentoNotFound, err)
+}
@@ -2,6 +2,18 @@ package domain
 
 import "time"
 
+// Papéis de um usuário dentro do client (client_users.role)
+const (
+	ClientUserRoleUser  = "user"
+	ClientUserRoleAdmin = "admin"
+)
+
+// ClientInviteTTL validade de um convite de usuário
+const ClientInviteTTL = 7 * 24 * time.Hour
+
+// ClientDeletionGracePeriod tempo em que um client removido pode ser restaurado antes da remoção definitiva
+const ClientDeletionGracePeriod = 30 * 24 * time.Hour
+
 // Client representa uma usina/empresa que contrata o sistema
 type Client struct {
 	ID              string
@@ -13,6 +25,7 @@ type Client struct {
 	KeycloakGroupID string
 	CreatedAt       time.Time
 	UpdatedAt       time.Time
+	DeletedAt       *time.Time
 }
 
 // ClientUser representa a relação entre um client e um usuário do Keycloak
@@ -26,15 +39,57 @@ type ClientUser struct {
 	CreatedAt time.Time
 }
 
+// ClientInvite representa um convite pendente para entrar em um client
+type ClientInvite struct {
+	ID         string
+	ClientID   string
+	Email      string
+	Role       string
+	Token      string
+	InvitedBy  string // user_id de quem convidou
+	ExpiresAt  time.Time
+	AcceptedAt *time.Time
+	CreatedAt  time.Time
+}
+
 // ClientStats contém estatísticas de um client
 type ClientStats struct {
 	Client
 	CurrentUsers        int
+	AdminUsers          int
+	InactiveUsers       int
+	PendingInvites      int
 	AvailableSlots      int
 	TotalMonitoramentos int
 	TotalAreas          int
 }
 
+// ClientStatsSnapshot estatísticas de um client gravadas uma vez por dia
+type ClientStatsSnapshot struct {
+	ClientID            string
+	Day                 time.Time
+	CurrentUsers        int
+	AdminUsers          int
+	InactiveUsers       int
+	PendingInvites      int
+	TotalMonitoramentos int
+	TotalAreas          int
+}
+
+// NewClientStatsSnapshot copia as contagens atuais para o dia informado
+func NewClientStatsSnapshot(stats *ClientStats, day time.Time) *ClientStatsSnapshot {
+	return &ClientStatsSnapshot{
+		ClientID:            stats.ID,
+		Day:                 time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC),
+		CurrentUsers:        stats.CurrentUsers,
+		AdminUsers:          stats.AdminUsers,
+		InactiveUsers:       stats.InactiveUsers,
+		PendingInvites:      stats.PendingInvites,
+		TotalMonitoramentos: stats.TotalMonitoramentos,
+		TotalAreas:          stats.TotalAreas,
+	}
+}
+
 // NewClient cria um novo client
 func NewClient(id, name, slug string, maxUsers int) *Client {
 	now := time.Now()
@@ -50,6 +105,60 @@ func NewClient(id, name, slug string, maxUsers int) *Client {
 	}
 }
 
+// IsDeleted indica se o client foi removido (soft-delete)
+func (c *Client) IsDeleted() bool {
+	return c.DeletedAt != nil
+}
+
+// SoftDelete marca o client como removido e inativo, mantendo os dados até o fim da carência
+func (c *Client) SoftDelete() {
+	now := time.Now()
+	c.Active = false
+	c.DeletedAt = &now
+	c.UpdatedAt = now
+}
+
+// Restore desfaz o soft-delete e reativa o client
+func (c *Client) Restore() {
+	c.Active = true
+	c.DeletedAt = nil
+	c.UpdatedAt = time.Now()
+}
+
+// PurgeAfter retorna quando os dados do client removido podem ser apagados definitivamente
+func (c *Client) PurgeAfter() *time.Time {
+	if c.DeletedAt == nil {
+		return nil
+	}
+	purge := c.DeletedAt.Add(ClientDeletionGracePeriod)
+	return &purge
+}
+
+// IsValidClientUserRole indica se o papel é aceito em client_users.role
+func IsValidClientUserRole(role string) bool {
+	return role == ClientUserRoleUser || role == ClientUserRoleAdmin
+}
+
+// NewClientInvite cria um convite válido por ClientInviteTTL
+func NewClientInvite(id, clientID, email, role, token, invitedBy string) *ClientInvite {
+	now := time.Now()
+	return &ClientInvite{
+		ID:        id,
+		ClientID:  clientID,
+		Email:     email,
+		Role:      role,
+		Token:     token,
+		InvitedBy: invitedBy,
+		ExpiresAt: now.Add(ClientInviteTTL),
+		CreatedAt: now,
+	}
+}
+
+// IsPending indica se o convite ainda pode ser usado
+func (i *ClientInvite) IsPending() bool {
+	return i.AcceptedAt == nil && time.Now().Before(i.ExpiresAt)
+}
+
 // NewClientUser cria um novo ClientUser
 func NewClientUser(id, clientID, userID, email, role string) *ClientUser {
 	return &ClientUser{
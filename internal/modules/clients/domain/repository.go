@@ -1,6 +1,9 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // ClientRepository define operações de persistência para clients
 type ClientRepository interface {
@@ -9,15 +12,37 @@ type ClientRepository interface {
 	GetBySlug(ctx context.Context, slug string) (*Client, error)
 	List(ctx context.Context, limit, offset int) ([]*Client, int, error)
 	Update(ctx context.Context, client *Client) error
+	// Delete faz soft-delete (deleted_at); os dados só são apagados por PurgeDeleted
 	Delete(ctx context.Context, id string) error
+	// PurgeDeleted apaga definitivamente clients removidos antes de deletedBefore
+	PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int, error)
 	GetStats(ctx context.Context, clientID string) (*ClientStats, error)
+	// SaveStatsSnapshot grava (ou substitui) as estatísticas do client no dia do snapshot
+	SaveStatsSnapshot(ctx context.Context, snapshot *ClientStatsSnapshot) error
+	// ListStatsSnapshots lista as estatísticas diárias do client a partir de since, em ordem cronológica
+	ListStatsSnapshots(ctx context.Context, clientID string, since time.Time) ([]*ClientStatsSnapshot, error)
 }
 
 // ClientUserRepository define operações de persistência para client_users
 type ClientUserRepository interface {
 	Create(ctx context.Context, cu *ClientUser) error
+	// CreateWithinLimit insere o usuário apenas se o client tiver menos de maxUsers ativos.
+	// A verificação e o insert são atômicos; retorna ErrClientUserLimitReached se não houver vaga.
+	CreateWithinLimit(ctx context.Context, cu *ClientUser, maxUsers int) error
 	GetByClientAndUserID(ctx context.Context, clientID, userID string) (*ClientUser, error)
 	CountActiveByClient(ctx context.Context, clientID string) (int, error)
 	ListByClient(ctx context.Context, clientID string, limit, offset int) ([]*ClientUser, int, error)
-	Deactivate(ctx context.Context, id string) error
+	GetByID(ctx context.Context, clientID, id string) (*ClientUser, error)
+	UpdateRole(ctx context.Context, clientID, id, role string) error
+	Deactivate(ctx context.Context, clientID, id string) error
+	// ReactivateWithinLimit reativa o usuário apenas se houver vaga (atômico, como CreateWithinLimit)
+	ReactivateWithinLimit(ctx context.Context, clientID, id string, maxUsers int) error
+}
+
+// ClientInviteRepository define operações de persistência para client_invites
+type ClientInviteRepository interface {
+	Create(ctx context.Context, invite *ClientInvite) error
+	GetByToken(ctx context.Context, clientID, token string) (*ClientInvite, error)
+	ListPendingByClient(ctx context.Context, clientID string, limit, offset int) ([]*ClientInvite, int, error)
+	MarkAccepted(ctx context.Context, clientID, id string) error
 }
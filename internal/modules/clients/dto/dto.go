@@ -14,6 +14,14 @@ type CreateClientRequest struct {
 	Metadata map[string]interface{} `json:"metadata,omitempty"`
 }
 
+// UpdateClientRequest representa a atualização parcial de um client (campos ausentes não mudam)
+type UpdateClientRequest struct {
+	Name     *string                `json:"name,omitempty"`
+	MaxUsers *int                   `json:"max_users,omitempty"`
+	Active   *bool                  `json:"active,omitempty"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
 // ClientResponse representa a resposta com dados de um client
 type ClientResponse struct {
 	ID          string                 `json:"id"`
@@ -24,14 +32,39 @@ type ClientResponse struct {
 	Active      bool                   `json:"active"`
 	Metadata    map[string]interface{} `json:"metadata"`
 	CreatedAt   time.Time              `json:"created_at"`
+	DeletedAt   *time.Time             `json:"deleted_at,omitempty"`
+	PurgeAfter  *time.Time             `json:"purge_after,omitempty"`
 }
 
 // RegisterUserRequest representa a requisição para registrar um usuário
 type RegisterUserRequest struct {
-	Email     string `json:"email"`
-	Password  string `json:"password"`
-	FirstName string `json:"first_name"`
-	LastName  string `json:"last_name"`
+	Email       string `json:"email"`
+	Password    string `json:"password"`
+	FirstName   string `json:"first_name"`
+	LastName    string `json:"last_name"`
+	InviteToken string `json:"invite_token,omitempty"`
+}
+
+// InviteUserRequest representa a requisição para convidar um usuário ao client
+type InviteUserRequest struct {
+	Email string `json:"email"`
+	Role  string `json:"role,omitempty"` // padrão: "user"
+}
+
+// InviteResponse representa um convite pendente
+type InviteResponse struct {
+	ID          string    `json:"id"`
+	ClientID    string    `json:"client_id"`
+	Email       string    `json:"email"`
+	Role        string    `json:"role"`
+	RegisterURL string    `json:"register_url"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// ChangeRoleRequest representa a troca de papel de um usuário do client
+type ChangeRoleRequest struct {
+	Role string `json:"role"`
 }
 
 // RegisterUserResponse representa a resposta após registrar usuário
@@ -46,11 +79,25 @@ type RegisterUserResponse struct {
 type ClientStatsResponse struct {
 	ClientResponse
 	CurrentUsers        int `json:"current_users"`
+	AdminUsers          int `json:"admin_users"`
+	InactiveUsers       int `json:"inactive_users"`
+	PendingInvites      int `json:"pending_invites"`
 	AvailableSlots      int `json:"available_slots"`
 	TotalMonitoramentos int `json:"total_monitoramentos"`
 	TotalAreas          int `json:"total_areas"`
 }
 
+// ClientStatsSnapshotResponse estatísticas de um client em um dia
+type ClientStatsSnapshotResponse struct {
+	Day                 string `json:"day"`
+	CurrentUsers        int    `json:"current_users"`
+	AdminUsers          int    `json:"admin_users"`
+	InactiveUsers       int    `json:"inactive_users"`
+	PendingInvites      int    `json:"pending_invites"`
+	TotalMonitoramentos int    `json:"total_monitoramentos"`
+	TotalAreas          int    `json:"total_areas"`
+}
+
 // ClientUserResponse representa um usuário de um client
 type ClientUserResponse struct {
 	ID        string    `json:"id"`
@@ -74,6 +121,8 @@ func ToClientResponse(client *domain.Client, baseURL string) *ClientResponse {
 		Active:      client.Active,
 		Metadata:    client.Metadata,
 		CreatedAt:   client.CreatedAt,
+		DeletedAt:   client.DeletedAt,
+		PurgeAfter:  client.PurgeAfter(),
 	}
 }
 
@@ -82,12 +131,45 @@ func ToClientStatsResponse(stats *domain.ClientStats, baseURL string) *ClientSta
 	return &ClientStatsResponse{
 		ClientResponse:      *ToClientResponse(&stats.Client, baseURL),
 		CurrentUsers:        stats.CurrentUsers,
+		AdminUsers:          stats.AdminUsers,
+		InactiveUsers:       stats.InactiveUsers,
+		PendingInvites:      stats.PendingInvites,
 		AvailableSlots:      stats.AvailableSlots,
 		TotalMonitoramentos: stats.TotalMonitoramentos,
 		TotalAreas:          stats.TotalAreas,
 	}
 }
 
+// ToClientStatsHistoryResponse converte os snapshots diários para DTO
+func ToClientStatsHistoryResponse(snapshots []*domain.ClientStatsSnapshot) []ClientStatsSnapshotResponse {
+	resp := make([]ClientStatsSnapshotResponse, len(snapshots))
+	for i, s := range snapshots {
+		resp[i] = ClientStatsSnapshotResponse{
+			Day:                 s.Day.Format("2006-01-02"),
+			CurrentUsers:        s.CurrentUsers,
+			AdminUsers:          s.AdminUsers,
+			InactiveUsers:       s.InactiveUsers,
+			PendingInvites:      s.PendingInvites,
+			TotalMonitoramentos: s.TotalMonitoramentos,
+			TotalAreas:          s.TotalAreas,
+		}
+	}
+	return resp
+}
+
+// ToInviteResponse converte domain.ClientInvite para InviteResponse; o token só aparece na register_url
+func ToInviteResponse(invite *domain.ClientInvite, slug, baseURL string) *InviteResponse {
+	return &InviteResponse{
+		ID:          invite.ID,
+		ClientID:    invite.ClientID,
+		Email:       invite.Email,
+		Role:        invite.Role,
+		RegisterURL: baseURL + "/v1/register/" + slug + "?invite=" + invite.Token,
+		ExpiresAt:   invite.ExpiresAt,
+		CreatedAt:   invite.CreatedAt,
+	}
+}
+
 // ToClientUserResponse converte domain.ClientUser para ClientUserResponse
 func ToClientUserResponse(cu *domain.ClientUser) *ClientUserResponse {
 	return &ClientUserResponse{
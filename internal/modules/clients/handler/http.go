@@ -7,10 +7,12 @@ import (
 	"strconv"
 
 	"agro-monitoring/internal/config"
+	"agro-monitoring/internal/modules/clients/domain"
 	"agro-monitoring/internal/modules/clients/dto"
 	"agro-monitoring/internal/modules/clients/usecase"
 	sharedContext "agro-monitoring/internal/shared/context"
 	sharedErrors "agro-monitoring/internal/shared/errors"
+	sharedMiddleware "agro-monitoring/internal/shared/middleware"
 	"agro-monitoring/internal/shared/response"
 
 	"github.com/go-chi/chi/v5"
@@ -34,7 +36,17 @@ func (h *Handler) RegisterRoutes(r chi.Router) {
 	r.Route("/clients", func(r chi.Router) {
 		r.Get("/me", h.GetMyClient)
 		r.Get("/me/stats", h.GetMyStats)
-		r.Get("/me/users", h.ListMyUsers)
+
+		// Gestão de usuários do client: apenas admins do tenant
+		r.Group(func(r chi.Router) {
+			r.Use(sharedMiddleware.RequireTenantRole(h.clientUC, domain.ClientUserRoleAdmin))
+			r.Get("/me/users", h.ListMyUsers)
+			r.Post("/me/users/invites", h.InviteMyUser)
+			r.Get("/me/users/invites", h.ListMyInvites)
+			r.Patch("/me/users/{id}/role", h.ChangeMyUserRole)
+			r.Post("/me/users/{id}/deactivate", h.DeactivateMyUser)
+			r.Post("/me/users/{id}/reactivate", h.ReactivateMyUser)
+		})
 	})
 }
 
@@ -45,8 +57,11 @@ func (h *Handler) RegisterAdminRoutes(r chi.Router) {
 		r.Get("/", h.List)
 		r.Get("/{id}", h.GetByID)
 		r.Get("/{id}/stats", h.GetStats)
+		r.Get("/{id}/stats/history", h.GetStatsHistory)
 		r.Patch("/{id}", h.Update)
 		r.Delete("/{id}", h.Delete)
+		r.Post("/{id}/restore", h.Restore)
+		r.Post("/{id}/invites", h.InviteUser)
 	})
 }
 
@@ -140,16 +155,90 @@ func (h *Handler) GetStats(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, http.StatusOK, response.NewSuccessResponse(resp))
 }
 
-// Update atualiza um client (admin)
+// GetStatsHistory retorna as estatísticas diárias do client (admin), ?days=30
+func (h *Handler) GetStatsHistory(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	days, _ := strconv.Atoi(r.URL.Query().Get("days"))
+
+	history, err := h.clientUC.GetStatsHistory(r.Context(), id, days)
+	if err != nil {
+		if err == sharedErrors.ErrClientNotFound {
+			respondError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "Failed to get stats history")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response.NewSuccessResponse(dto.ToClientStatsHistoryResponse(history)))
+}
+
+// Update atualiza parcialmente um client (admin)
 func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
-	// TODO: Implementar update
-	respondError(w, http.StatusNotImplemented, "Not implemented")
+	id := chi.URLParam(r, "id")
+
+	var req dto.UpdateClientRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	client, err := h.clientUC.UpdateClient(r.Context(), id, req)
+	if err != nil {
+		switch err {
+		case sharedErrors.ErrClientNotFound:
+			respondError(w, http.StatusNotFound, err.Error())
+		case sharedErrors.ErrInvalidMaxUsers:
+			respondError(w, http.StatusBadRequest, err.Error())
+		case sharedErrors.ErrMaxUsersBelowActive:
+			respondError(w, http.StatusConflict, err.Error())
+		default:
+			fmt.Printf("[ERROR] Failed to update client: %v\n", err)
+			respondError(w, http.StatusInternalServerError, "Failed to update client")
+		}
+		return
+	}
+
+	resp := dto.ToClientResponse(client, h.env.AppBaseURL)
+	respondJSON(w, http.StatusOK, response.NewSuccessResponse(resp))
 }
 
-// Delete deleta um client (admin)
+// Delete remove um client com soft-delete; os dados podem ser restaurados durante a carência (admin)
 func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
-	// TODO: Implementar delete
-	respondError(w, http.StatusNotImplemented, "Not implemented")
+	id := chi.URLParam(r, "id")
+
+	client, err := h.clientUC.DeleteClient(r.Context(), id)
+	if err != nil {
+		if err == sharedErrors.ErrClientNotFound {
+			respondError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		fmt.Printf("[ERROR] Failed to delete client: %v\n", err)
+		respondError(w, http.StatusInternalServerError, "Failed to delete client")
+		return
+	}
+
+	resp := dto.ToClientResponse(client, h.env.AppBaseURL)
+	respondJSON(w, http.StatusOK, response.NewSuccessResponse(resp))
+}
+
+// Restore desfaz o soft-delete de um client (admin)
+func (h *Handler) Restore(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	client, err := h.clientUC.RestoreClient(r.Context(), id)
+	if err != nil {
+		if err == sharedErrors.ErrClientNotFound {
+			respondError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		fmt.Printf("[ERROR] Failed to restore client: %v\n", err)
+		respondError(w, http.StatusInternalServerError, "Failed to restore client")
+		return
+	}
+
+	resp := dto.ToClientResponse(client, h.env.AppBaseURL)
+	respondJSON(w, http.StatusOK, response.NewSuccessResponse(resp))
 }
 
 // RegisterUser registra um novo usuário para um client (público)
@@ -162,9 +251,16 @@ func (h *Handler) RegisterUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// O link de convite carrega o token na query string
+	if req.InviteToken == "" {
+		req.InviteToken = r.URL.Query().Get("invite")
+	}
+
 	clientUser, err := h.clientUC.RegisterUser(r.Context(), slug, req)
 	if err != nil {
 		switch err {
+		case sharedErrors.ErrInvalidInvite:
+			respondError(w, http.StatusBadRequest, err.Error())
 		case sharedErrors.ErrClientNotFound:
 			respondError(w, http.StatusNotFound, "URL de registro inválida")
 		case sharedErrors.ErrClientInactive:
@@ -259,6 +355,160 @@ func (h *Handler) ListMyUsers(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, http.StatusOK, response.NewSuccessResponse(data))
 }
 
+// InviteUser convida um usuário para um client (admin); usado para criar o primeiro admin do tenant
+func (h *Handler) InviteUser(w http.ResponseWriter, r *http.Request) {
+	h.invite(w, r, chi.URLParam(r, "id"))
+}
+
+// InviteMyUser convida um usuário para o client do admin autenticado
+func (h *Handler) InviteMyUser(w http.ResponseWriter, r *http.Request) {
+	clientID, ok := sharedContext.GetClientID(r.Context())
+	if !ok {
+		respondError(w, http.StatusForbidden, "No client association")
+		return
+	}
+	h.invite(w, r, clientID)
+}
+
+func (h *Handler) invite(w http.ResponseWriter, r *http.Request, clientID string) {
+	var req dto.InviteUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if req.Email == "" {
+		respondError(w, http.StatusBadRequest, "email is required")
+		return
+	}
+
+	invitedBy, _ := sharedContext.GetUserID(r.Context())
+
+	invite, err := h.clientUC.InviteUser(r.Context(), clientID, invitedBy, req)
+	if err != nil {
+		switch err {
+		case sharedErrors.ErrClientNotFound:
+			respondError(w, http.StatusNotFound, err.Error())
+		case sharedErrors.ErrInvalidRole:
+			respondError(w, http.StatusBadRequest, err.Error())
+		case sharedErrors.ErrClientInactive, sharedErrors.ErrClientUserLimitReached:
+			respondError(w, http.StatusConflict, err.Error())
+		default:
+			fmt.Printf("[ERROR] Failed to invite user: %v\n", err)
+			respondError(w, http.StatusInternalServerError, "Failed to invite user")
+		}
+		return
+	}
+
+	client, err := h.clientUC.GetClient(r.Context(), clientID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get client")
+		return
+	}
+
+	resp := dto.ToInviteResponse(invite, client.Slug, h.env.AppBaseURL)
+	respondJSON(w, http.StatusCreated, response.NewSuccessResponse(resp))
+}
+
+// ListMyInvites lista convites pendentes do client do admin autenticado
+func (h *Handler) ListMyInvites(w http.ResponseWriter, r *http.Request) {
+	clientID, ok := sharedContext.GetClientID(r.Context())
+	if !ok {
+		respondError(w, http.StatusForbidden, "No client association")
+		return
+	}
+
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	pageSize, _ := strconv.Atoi(r.URL.Query().Get("page_size"))
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	client, err := h.clientUC.GetClient(r.Context(), clientID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to get client")
+		return
+	}
+
+	invites, total, err := h.clientUC.ListPendingInvites(r.Context(), clientID, page, pageSize)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to list invites")
+		return
+	}
+
+	var resp []dto.InviteResponse
+	for _, invite := range invites {
+		resp = append(resp, *dto.ToInviteResponse(invite, client.Slug, h.env.AppBaseURL))
+	}
+
+	data := map[string]interface{}{
+		"invites": resp,
+		"total":   total,
+		"page":    page,
+	}
+	respondJSON(w, http.StatusOK, response.NewSuccessResponse(data))
+}
+
+// ChangeMyUserRole promove ou rebaixa um usuário do client
+func (h *Handler) ChangeMyUserRole(w http.ResponseWriter, r *http.Request) {
+	var req dto.ChangeRoleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	h.manageUser(w, r, func(clientID, actorID, memberID string) (*domain.ClientUser, error) {
+		return h.clientUC.ChangeUserRole(r.Context(), clientID, actorID, memberID, req.Role)
+	})
+}
+
+// DeactivateMyUser desativa um usuário do client
+func (h *Handler) DeactivateMyUser(w http.ResponseWriter, r *http.Request) {
+	h.manageUser(w, r, func(clientID, actorID, memberID string) (*domain.ClientUser, error) {
+		return h.clientUC.DeactivateUser(r.Context(), clientID, actorID, memberID)
+	})
+}
+
+// ReactivateMyUser reativa um usuário do client
+func (h *Handler) ReactivateMyUser(w http.ResponseWriter, r *http.Request) {
+	h.manageUser(w, r, func(clientID, actorID, memberID string) (*domain.ClientUser, error) {
+		return h.clientUC.ReactivateUser(r.Context(), clientID, actorID, memberID)
+	})
+}
+
+// manageUser resolve tenant/ator e traduz os erros comuns da gestão de usuários
+func (h *Handler) manageUser(w http.ResponseWriter, r *http.Request, action func(clientID, actorID, memberID string) (*domain.ClientUser, error)) {
+	clientID, ok := sharedContext.GetClientID(r.Context())
+	if !ok {
+		respondError(w, http.StatusForbidden, "No client association")
+		return
+	}
+	actorID, _ := sharedContext.GetUserID(r.Context())
+
+	member, err := action(clientID, actorID, chi.URLParam(r, "id"))
+	if err != nil {
+		switch err {
+		case sharedErrors.ErrClientUserNotFound, sharedErrors.ErrClientNotFound:
+			respondError(w, http.StatusNotFound, err.Error())
+		case sharedErrors.ErrInvalidRole:
+			respondError(w, http.StatusBadRequest, err.Error())
+		case sharedErrors.ErrCannotModifySelf:
+			respondError(w, http.StatusForbidden, err.Error())
+		case sharedErrors.ErrClientInactive, sharedErrors.ErrClientUserLimitReached:
+			respondError(w, http.StatusConflict, err.Error())
+		default:
+			fmt.Printf("[ERROR] Failed to update client user: %v\n", err)
+			respondError(w, http.StatusInternalServerError, "Failed to update user")
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, response.NewSuccessResponse(dto.ToClientUserResponse(member)))
+}
+
 func respondJSON(w http.ResponseWriter, status int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"agro-monitoring/internal/modules/clients/domain"
+	"agro-monitoring/internal/shared/database"
+)
+
+const selectInviteColumns = `id, client_id, email, role, token, invited_by, expires_at, accepted_at, created_at`
+
+type ClientInvitePostgresRepository struct {
+	db *database.TenantDB
+}
+
+func NewClientInvitePostgresRepository(db *database.TenantDB) domain.ClientInviteRepository {
+	return &ClientInvitePostgresRepository{db: db}
+}
+
+func (r *ClientInvitePostgresRepository) Create(ctx context.Context, invite *domain.ClientInvite) error {
+	query := `
+		INSERT INTO client_invites (id, client_id, email, role, token, invited_by, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	err := r.db.InTenant(ctx, invite.ClientID, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, query,
+			invite.ID,
+			invite.ClientID,
+			invite.Email,
+			invite.Role,
+			invite.Token,
+			invite.InvitedBy,
+			invite.ExpiresAt,
+			invite.CreatedAt,
+		)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao criar convite: %w", err)
+	}
+
+	return nil
+}
+
+func (r *ClientInvitePostgresRepository) GetByToken(ctx context.Context, clientID, token string) (*domain.ClientInvite, error) {
+	query := "SELECT " + selectInviteColumns + " FROM client_invites WHERE client_id = $1 AND token = $2"
+
+	var invite *domain.ClientInvite
+	err := r.db.InTenant(ctx, clientID, func(tx *sql.Tx) error {
+		var err error
+		invite, err = scanInvite(tx.QueryRowContext(ctx, query, clientID, token))
+		return err
+	})
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar convite: %w", err)
+	}
+
+	return invite, nil
+}
+
+func (r *ClientInvitePostgresRepository) ListPendingByClient(ctx context.Context, clientID string, limit, offset int) ([]*domain.ClientInvite, int, error) {
+	where := " FROM client_invites WHERE client_id = $1 AND accepted_at IS NULL AND expires_at > NOW()"
+	countQuery := "SELECT COUNT(*)" + where
+	query := "SELECT " + selectInviteColumns + where + " ORDER BY created_at DESC LIMIT $2 OFFSET $3"
+
+	var total int
+	var invites []*domain.ClientInvite
+
+	err := r.db.InTenant(ctx, clientID, func(tx *sql.Tx) error {
+		if err := tx.QueryRowContext(ctx, countQuery, clientID).Scan(&total); err != nil {
+			return fmt.Errorf("erro ao contar convites: %w", err)
+		}
+
+		rows, err := tx.QueryContext(ctx, query, clientID, limit, offset)
+		if err != nil {
+			return fmt.Errorf("erro ao listar convites: %w", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			invite, err := scanInvite(rows)
+			if err != nil {
+				return fmt.Errorf("erro ao escanear convite: %w", err)
+			}
+			invites = append(invites, invite)
+		}
+
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return invites, total, nil
+}
+
+func (r *ClientInvitePostgresRepository) MarkAccepted(ctx context.Context, clientID, id string) error {
+	query := "UPDATE client_invites SET accepted_at = NOW() WHERE id = $1 AND client_id = $2"
+	err := r.db.InTenant(ctx, clientID, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, query, id, clientID)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao aceitar convite: %w", err)
+	}
+	return nil
+}
+
+func scanInvite(row rowScanner) (*domain.ClientInvite, error) {
+	var invite domain.ClientInvite
+	var acceptedAt sql.NullTime
+
+	err := row.Scan(
+		&invite.ID,
+		&invite.ClientID,
+		&invite.Email,
+		&invite.Role,
+		&invite.Token,
+		&invite.InvitedBy,
+		&invite.ExpiresAt,
+		&acceptedAt,
+		&invite.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if acceptedAt.Valid {
+		invite.AcceptedAt = &acceptedAt.Time
+	}
+
+	return &invite, nil
+}
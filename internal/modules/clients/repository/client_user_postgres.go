@@ -6,13 +6,15 @@ import (
 	"fmt"
 
 	"agro-monitoring/internal/modules/clients/domain"
+	"agro-monitoring/internal/shared/database"
+	sharedErrors "agro-monitoring/internal/shared/errors"
 )
 
 type ClientUserPostgresRepository struct {
-	db *sql.DB
+	db *database.TenantDB
 }
 
-func NewClientUserPostgresRepository(db *sql.DB) domain.ClientUserRepository {
+func NewClientUserPostgresRepository(db *database.TenantDB) domain.ClientUserRepository {
 	return &ClientUserPostgresRepository{db: db}
 }
 
@@ -22,15 +24,18 @@ func (r *ClientUserPostgresRepository) Create(ctx context.Context, cu *domain.Cl
 		VALUES ($1, $2, $3, $4, $5, $6, $7)
 	`
 
-	_, err := r.db.ExecContext(ctx, query,
-		cu.ID,
-		cu.ClientID,
-		cu.UserID,
-		cu.Email,
-		cu.Role,
-		cu.Active,
-		cu.CreatedAt,
-	)
+	err := r.db.InTenant(ctx, cu.ClientID, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, query,
+			cu.ID,
+			cu.ClientID,
+			cu.UserID,
+			cu.Email,
+			cu.Role,
+			cu.Active,
+			cu.CreatedAt,
+		)
+		return err
+	})
 
 	if err != nil {
 		return fmt.Errorf("erro ao criar client_user: %w", err)
@@ -39,6 +44,54 @@ func (r *ClientUserPostgresRepository) Create(ctx context.Context, cu *domain.Cl
 	return nil
 }
 
+func (r *ClientUserPostgresRepository) CreateWithinLimit(ctx context.Context, cu *domain.ClientUser, maxUsers int) error {
+	lockQuery := "SELECT id FROM clients WHERE id = $1 FOR UPDATE"
+	countQuery := "SELECT COUNT(*) FROM client_users WHERE client_id = $1 AND active = true"
+	insertQuery := `
+		INSERT INTO client_users (id, client_id, user_id, email, role, active, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	err := r.db.InTenant(ctx, cu.ClientID, func(tx *sql.Tx) error {
+		// A trava na linha do client serializa registros concorrentes no mesmo tenant
+		var lockedID string
+		if err := tx.QueryRowContext(ctx, lockQuery, cu.ClientID).Scan(&lockedID); err != nil {
+			if err == sql.ErrNoRows {
+				return sharedErrors.ErrClientNotFound
+			}
+			return err
+		}
+
+		var count int
+		if err := tx.QueryRowContext(ctx, countQuery, cu.ClientID).Scan(&count); err != nil {
+			return err
+		}
+		if count >= maxUsers {
+			return sharedErrors.ErrClientUserLimitReached
+		}
+
+		_, err := tx.ExecContext(ctx, insertQuery,
+			cu.ID,
+			cu.ClientID,
+			cu.UserID,
+			cu.Email,
+			cu.Role,
+			cu.Active,
+			cu.CreatedAt,
+		)
+		return err
+	})
+
+	switch err {
+	case nil:
+		return nil
+	case sharedErrors.ErrClientNotFound, sharedErrors.ErrClientUserLimitReached:
+		return err
+	default:
+		return fmt.Errorf("erro ao criar client_user: %w", err)
+	}
+}
+
 func (r *ClientUserPostgresRepository) GetByClientAndUserID(ctx context.Context, clientID, userID string) (*domain.ClientUser, error) {
 	query := `
 		SELECT id, client_id, user_id, email, role, active, created_at
@@ -47,15 +100,17 @@ func (r *ClientUserPostgresRepository) GetByClientAndUserID(ctx context.Context,
 	`
 
 	var cu domain.ClientUser
-	err := r.db.QueryRowContext(ctx, query, clientID, userID).Scan(
-		&cu.ID,
-		&cu.ClientID,
-		&cu.UserID,
-		&cu.Email,
-		&cu.Role,
-		&cu.Active,
-		&cu.CreatedAt,
-	)
+	err := r.db.InTenant(ctx, clientID, func(tx *sql.Tx) error {
+		return tx.QueryRowContext(ctx, query, clientID, userID).Scan(
+			&cu.ID,
+			&cu.ClientID,
+			&cu.UserID,
+			&cu.Email,
+			&cu.Role,
+			&cu.Active,
+			&cu.CreatedAt,
+		)
+	})
 
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -71,7 +126,9 @@ func (r *ClientUserPostgresRepository) CountActiveByClient(ctx context.Context,
 	query := "SELECT COUNT(*) FROM client_users WHERE client_id = $1 AND active = true"
 
 	var count int
-	err := r.db.QueryRowContext(ctx, query, clientID).Scan(&count)
+	err := r.db.InTenant(ctx, clientID, func(tx *sql.Tx) error {
+		return tx.QueryRowContext(ctx, query, clientID).Scan(&count)
+	})
 	if err != nil {
 		return 0, fmt.Errorf("erro ao contar usuários: %w", err)
 	}
@@ -80,15 +137,7 @@ func (r *ClientUserPostgresRepository) CountActiveByClient(ctx context.Context,
 }
 
 func (r *ClientUserPostgresRepository) ListByClient(ctx context.Context, clientID string, limit, offset int) ([]*domain.ClientUser, int, error) {
-	// Contar total
-	var total int
 	countQuery := "SELECT COUNT(*) FROM client_users WHERE client_id = $1"
-	err := r.db.QueryRowContext(ctx, countQuery, clientID).Scan(&total)
-	if err != nil {
-		return nil, 0, fmt.Errorf("erro ao contar client_users: %w", err)
-	}
-
-	// Buscar paginado
 	query := `
 		SELECT id, client_id, user_id, email, role, active, created_at
 		FROM client_users
@@ -97,16 +146,58 @@ func (r *ClientUserPostgresRepository) ListByClient(ctx context.Context, clientI
 		LIMIT $2 OFFSET $3
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, clientID, limit, offset)
+	var total int
+	var users []*domain.ClientUser
+
+	err := r.db.InTenant(ctx, clientID, func(tx *sql.Tx) error {
+		// Contar total
+		if err := tx.QueryRowContext(ctx, countQuery, clientID).Scan(&total); err != nil {
+			return fmt.Errorf("erro ao contar client_users: %w", err)
+		}
+
+		// Buscar paginado
+		rows, err := tx.QueryContext(ctx, query, clientID, limit, offset)
+		if err != nil {
+			return fmt.Errorf("erro ao listar client_users: %w", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var cu domain.ClientUser
+			err := rows.Scan(
+				&cu.ID,
+				&cu.ClientID,
+				&cu.UserID,
+				&cu.Email,
+				&cu.Role,
+				&cu.Active,
+				&cu.CreatedAt,
+			)
+			if err != nil {
+				return fmt.Errorf("erro ao escanear client_user: %w", err)
+			}
+			users = append(users, &cu)
+		}
+
+		return rows.Err()
+	})
 	if err != nil {
-		return nil, 0, fmt.Errorf("erro ao listar client_users: %w", err)
+		return nil, 0, err
 	}
-	defer rows.Close()
 
-	var users []*domain.ClientUser
-	for rows.Next() {
-		var cu domain.ClientUser
-		err := rows.Scan(
+	return users, total, nil
+}
+
+func (r *ClientUserPostgresRepository) GetByID(ctx context.Context, clientID, id string) (*domain.ClientUser, error) {
+	query := `
+		SELECT id, client_id, user_id, email, role, active, created_at
+		FROM client_users
+		WHERE id = $1 AND client_id = $2
+	`
+
+	var cu domain.ClientUser
+	err := r.db.InTenant(ctx, clientID, func(tx *sql.Tx) error {
+		return tx.QueryRowContext(ctx, query, id, clientID).Scan(
 			&cu.ID,
 			&cu.ClientID,
 			&cu.UserID,
@@ -115,20 +206,74 @@ func (r *ClientUserPostgresRepository) ListByClient(ctx context.Context, clientI
 			&cu.Active,
 			&cu.CreatedAt,
 		)
-		if err != nil {
-			return nil, 0, fmt.Errorf("erro ao escanear client_user: %w", err)
-		}
-		users = append(users, &cu)
+	})
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar client_user: %w", err)
 	}
 
-	return users, total, nil
+	return &cu, nil
 }
 
-func (r *ClientUserPostgresRepository) Deactivate(ctx context.Context, id string) error {
-	query := "UPDATE client_users SET active = false WHERE id = $1"
-	_, err := r.db.ExecContext(ctx, query, id)
+func (r *ClientUserPostgresRepository) UpdateRole(ctx context.Context, clientID, id, role string) error {
+	query := "UPDATE client_users SET role = $1 WHERE id = $2 AND client_id = $3"
+	err := r.db.InTenant(ctx, clientID, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, query, role, id, clientID)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar papel do client_user: %w", err)
+	}
+	return nil
+}
+
+func (r *ClientUserPostgresRepository) Deactivate(ctx context.Context, clientID, id string) error {
+	query := "UPDATE client_users SET active = false WHERE id = $1 AND client_id = $2"
+	err := r.db.InTenant(ctx, clientID, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, query, id, clientID)
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("erro ao desativar client_user: %w", err)
 	}
 	return nil
 }
+
+func (r *ClientUserPostgresRepository) ReactivateWithinLimit(ctx context.Context, clientID, id string, maxUsers int) error {
+	lockQuery := "SELECT id FROM clients WHERE id = $1 FOR UPDATE"
+	countQuery := "SELECT COUNT(*) FROM client_users WHERE client_id = $1 AND active = true"
+	updateQuery := "UPDATE client_users SET active = true WHERE id = $1 AND client_id = $2 AND active = false"
+
+	err := r.db.InTenant(ctx, clientID, func(tx *sql.Tx) error {
+		var lockedID string
+		if err := tx.QueryRowContext(ctx, lockQuery, clientID).Scan(&lockedID); err != nil {
+			if err == sql.ErrNoRows {
+				return sharedErrors.ErrClientNotFound
+			}
+			return err
+		}
+
+		var count int
+		if err := tx.QueryRowContext(ctx, countQuery, clientID).Scan(&count); err != nil {
+			return err
+		}
+		if count >= maxUsers {
+			return sharedErrors.ErrClientUserLimitReached
+		}
+
+		_, err := tx.ExecContext(ctx, updateQuery, id, clientID)
+		return err
+	})
+
+	switch err {
+	case nil:
+		return nil
+	case sharedErrors.ErrClientNotFound, sharedErrors.ErrClientUserLimitReached:
+		return err
+	default:
+		return fmt.Errorf("erro ao reativar client_user: %w", err)
+	}
+}
@@ -4,20 +4,23 @@ import (
 	"context"
 	"sort"
 	"sync"
+	"time"
 
 	"agro-monitoring/internal/modules/clients/domain"
 )
 
 type InMemoryRepository struct {
-	mu      sync.RWMutex
-	clients map[string]*domain.Client
-	slugs   map[string]string // slug -> id
+	mu        sync.RWMutex
+	clients   map[string]*domain.Client
+	slugs     map[string]string // slug -> id
+	snapshots map[string]map[time.Time]*domain.ClientStatsSnapshot
 }
 
 func NewInMemoryRepository() domain.ClientRepository {
 	return &InMemoryRepository{
-		clients: make(map[string]*domain.Client),
-		slugs:   make(map[string]string),
+		clients:   make(map[string]*domain.Client),
+		slugs:     make(map[string]string),
+		snapshots: make(map[string]map[time.Time]*domain.ClientStatsSnapshot),
 	}
 }
 
@@ -55,6 +58,9 @@ func (r *InMemoryRepository) List(ctx context.Context, limit, offset int) ([]*do
 	// Convert map to slice
 	all := make([]*domain.Client, 0, len(r.clients))
 	for _, client := range r.clients {
+		if client.IsDeleted() {
+			continue
+		}
 		all = append(all, client)
 	}
 
@@ -99,14 +105,29 @@ func (r *InMemoryRepository) Delete(ctx context.Context, id string) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	if client, ok := r.clients[id]; ok {
-		delete(r.slugs, client.Slug)
-		delete(r.clients, id)
+	if client, ok := r.clients[id]; ok && !client.IsDeleted() {
+		client.SoftDelete()
 	}
 
 	return nil
 }
 
+func (r *InMemoryRepository) PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	purged := 0
+	for id, client := range r.clients {
+		if client.IsDeleted() && client.DeletedAt.Before(deletedBefore) {
+			delete(r.slugs, client.Slug)
+			delete(r.clients, id)
+			purged++
+		}
+	}
+
+	return purged, nil
+}
+
 func (r *InMemoryRepository) GetStats(ctx context.Context, clientID string) (*domain.ClientStats, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -126,9 +147,35 @@ func (r *InMemoryRepository) GetStats(ctx context.Context, clientID string) (*do
 	}, nil
 }
 
+func (r *InMemoryRepository) SaveStatsSnapshot(ctx context.Context, snapshot *domain.ClientStatsSnapshot) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.snapshots[snapshot.ClientID] == nil {
+		r.snapshots[snapshot.ClientID] = make(map[time.Time]*domain.ClientStatsSnapshot)
+	}
+	r.snapshots[snapshot.ClientID][snapshot.Day] = snapshot
+	return nil
+}
+
+func (r *InMemoryRepository) ListStatsSnapshots(ctx context.Context, clientID string, since time.Time) ([]*domain.ClientStatsSnapshot, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]*domain.ClientStatsSnapshot, 0)
+	for day, snapshot := range r.snapshots[clientID] {
+		if !day.Before(since) {
+			result = append(result, snapshot)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Day.Before(result[j].Day) })
+	return result, nil
+}
+
 func (r *InMemoryRepository) Clear() {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	r.clients = make(map[string]*domain.Client)
 	r.slugs = make(map[string]string)
+	r.snapshots = make(map[string]map[time.Time]*domain.ClientStatsSnapshot)
 }
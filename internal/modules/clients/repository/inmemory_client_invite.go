@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"agro-monitoring/internal/modules/clients/domain"
+)
+
+type InMemoryClientInviteRepository struct {
+	mu      sync.RWMutex
+	invites map[string]*domain.ClientInvite
+}
+
+func NewInMemoryClientInviteRepository() domain.ClientInviteRepository {
+	return &InMemoryClientInviteRepository{
+		invites: make(map[string]*domain.ClientInvite),
+	}
+}
+
+func (r *InMemoryClientInviteRepository) Create(ctx context.Context, invite *domain.ClientInvite) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.invites[invite.ID] = invite
+	return nil
+}
+
+func (r *InMemoryClientInviteRepository) GetByToken(ctx context.Context, clientID, token string) (*domain.ClientInvite, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, invite := range r.invites {
+		if invite.ClientID == clientID && invite.Token == token {
+			return invite, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (r *InMemoryClientInviteRepository) ListPendingByClient(ctx context.Context, clientID string, limit, offset int) ([]*domain.ClientInvite, int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	filtered := make([]*domain.ClientInvite, 0)
+	for _, invite := range r.invites {
+		if invite.ClientID == clientID && invite.IsPending() {
+			filtered = append(filtered, invite)
+		}
+	}
+
+	sort.Slice(filtered, func(i, j int) bool {
+		return filtered[i].CreatedAt.After(filtered[j].CreatedAt)
+	})
+
+	total := len(filtered)
+
+	start := offset
+	if start > total {
+		return []*domain.ClientInvite{}, total, nil
+	}
+
+	end := start + limit
+	if end > total {
+		end = total
+	}
+
+	return filtered[start:end], total, nil
+}
+
+func (r *InMemoryClientInviteRepository) MarkAccepted(ctx context.Context, clientID, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if invite, ok := r.invites[id]; ok && invite.ClientID == clientID {
+		now := time.Now()
+		invite.AcceptedAt = &now
+	}
+
+	return nil
+}
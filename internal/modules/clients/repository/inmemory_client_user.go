@@ -6,6 +6,7 @@ import (
 	"sync"
 
 	"agro-monitoring/internal/modules/clients/domain"
+	sharedErrors "agro-monitoring/internal/shared/errors"
 )
 
 type InMemoryClientUserRepository struct {
@@ -27,6 +28,24 @@ func (r *InMemoryClientUserRepository) Create(ctx context.Context, cu *domain.Cl
 	return nil
 }
 
+func (r *InMemoryClientUserRepository) CreateWithinLimit(ctx context.Context, cu *domain.ClientUser, maxUsers int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := 0
+	for _, existing := range r.users {
+		if existing.ClientID == cu.ClientID && existing.Active {
+			count++
+		}
+	}
+	if count >= maxUsers {
+		return sharedErrors.ErrClientUserLimitReached
+	}
+
+	r.users[cu.ID] = cu
+	return nil
+}
+
 func (r *InMemoryClientUserRepository) GetByClientAndUserID(ctx context.Context, clientID, userID string) (*domain.ClientUser, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -87,11 +106,52 @@ func (r *InMemoryClientUserRepository) ListByClient(ctx context.Context, clientI
 	return filtered[start:end], total, nil
 }
 
-func (r *InMemoryClientUserRepository) Deactivate(ctx context.Context, id string) error {
+func (r *InMemoryClientUserRepository) GetByID(ctx context.Context, clientID, id string) (*domain.ClientUser, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	cu, ok := r.users[id]
+	if !ok || cu.ClientID != clientID {
+		return nil, nil
+	}
+	return cu, nil
+}
+
+func (r *InMemoryClientUserRepository) UpdateRole(ctx context.Context, clientID, id, role string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if cu, ok := r.users[id]; ok && cu.ClientID == clientID {
+		cu.Role = role
+	}
+	return nil
+}
+
+func (r *InMemoryClientUserRepository) ReactivateWithinLimit(ctx context.Context, clientID, id string, maxUsers int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := 0
+	for _, existing := range r.users {
+		if existing.ClientID == clientID && existing.Active {
+			count++
+		}
+	}
+	if count >= maxUsers {
+		return sharedErrors.ErrClientUserLimitReached
+	}
+
+	if cu, ok := r.users[id]; ok && cu.ClientID == clientID {
+		cu.Active = true
+	}
+	return nil
+}
+
+func (r *InMemoryClientUserRepository) Deactivate(ctx context.Context, clientID, id string) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	if cu, ok := r.users[id]; ok {
+	if cu, ok := r.users[id]; ok && cu.ClientID == clientID {
 		cu.Active = false
 	}
 
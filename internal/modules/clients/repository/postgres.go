@@ -5,16 +5,19 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"agro-monitoring/internal/modules/clients/domain"
+	"agro-monitoring/internal/shared/database"
 )
 
 type PostgresRepository struct {
-	db *sql.DB
+	db       *sql.DB
+	tenantDB *database.TenantDB
 }
 
-func NewPostgresRepository(db *sql.DB) domain.ClientRepository {
-	return &PostgresRepository{db: db}
+func NewPostgresRepository(tenantDB *database.TenantDB) domain.ClientRepository {
+	return &PostgresRepository{db: tenantDB.DB(), tenantDB: tenantDB}
 }
 
 func (r *PostgresRepository) Create(ctx context.Context, client *domain.Client) error {
@@ -47,17 +50,18 @@ func (r *PostgresRepository) Create(ctx context.Context, client *domain.Client)
 	return nil
 }
 
-func (r *PostgresRepository) GetByID(ctx context.Context, id string) (*domain.Client, error) {
-	query := `
-		SELECT id, name, slug, max_users, active, metadata, keycloak_group_id, created_at, updated_at
-		FROM clients
-		WHERE id = $1
-	`
+const selectClientColumns = `id, name, slug, max_users, active, metadata, keycloak_group_id, created_at, updated_at, deleted_at`
 
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanClient(row rowScanner) (*domain.Client, error) {
 	var client domain.Client
 	var metadataJSON []byte
+	var deletedAt sql.NullTime
 
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
+	err := row.Scan(
 		&client.ID,
 		&client.Name,
 		&client.Slug,
@@ -67,13 +71,14 @@ func (r *PostgresRepository) GetByID(ctx context.Context, id string) (*domain.Cl
 		&client.KeycloakGroupID,
 		&client.CreatedAt,
 		&client.UpdatedAt,
+		&deletedAt,
 	)
-
-	if err == sql.ErrNoRows {
-		return nil, nil
-	}
 	if err != nil {
-		return nil, fmt.Errorf("erro ao buscar client: %w", err)
+		return nil, err
+	}
+
+	if deletedAt.Valid {
+		client.DeletedAt = &deletedAt.Time
 	}
 
 	if err := json.Unmarshal(metadataJSON, &client.Metadata); err != nil {
@@ -83,28 +88,26 @@ func (r *PostgresRepository) GetByID(ctx context.Context, id string) (*domain.Cl
 	return &client, nil
 }
 
-func (r *PostgresRepository) GetBySlug(ctx context.Context, slug string) (*domain.Client, error) {
-	query := `
-		SELECT id, name, slug, max_users, active, metadata, keycloak_group_id, created_at, updated_at
-		FROM clients
-		WHERE slug = $1
-	`
+// GetByID retorna o client mesmo se removido, para permitir restauração
+func (r *PostgresRepository) GetByID(ctx context.Context, id string) (*domain.Client, error) {
+	query := "SELECT " + selectClientColumns + " FROM clients WHERE id = $1"
 
-	var client domain.Client
-	var metadataJSON []byte
+	client, err := scanClient(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar client: %w", err)
+	}
 
-	err := r.db.QueryRowContext(ctx, query, slug).Scan(
-		&client.ID,
-		&client.Name,
-		&client.Slug,
-		&client.MaxUsers,
-		&client.Active,
-		&metadataJSON,
-		&client.KeycloakGroupID,
-		&client.CreatedAt,
-		&client.UpdatedAt,
-	)
+	return client, nil
+}
+
+// GetBySlug também retorna clients removidos: o slug segue reservado até o purge
+func (r *PostgresRepository) GetBySlug(ctx context.Context, slug string) (*domain.Client, error) {
+	query := "SELECT " + selectClientColumns + " FROM clients WHERE slug = $1"
 
+	client, err := scanClient(r.db.QueryRowContext(ctx, query, slug))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -112,25 +115,21 @@ func (r *PostgresRepository) GetBySlug(ctx context.Context, slug string) (*domai
 		return nil, fmt.Errorf("erro ao buscar client por slug: %w", err)
 	}
 
-	if err := json.Unmarshal(metadataJSON, &client.Metadata); err != nil {
-		return nil, fmt.Errorf("erro ao deserializar metadata: %w", err)
-	}
-
-	return &client, nil
+	return client, nil
 }
 
 func (r *PostgresRepository) List(ctx context.Context, limit, offset int) ([]*domain.Client, int, error) {
 	// Contar total
 	var total int
-	err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM clients").Scan(&total)
+	err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM clients WHERE deleted_at IS NULL").Scan(&total)
 	if err != nil {
 		return nil, 0, fmt.Errorf("erro ao contar clients: %w", err)
 	}
 
 	// Buscar paginado
-	query := `
-		SELECT id, name, slug, max_users, active, metadata, keycloak_group_id, created_at, updated_at
+	query := "SELECT " + selectClientColumns + `
 		FROM clients
+		WHERE deleted_at IS NULL
 		ORDER BY created_at DESC
 		LIMIT $1 OFFSET $2
 	`
@@ -143,29 +142,11 @@ func (r *PostgresRepository) List(ctx context.Context, limit, offset int) ([]*do
 
 	var clients []*domain.Client
 	for rows.Next() {
-		var client domain.Client
-		var metadataJSON []byte
-
-		err := rows.Scan(
-			&client.ID,
-			&client.Name,
-			&client.Slug,
-			&client.MaxUsers,
-			&client.Active,
-			&metadataJSON,
-			&client.KeycloakGroupID,
-			&client.CreatedAt,
-			&client.UpdatedAt,
-		)
+		client, err := scanClient(rows)
 		if err != nil {
 			return nil, 0, fmt.Errorf("erro ao escanear client: %w", err)
 		}
-
-		if err := json.Unmarshal(metadataJSON, &client.Metadata); err != nil {
-			return nil, 0, fmt.Errorf("erro ao deserializar metadata: %w", err)
-		}
-
-		clients = append(clients, &client)
+		clients = append(clients, client)
 	}
 
 	return clients, total, nil
@@ -180,8 +161,8 @@ func (r *PostgresRepository) Update(ctx context.Context, client *domain.Client)
 	query := `
 		UPDATE clients
 		SET name = $1, slug = $2, max_users = $3, active = $4, metadata = $5,
-		    keycloak_group_id = $6, updated_at = $7
-		WHERE id = $8
+		    keycloak_group_id = $6, updated_at = $7, deleted_at = $8
+		WHERE id = $9
 	`
 
 	_, err = r.db.ExecContext(ctx, query,
@@ -192,6 +173,7 @@ func (r *PostgresRepository) Update(ctx context.Context, client *domain.Client)
 		metadataJSON,
 		client.KeycloakGroupID,
 		client.UpdatedAt,
+		client.DeletedAt,
 		client.ID,
 	)
 
@@ -202,8 +184,13 @@ func (r *PostgresRepository) Update(ctx context.Context, client *domain.Client)
 	return nil
 }
 
+// Delete marca o client como removido; monitoramentos e usuários ficam retidos até o purge
 func (r *PostgresRepository) Delete(ctx context.Context, id string) error {
-	query := "DELETE FROM clients WHERE id = $1"
+	query := `
+		UPDATE clients
+		SET active = false, deleted_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL
+	`
 	_, err := r.db.ExecContext(ctx, query, id)
 	if err != nil {
 		return fmt.Errorf("erro ao deletar client: %w", err)
@@ -211,10 +198,26 @@ func (r *PostgresRepository) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
+// PurgeDeleted remove definitivamente (ON DELETE CASCADE) os clients com carência expirada
+func (r *PostgresRepository) PurgeDeleted(ctx context.Context, deletedBefore time.Time) (int, error) {
+	query := "DELETE FROM clients WHERE deleted_at IS NOT NULL AND deleted_at < $1"
+	result, err := r.db.ExecContext(ctx, query, deletedBefore)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao remover clients expirados: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("erro ao contar clients removidos: %w", err)
+	}
+
+	return int(affected), nil
+}
+
 func (r *PostgresRepository) GetStats(ctx context.Context, clientID string) (*domain.ClientStats, error) {
 	query := `
-		SELECT id, name, slug, max_users, current_users, available_slots,
-		       total_monitoramentos, total_areas, active, created_at
+		SELECT id, name, slug, max_users, current_users, admin_users, inactive_users,
+		       pending_invites, available_slots, total_monitoramentos, total_areas, active, created_at
 		FROM client_stats
 		WHERE id = $1
 	`
@@ -222,18 +225,24 @@ func (r *PostgresRepository) GetStats(ctx context.Context, clientID string) (*do
 	var stats domain.ClientStats
 	var metadata = make(map[string]interface{})
 
-	err := r.db.QueryRowContext(ctx, query, clientID).Scan(
-		&stats.ID,
-		&stats.Name,
-		&stats.Slug,
-		&stats.MaxUsers,
-		&stats.CurrentUsers,
-		&stats.AvailableSlots,
-		&stats.TotalMonitoramentos,
-		&stats.TotalAreas,
-		&stats.Active,
-		&stats.CreatedAt,
-	)
+	// A view agrega tabelas com RLS: as contagens só enxergam o tenant da transação
+	err := r.tenantDB.InTenant(ctx, clientID, func(tx *sql.Tx) error {
+		return tx.QueryRowContext(ctx, query, clientID).Scan(
+			&stats.ID,
+			&stats.Name,
+			&stats.Slug,
+			&stats.MaxUsers,
+			&stats.CurrentUsers,
+			&stats.AdminUsers,
+			&stats.InactiveUsers,
+			&stats.PendingInvites,
+			&stats.AvailableSlots,
+			&stats.TotalMonitoramentos,
+			&stats.TotalAreas,
+			&stats.Active,
+			&stats.CreatedAt,
+		)
+	})
 
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -246,3 +255,77 @@ func (r *PostgresRepository) GetStats(ctx context.Context, clientID string) (*do
 
 	return &stats, nil
 }
+
+// SaveStatsSnapshot grava o snapshot na transação do tenant (client_stats_daily tem RLS)
+func (r *PostgresRepository) SaveStatsSnapshot(ctx context.Context, snapshot *domain.ClientStatsSnapshot) error {
+	query := `
+		INSERT INTO client_stats_daily (
+			client_id, day, current_users, admin_users, inactive_users, pending_invites,
+			total_monitoramentos, total_areas, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW())
+		ON CONFLICT (client_id, day) DO UPDATE SET
+			current_users = EXCLUDED.current_users,
+			admin_users = EXCLUDED.admin_users,
+			inactive_users = EXCLUDED.inactive_users,
+			pending_invites = EXCLUDED.pending_invites,
+			total_monitoramentos = EXCLUDED.total_monitoramentos,
+			total_areas = EXCLUDED.total_areas,
+			created_at = NOW()
+	`
+
+	return r.tenantDB.InTenant(ctx, snapshot.ClientID, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, query,
+			snapshot.ClientID,
+			snapshot.Day,
+			snapshot.CurrentUsers,
+			snapshot.AdminUsers,
+			snapshot.InactiveUsers,
+			snapshot.PendingInvites,
+			snapshot.TotalMonitoramentos,
+			snapshot.TotalAreas,
+		)
+		return err
+	})
+}
+
+func (r *PostgresRepository) ListStatsSnapshots(ctx context.Context, clientID string, since time.Time) ([]*domain.ClientStatsSnapshot, error) {
+	query := `
+		SELECT client_id, day, current_users, admin_users, inactive_users, pending_invites,
+		       total_monitoramentos, total_areas
+		FROM client_stats_daily
+		WHERE client_id = $1 AND day >= $2
+		ORDER BY day
+	`
+
+	result := make([]*domain.ClientStatsSnapshot, 0)
+	err := r.tenantDB.InTenant(ctx, clientID, func(tx *sql.Tx) error {
+		rows, err := tx.QueryContext(ctx, query, clientID, since)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var s domain.ClientStatsSnapshot
+			if err := rows.Scan(
+				&s.ClientID,
+				&s.Day,
+				&s.CurrentUsers,
+				&s.AdminUsers,
+				&s.InactiveUsers,
+				&s.PendingInvites,
+				&s.TotalMonitoramentos,
+				&s.TotalAreas,
+			); err != nil {
+				return err
+			}
+			result = append(result, &s)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar histórico de estatísticas: %w", err)
+	}
+
+	return result, nil
+}
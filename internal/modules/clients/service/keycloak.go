@@ -2,7 +2,9 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 
 	"agro-monitoring/internal/config"
 	"github.com/Nerzal/gocloak/v13"
@@ -11,9 +13,14 @@ import (
 // KeycloakService define operações de integração com Keycloak Admin API
 type KeycloakService interface {
 	CreateGroup(ctx context.Context, name string, attrs map[string][]string) (string, error)
+	SetGroupAttribute(ctx context.Context, groupID, key, value string) error
 	CreateUser(ctx context.Context, user KeycloakUser) (string, error)
 	AddUserToGroup(ctx context.Context, userID, groupID string) error
+	RemoveUserFromGroup(ctx context.Context, userID, groupID string) error
+	SetSubgroupMembership(ctx context.Context, userID, parentGroupID, name string, member bool) error
 	SetUserAttribute(ctx context.Context, userID, key, value string) error
+	SetUserEnabled(ctx context.Context, userID string, enabled bool) error
+	DeleteUser(ctx context.Context, userID string) error
 }
 
 // KeycloakUser representa dados para criar usuário no Keycloak
@@ -73,6 +80,33 @@ func (s *keycloakService) CreateGroup(ctx context.Context, name string, attrs ma
 	return groupID, nil
 }
 
+// SetGroupAttribute seta um atributo customizado no grupo (ex.: max_users do client)
+func (s *keycloakService) SetGroupAttribute(ctx context.Context, groupID, key, value string) error {
+	token, err := s.getToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	group, err := s.client.GetGroup(ctx, token, s.realm, groupID)
+	if err != nil {
+		return fmt.Errorf("erro ao buscar grupo: %w", err)
+	}
+
+	if group.Attributes == nil {
+		attrs := make(map[string][]string)
+		group.Attributes = &attrs
+	}
+
+	(*group.Attributes)[key] = []string{value}
+
+	err = s.client.UpdateGroup(ctx, token, s.realm, *group)
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar atributo do grupo: %w", err)
+	}
+
+	return nil
+}
+
 // CreateUser cria um usuário no Keycloak
 func (s *keycloakService) CreateUser(ctx context.Context, user KeycloakUser) (string, error) {
 	token, err := s.getToken(ctx)
@@ -120,6 +154,65 @@ func (s *keycloakService) AddUserToGroup(ctx context.Context, userID, groupID st
 	return nil
 }
 
+// RemoveUserFromGroup remove um usuário de um grupo
+func (s *keycloakService) RemoveUserFromGroup(ctx context.Context, userID, groupID string) error {
+	token, err := s.getToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = s.client.DeleteUserFromGroup(ctx, token, s.realm, userID, groupID)
+	if err != nil {
+		return fmt.Errorf("erro ao remover usuário do grupo: %w", err)
+	}
+
+	return nil
+}
+
+// SetSubgroupMembership coloca ou tira o usuário do subgrupo name do grupo pai (ex.: admin do client),
+// criando o subgrupo na primeira vez que alguém entra nele
+func (s *keycloakService) SetSubgroupMembership(ctx context.Context, userID, parentGroupID, name string, member bool) error {
+	token, err := s.getToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	parent, err := s.client.GetGroup(ctx, token, s.realm, parentGroupID)
+	if err != nil {
+		return fmt.Errorf("erro ao buscar grupo: %w", err)
+	}
+
+	var subgroupID string
+	subgroup, err := s.client.GetGroupByPath(ctx, token, s.realm, gocloak.PString(parent.Path)+"/"+name)
+	var apiErr *gocloak.APIError
+	switch {
+	case err == nil:
+		subgroupID = gocloak.PString(subgroup.ID)
+	case errors.As(err, &apiErr) && apiErr.Code == http.StatusNotFound:
+		// Subgrupo inexistente: ninguém está nele, então só precisa ser criado para adicionar
+		if !member {
+			return nil
+		}
+		subgroupID, err = s.client.CreateChildGroup(ctx, token, s.realm, parentGroupID, gocloak.Group{Name: gocloak.StringP(name)})
+		if err != nil {
+			return fmt.Errorf("erro ao criar subgrupo: %w", err)
+		}
+	default:
+		return fmt.Errorf("erro ao buscar subgrupo: %w", err)
+	}
+
+	if member {
+		err = s.client.AddUserToGroup(ctx, token, s.realm, userID, subgroupID)
+	} else {
+		err = s.client.DeleteUserFromGroup(ctx, token, s.realm, userID, subgroupID)
+	}
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar subgrupo do usuário: %w", err)
+	}
+
+	return nil
+}
+
 // SetUserAttribute seta um atributo customizado no usuário
 func (s *keycloakService) SetUserAttribute(ctx context.Context, userID, key, value string) error {
 	token, err := s.getToken(ctx)
@@ -146,3 +239,40 @@ func (s *keycloakService) SetUserAttribute(ctx context.Context, userID, key, val
 
 	return nil
 }
+
+// SetUserEnabled habilita ou desabilita o login de um usuário
+func (s *keycloakService) SetUserEnabled(ctx context.Context, userID string, enabled bool) error {
+	token, err := s.getToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	user, err := s.client.GetUserByID(ctx, token, s.realm, userID)
+	if err != nil {
+		return fmt.Errorf("erro ao buscar usuário: %w", err)
+	}
+
+	user.Enabled = gocloak.BoolP(enabled)
+
+	err = s.client.UpdateUser(ctx, token, s.realm, *user)
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar status do usuário: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteUser remove um usuário do Keycloak
+func (s *keycloakService) DeleteUser(ctx context.Context, userID string) error {
+	token, err := s.getToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = s.client.DeleteUser(ctx, token, s.realm, userID)
+	if err != nil {
+		return fmt.Errorf("erro ao deletar usuário: %w", err)
+	}
+
+	return nil
+}
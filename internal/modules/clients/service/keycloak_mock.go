@@ -9,18 +9,22 @@ import (
 type InMemoryKeycloakService struct {
 	mu         sync.RWMutex
 	groups     map[string]string            // groupID -> name
+	groupAttrs map[string]map[string]string // groupID -> {key: value}
 	users      map[string]KeycloakUser      // userID -> user
 	userGroups map[string][]string          // userID -> []groupID
 	userAttrs  map[string]map[string]string // userID -> {key: value}
+	subgroups  map[string]string            // "parentID/name" -> groupID
 	counter    int
 }
 
 func NewInMemoryKeycloakService() KeycloakService {
 	return &InMemoryKeycloakService{
 		groups:     make(map[string]string),
+		groupAttrs: make(map[string]map[string]string),
 		users:      make(map[string]KeycloakUser),
 		userGroups: make(map[string][]string),
 		userAttrs:  make(map[string]map[string]string),
+		subgroups:  make(map[string]string),
 	}
 }
 
@@ -31,9 +35,35 @@ func (s *InMemoryKeycloakService) CreateGroup(ctx context.Context, name string,
 	s.counter++
 	groupID := fmt.Sprintf("group-%d", s.counter)
 	s.groups[groupID] = name
+	s.groupAttrs[groupID] = make(map[string]string)
+	for key, values := range attrs {
+		if len(values) > 0 {
+			s.groupAttrs[groupID][key] = values[0]
+		}
+	}
 	return groupID, nil
 }
 
+func (s *InMemoryKeycloakService) SetGroupAttribute(ctx context.Context, groupID, key, value string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.groups[groupID]; !ok {
+		return fmt.Errorf("grupo não encontrado: %s", groupID)
+	}
+
+	s.groupAttrs[groupID][key] = value
+	return nil
+}
+
+// GroupAttribute retorna um atributo do grupo (usado em testes)
+func (s *InMemoryKeycloakService) GroupAttribute(groupID, key string) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.groupAttrs[groupID][key]
+}
+
 func (s *InMemoryKeycloakService) CreateUser(ctx context.Context, user KeycloakUser) (string, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -58,6 +88,84 @@ func (s *InMemoryKeycloakService) AddUserToGroup(ctx context.Context, userID, gr
 	return nil
 }
 
+func (s *InMemoryKeycloakService) RemoveUserFromGroup(ctx context.Context, userID, groupID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	groups := s.userGroups[userID][:0]
+	for _, g := range s.userGroups[userID] {
+		if g != groupID {
+			groups = append(groups, g)
+		}
+	}
+	s.userGroups[userID] = groups
+	return nil
+}
+
+func (s *InMemoryKeycloakService) SetSubgroupMembership(ctx context.Context, userID, parentGroupID, name string, member bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.groups[parentGroupID]; !ok {
+		return fmt.Errorf("grupo não encontrado: %s", parentGroupID)
+	}
+
+	key := parentGroupID + "/" + name
+	subgroupID, ok := s.subgroups[key]
+	if !ok {
+		if !member {
+			return nil
+		}
+		s.counter++
+		subgroupID = fmt.Sprintf("group-%d", s.counter)
+		s.groups[subgroupID] = name
+		s.groupAttrs[subgroupID] = make(map[string]string)
+		s.subgroups[key] = subgroupID
+	}
+
+	groups := make([]string, 0, len(s.userGroups[userID])+1)
+	for _, g := range s.userGroups[userID] {
+		if g != subgroupID {
+			groups = append(groups, g)
+		}
+	}
+	if member {
+		groups = append(groups, subgroupID)
+	}
+	s.userGroups[userID] = groups
+	return nil
+}
+
+// IsUserInSubgroup indica se o usuário pertence ao subgrupo name do grupo pai (usado em testes)
+func (s *InMemoryKeycloakService) IsUserInSubgroup(userID, parentGroupID, name string) bool {
+	s.mu.RLock()
+	subgroupID, ok := s.subgroups[parentGroupID+"/"+name]
+	s.mu.RUnlock()
+
+	return ok && s.IsUserInGroup(userID, subgroupID)
+}
+
+// IsUserInGroup indica se o usuário pertence ao grupo (usado em testes)
+func (s *InMemoryKeycloakService) IsUserInGroup(userID, groupID string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, g := range s.userGroups[userID] {
+		if g == groupID {
+			return true
+		}
+	}
+	return false
+}
+
+// UserAttribute retorna um atributo do usuário (usado em testes)
+func (s *InMemoryKeycloakService) UserAttribute(userID, key string) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.userAttrs[userID][key]
+}
+
 func (s *InMemoryKeycloakService) SetUserAttribute(ctx context.Context, userID, key, value string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -70,12 +178,67 @@ func (s *InMemoryKeycloakService) SetUserAttribute(ctx context.Context, userID,
 	return nil
 }
 
+func (s *InMemoryKeycloakService) SetUserEnabled(ctx context.Context, userID string, enabled bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[userID]
+	if !ok {
+		return fmt.Errorf("usuário não encontrado: %s", userID)
+	}
+
+	user.Enabled = enabled
+	s.users[userID] = user
+	return nil
+}
+
+func (s *InMemoryKeycloakService) DeleteUser(ctx context.Context, userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.users[userID]; !ok {
+		return fmt.Errorf("usuário não encontrado: %s", userID)
+	}
+
+	delete(s.users, userID)
+	delete(s.userGroups, userID)
+	delete(s.userAttrs, userID)
+	return nil
+}
+
+// HasUser indica se o usuário existe (usado em testes)
+func (s *InMemoryKeycloakService) HasUser(userID string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	_, ok := s.users[userID]
+	return ok
+}
+
+// UserCount retorna a quantidade de usuários cadastrados (usado em testes)
+func (s *InMemoryKeycloakService) UserCount() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return len(s.users)
+}
+
+// IsUserEnabled retorna o status de login do usuário (usado em testes)
+func (s *InMemoryKeycloakService) IsUserEnabled(userID string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.users[userID].Enabled
+}
+
 func (s *InMemoryKeycloakService) Clear() {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	s.groups = make(map[string]string)
+	s.groupAttrs = make(map[string]map[string]string)
 	s.users = make(map[string]KeycloakUser)
 	s.userGroups = make(map[string][]string)
 	s.userAttrs = make(map[string]map[string]string)
+	s.subgroups = make(map[string]string)
 	s.counter = 0
 }
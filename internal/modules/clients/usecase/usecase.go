@@ -1,14 +1,22 @@
 package usecase
 
 import (
+	"bytes"
 	"context"
+	"crypto/rand"
+	"encoding/csv"
+	"encoding/hex"
 	"fmt"
+	"log"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"agro-monitoring/internal/modules/clients/domain"
 	"agro-monitoring/internal/modules/clients/dto"
 	"agro-monitoring/internal/modules/clients/service"
+	"agro-monitoring/internal/services/storage"
 	sharedErrors "agro-monitoring/internal/shared/errors"
 )
 
@@ -19,31 +27,54 @@ type ClientUseCase interface {
 	GetClientBySlug(ctx context.Context, slug string) (*domain.Client, error)
 	ListClients(ctx context.Context, page, pageSize int) ([]*domain.Client, int, error)
 	GetClientStats(ctx context.Context, clientID string) (*domain.ClientStats, error)
+	UpdateClient(ctx context.Context, id string, req dto.UpdateClientRequest) (*domain.Client, error)
+	DeleteClient(ctx context.Context, id string) (*domain.Client, error)
+	RestoreClient(ctx context.Context, id string) (*domain.Client, error)
+	PurgeDeletedClients(ctx context.Context) (int, error)
+	SnapshotStats(ctx context.Context) (int, error)
+	GetStatsHistory(ctx context.Context, clientID string, days int) ([]*domain.ClientStatsSnapshot, error)
+	GenerateStatsReports(ctx context.Context, days int) (int, error)
 
 	RegisterUser(ctx context.Context, slug string, req dto.RegisterUserRequest) (*domain.ClientUser, error)
 	CheckUserLimit(ctx context.Context, clientID string) (bool, error)
 	ListClientUsers(ctx context.Context, clientID string, page, pageSize int) ([]*domain.ClientUser, int, error)
+	GetTenantRole(ctx context.Context, clientID, userID string) (string, error)
+
+	InviteUser(ctx context.Context, clientID, invitedBy string, req dto.InviteUserRequest) (*domain.ClientInvite, error)
+	ListPendingInvites(ctx context.Context, clientID string, page, pageSize int) ([]*domain.ClientInvite, int, error)
+	ChangeUserRole(ctx context.Context, clientID, actorUserID, memberID, role string) (*domain.ClientUser, error)
+	DeactivateUser(ctx context.Context, clientID, actorUserID, memberID string) (*domain.ClientUser, error)
+	ReactivateUser(ctx context.Context, clientID, actorUserID, memberID string) (*domain.ClientUser, error)
 }
 
+// adminSubgroup é o subgrupo do grupo do client no Keycloak que reúne os admins do tenant
+const adminSubgroup = domain.ClientUserRoleAdmin
+
 type clientUseCase struct {
 	clientRepo     domain.ClientRepository
 	clientUserRepo domain.ClientUserRepository
+	inviteRepo     domain.ClientInviteRepository
 	keycloakSvc    service.KeycloakService
 	uuidGen        func() string
+	fileStorage    storage.Service
 }
 
 // NewClientUseCase cria uma nova instância de ClientUseCase
 func NewClientUseCase(
 	clientRepo domain.ClientRepository,
 	clientUserRepo domain.ClientUserRepository,
+	inviteRepo domain.ClientInviteRepository,
 	keycloakSvc service.KeycloakService,
 	uuidGen func() string,
+	fileStorage storage.Service,
 ) ClientUseCase {
 	return &clientUseCase{
 		clientRepo:     clientRepo,
 		clientUserRepo: clientUserRepo,
+		inviteRepo:     inviteRepo,
 		keycloakSvc:    keycloakSvc,
 		uuidGen:        uuidGen,
+		fileStorage:    fileStorage,
 	}
 }
 
@@ -128,6 +159,259 @@ func (uc *clientUseCase) GetClientStats(ctx context.Context, clientID string) (*
 	return stats, nil
 }
 
+// UpdateClient aplica alterações parciais; desativar o client desabilita seus usuários no Keycloak
+func (uc *clientUseCase) UpdateClient(ctx context.Context, id string, req dto.UpdateClientRequest) (*domain.Client, error) {
+	client, err := uc.clientRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar client: %w", err)
+	}
+	if client == nil || client.IsDeleted() {
+		return nil, sharedErrors.ErrClientNotFound
+	}
+
+	if req.Name != nil {
+		client.Name = *req.Name
+	}
+
+	if req.MaxUsers != nil {
+		if *req.MaxUsers <= 0 {
+			return nil, sharedErrors.ErrInvalidMaxUsers
+		}
+
+		currentUsers, err := uc.clientUserRepo.CountActiveByClient(ctx, client.ID)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao contar usuários: %w", err)
+		}
+		if *req.MaxUsers < currentUsers {
+			return nil, sharedErrors.ErrMaxUsersBelowActive
+		}
+
+		client.MaxUsers = *req.MaxUsers
+	}
+
+	if req.Active != nil {
+		client.Active = *req.Active
+	}
+
+	if req.Metadata != nil {
+		client.Metadata = req.Metadata
+	}
+
+	client.UpdatedAt = time.Now()
+
+	if err := uc.clientRepo.Update(ctx, client); err != nil {
+		return nil, fmt.Errorf("erro ao atualizar client: %w", err)
+	}
+
+	// Sincroniza sempre que max_users/active é informado, para que uma nova chamada corrija falhas parciais
+	if req.MaxUsers != nil {
+		if err := uc.keycloakSvc.SetGroupAttribute(ctx, client.KeycloakGroupID, "max_users", fmt.Sprintf("%d", client.MaxUsers)); err != nil {
+			return nil, fmt.Errorf("erro ao atualizar max_users no Keycloak: %w", err)
+		}
+	}
+
+	if req.Active != nil {
+		if err := uc.setMembersEnabled(ctx, client.ID, *req.Active); err != nil {
+			return nil, err
+		}
+	}
+
+	return client, nil
+}
+
+// DeleteClient faz soft-delete do client e desabilita seus usuários no Keycloak.
+// Os dados ficam retidos por domain.ClientDeletionGracePeriod.
+func (uc *clientUseCase) DeleteClient(ctx context.Context, id string) (*domain.Client, error) {
+	client, err := uc.clientRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar client: %w", err)
+	}
+	if client == nil || client.IsDeleted() {
+		return nil, sharedErrors.ErrClientNotFound
+	}
+
+	if err := uc.clientRepo.Delete(ctx, client.ID); err != nil {
+		return nil, err
+	}
+
+	if err := uc.setMembersEnabled(ctx, client.ID, false); err != nil {
+		return nil, err
+	}
+
+	return uc.clientRepo.GetByID(ctx, client.ID)
+}
+
+// RestoreClient desfaz o soft-delete dentro da carência e reabilita os usuários ativos
+func (uc *clientUseCase) RestoreClient(ctx context.Context, id string) (*domain.Client, error) {
+	client, err := uc.clientRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar client: %w", err)
+	}
+	if client == nil {
+		return nil, sharedErrors.ErrClientNotFound
+	}
+	if !client.IsDeleted() {
+		return client, nil
+	}
+
+	client.Restore()
+
+	if err := uc.clientRepo.Update(ctx, client); err != nil {
+		return nil, fmt.Errorf("erro ao restaurar client: %w", err)
+	}
+
+	if err := uc.setMembersEnabled(ctx, client.ID, true); err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+// PurgeDeletedClients apaga definitivamente os clients cuja carência expirou
+func (uc *clientUseCase) PurgeDeletedClients(ctx context.Context) (int, error) {
+	return uc.clientRepo.PurgeDeleted(ctx, time.Now().Add(-domain.ClientDeletionGracePeriod))
+}
+
+// SnapshotStats grava as estatísticas do dia de todos os clients não removidos
+func (uc *clientUseCase) SnapshotStats(ctx context.Context) (int, error) {
+	const pageSize = 100
+	today := time.Now().UTC()
+
+	saved := 0
+	for offset := 0; ; offset += pageSize {
+		clients, total, err := uc.clientRepo.List(ctx, pageSize, offset)
+		if err != nil {
+			return saved, fmt.Errorf("erro ao listar clients: %w", err)
+		}
+
+		for _, client := range clients {
+			stats, err := uc.clientRepo.GetStats(ctx, client.ID)
+			if err != nil {
+				return saved, err
+			}
+			if stats == nil {
+				continue // removido entre a listagem e a consulta
+			}
+			if err := uc.clientRepo.SaveStatsSnapshot(ctx, domain.NewClientStatsSnapshot(stats, today)); err != nil {
+				return saved, fmt.Errorf("erro ao gravar estatísticas do client %s: %w", client.ID, err)
+			}
+			saved++
+		}
+
+		if offset+pageSize >= total {
+			return saved, nil
+		}
+	}
+}
+
+// GetStatsHistory retorna as estatísticas diárias dos últimos days dias (padrão 30, máximo 366)
+func (uc *clientUseCase) GetStatsHistory(ctx context.Context, clientID string, days int) ([]*domain.ClientStatsSnapshot, error) {
+	client, err := uc.clientRepo.GetByID(ctx, clientID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar client: %w", err)
+	}
+	if client == nil {
+		return nil, sharedErrors.ErrClientNotFound
+	}
+
+	if days < 1 {
+		days = 30
+	}
+	if days > 366 {
+		days = 366
+	}
+
+	today := time.Now().UTC()
+	since := time.Date(today.Year(), today.Month(), today.Day()-days+1, 0, 0, 0, 0, time.UTC)
+	return uc.clientRepo.ListStatsSnapshots(ctx, clientID, since)
+}
+
+// GenerateStatsReports grava para cada client não removido o relatório CSV das estatísticas
+// diárias dos últimos days dias em "<client_id>/relatorios/estatisticas-<dia>.csv"
+func (uc *clientUseCase) GenerateStatsReports(ctx context.Context, days int) (int, error) {
+	const pageSize = 100
+	today := time.Now().UTC().Format("2006-01-02")
+
+	generated := 0
+	for offset := 0; ; offset += pageSize {
+		clients, total, err := uc.clientRepo.List(ctx, pageSize, offset)
+		if err != nil {
+			return generated, fmt.Errorf("erro ao listar clients: %w", err)
+		}
+
+		for _, client := range clients {
+			history, err := uc.GetStatsHistory(ctx, client.ID, days)
+			if err == sharedErrors.ErrClientNotFound {
+				continue // removido entre a listagem e a consulta
+			}
+			if err != nil {
+				return generated, err
+			}
+
+			var buf bytes.Buffer
+			if err := writeStatsReport(&buf, history); err != nil {
+				return generated, err
+			}
+			key := storage.Key(client.ID, "relatorios", "estatisticas-"+today+".csv")
+			if err := uc.fileStorage.Put(ctx, key, &buf, int64(buf.Len())); err != nil {
+				return generated, fmt.Errorf("erro ao gravar relatório do client %s: %w", client.ID, err)
+			}
+			generated++
+		}
+
+		if offset+pageSize >= total {
+			return generated, nil
+		}
+	}
+}
+
+// writeStatsReport gera o CSV (separador ";", UTF-8 com BOM para o Excel) com um dia por linha
+func writeStatsReport(buf *bytes.Buffer, history []*domain.ClientStatsSnapshot) error {
+	buf.WriteString("\xef\xbb\xbf")
+	writer := csv.NewWriter(buf)
+	writer.Comma = ';'
+
+	writer.Write([]string{"Dia", "Usuários", "Administradores", "Inativos", "Convites pendentes", "Monitoramentos", "Áreas"})
+	for _, s := range history {
+		writer.Write([]string{
+			s.Day.Format("2006-01-02"),
+			strconv.Itoa(s.CurrentUsers),
+			strconv.Itoa(s.AdminUsers),
+			strconv.Itoa(s.InactiveUsers),
+			strconv.Itoa(s.PendingInvites),
+			strconv.Itoa(s.TotalMonitoramentos),
+			strconv.Itoa(s.TotalAreas),
+		})
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// setMembersEnabled habilita/desabilita no Keycloak todos os usuários ativos do client
+func (uc *clientUseCase) setMembersEnabled(ctx context.Context, clientID string, enabled bool) error {
+	const pageSize = 100
+
+	for offset := 0; ; offset += pageSize {
+		users, total, err := uc.clientUserRepo.ListByClient(ctx, clientID, pageSize, offset)
+		if err != nil {
+			return fmt.Errorf("erro ao listar usuários do client: %w", err)
+		}
+
+		for _, cu := range users {
+			if !cu.Active {
+				continue
+			}
+			if err := uc.keycloakSvc.SetUserEnabled(ctx, cu.UserID, enabled); err != nil {
+				return fmt.Errorf("erro ao atualizar usuário %s no Keycloak: %w", cu.UserID, err)
+			}
+		}
+
+		if offset+pageSize >= total {
+			return nil
+		}
+	}
+}
+
 func (uc *clientUseCase) RegisterUser(ctx context.Context, slug string, req dto.RegisterUserRequest) (*domain.ClientUser, error) {
 	// 1. Buscar client por slug
 	client, err := uc.clientRepo.GetBySlug(ctx, slug)
@@ -143,18 +427,30 @@ func (uc *clientUseCase) RegisterUser(ctx context.Context, slug string, req dto.
 		return nil, sharedErrors.ErrClientInactive
 	}
 
-	// 3. Contar usuários ativos
+	// Convite (opcional) define o papel e precisa ser do mesmo e-mail
+	role := domain.ClientUserRoleUser
+	var invite *domain.ClientInvite
+	if req.InviteToken != "" {
+		invite, err = uc.inviteRepo.GetByToken(ctx, client.ID, req.InviteToken)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao buscar convite: %w", err)
+		}
+		if invite == nil || !invite.IsPending() || !strings.EqualFold(invite.Email, req.Email) {
+			return nil, sharedErrors.ErrInvalidInvite
+		}
+		role = invite.Role
+	}
+
+	// 3. Pré-checagem do limite (evita criar usuário no Keycloak à toa; a garantia vem do passo 7)
 	currentUsers, err := uc.clientUserRepo.CountActiveByClient(ctx, client.ID)
 	if err != nil {
 		return nil, fmt.Errorf("erro ao contar usuários: %w", err)
 	}
-
-	// 4. Validar limite
 	if currentUsers >= client.MaxUsers {
 		return nil, sharedErrors.ErrClientUserLimitReached
 	}
 
-	// 5. Criar usuário no Keycloak
+	// 4. Criar usuário no Keycloak
 	username := strings.Split(req.Email, "@")[0] // username = parte antes do @
 	kcUser := service.KeycloakUser{
 		Username:  username,
@@ -170,27 +466,65 @@ func (uc *clientUseCase) RegisterUser(ctx context.Context, slug string, req dto.
 		return nil, fmt.Errorf("erro ao criar usuário no Keycloak: %w", err)
 	}
 
-	// 6. Setar atributo client_id no usuário
+	// A partir daqui qualquer falha precisa desfazer o usuário criado no Keycloak
+
+	// 5. Setar atributo client_id no usuário
 	if err := uc.keycloakSvc.SetUserAttribute(ctx, userID, "client_id", client.ID); err != nil {
+		uc.compensateKeycloakUser(ctx, userID)
 		return nil, fmt.Errorf("erro ao setar atributo client_id: %w", err)
 	}
+	if err := uc.keycloakSvc.SetUserAttribute(ctx, userID, "client_role", role); err != nil {
+		uc.compensateKeycloakUser(ctx, userID)
+		return nil, fmt.Errorf("erro ao setar atributo client_role: %w", err)
+	}
 
-	// 7. Adicionar ao grupo do client
+	// 6. Adicionar ao grupo do client
 	if err := uc.keycloakSvc.AddUserToGroup(ctx, userID, client.KeycloakGroupID); err != nil {
+		uc.compensateKeycloakUser(ctx, userID)
 		return nil, fmt.Errorf("erro ao adicionar ao grupo: %w", err)
 	}
+	if role == domain.ClientUserRoleAdmin {
+		if err := uc.keycloakSvc.SetSubgroupMembership(ctx, userID, client.KeycloakGroupID, adminSubgroup, true); err != nil {
+			uc.compensateKeycloakUser(ctx, userID)
+			return nil, fmt.Errorf("erro ao adicionar ao grupo do papel: %w", err)
+		}
+	}
 
-	// 8. Salvar em client_users
-	clientUser := domain.NewClientUser(uc.uuidGen(), client.ID, userID, req.Email, "user")
+	// 7. Reservar a vaga e salvar em client_users de forma atômica
+	clientUser := domain.NewClientUser(uc.uuidGen(), client.ID, userID, req.Email, role)
 
-	if err := uc.clientUserRepo.Create(ctx, clientUser); err != nil {
-		// TODO: Compensação - tentar deletar do Keycloak
+	if err := uc.clientUserRepo.CreateWithinLimit(ctx, clientUser, client.MaxUsers); err != nil {
+		uc.compensateKeycloakUser(ctx, userID)
+		if err == sharedErrors.ErrClientUserLimitReached {
+			return nil, err
+		}
 		return nil, fmt.Errorf("erro ao salvar client_user: %w", err)
 	}
 
+	// O usuário já existe; falhar aqui só deixaria o convite reutilizável até expirar
+	if invite != nil {
+		if err := uc.inviteRepo.MarkAccepted(ctx, client.ID, invite.ID); err != nil {
+			log.Printf("Erro ao marcar convite %s como aceito: %v", invite.ID, err)
+		}
+	}
+
 	return clientUser, nil
 }
 
+// compensateKeycloakUser desfaz a criação do usuário no Keycloak; se a remoção falhar,
+// desabilita a conta para não deixar um login órfão ativo
+func (uc *clientUseCase) compensateKeycloakUser(ctx context.Context, userID string) {
+	err := uc.keycloakSvc.DeleteUser(ctx, userID)
+	if err == nil {
+		return
+	}
+	log.Printf("Erro ao remover usuário %s do Keycloak na compensação: %v", userID, err)
+
+	if err := uc.keycloakSvc.SetUserEnabled(ctx, userID, false); err != nil {
+		log.Printf("Erro ao desabilitar usuário órfão %s no Keycloak: %v", userID, err)
+	}
+}
+
 func (uc *clientUseCase) CheckUserLimit(ctx context.Context, clientID string) (bool, error) {
 	client, err := uc.clientRepo.GetByID(ctx, clientID)
 	if err != nil {
@@ -213,6 +547,197 @@ func (uc *clientUseCase) ListClientUsers(ctx context.Context, clientID string, p
 	return uc.clientUserRepo.ListByClient(ctx, clientID, pageSize, offset)
 }
 
+// GetTenantRole retorna o papel do usuário no client ("" se não for membro ativo)
+func (uc *clientUseCase) GetTenantRole(ctx context.Context, clientID, userID string) (string, error) {
+	cu, err := uc.clientUserRepo.GetByClientAndUserID(ctx, clientID, userID)
+	if err != nil {
+		return "", err
+	}
+	if cu == nil || !cu.Active {
+		return "", nil
+	}
+	return cu.Role, nil
+}
+
+// InviteUser cria um convite pendente; o token é consumido em /v1/register/{slug}
+func (uc *clientUseCase) InviteUser(ctx context.Context, clientID, invitedBy string, req dto.InviteUserRequest) (*domain.ClientInvite, error) {
+	role := req.Role
+	if role == "" {
+		role = domain.ClientUserRoleUser
+	}
+	if !domain.IsValidClientUserRole(role) {
+		return nil, sharedErrors.ErrInvalidRole
+	}
+
+	client, err := uc.GetClient(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+	if !client.Active {
+		return nil, sharedErrors.ErrClientInactive
+	}
+
+	currentUsers, err := uc.clientUserRepo.CountActiveByClient(ctx, client.ID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao contar usuários: %w", err)
+	}
+	if currentUsers >= client.MaxUsers {
+		return nil, sharedErrors.ErrClientUserLimitReached
+	}
+
+	token, err := generateInviteToken()
+	if err != nil {
+		return nil, err
+	}
+
+	invite := domain.NewClientInvite(uc.uuidGen(), client.ID, strings.ToLower(req.Email), role, token, invitedBy)
+	if err := uc.inviteRepo.Create(ctx, invite); err != nil {
+		return nil, err
+	}
+
+	return invite, nil
+}
+
+func (uc *clientUseCase) ListPendingInvites(ctx context.Context, clientID string, page, pageSize int) ([]*domain.ClientInvite, int, error) {
+	offset := (page - 1) * pageSize
+	return uc.inviteRepo.ListPendingByClient(ctx, clientID, pageSize, offset)
+}
+
+// ChangeUserRole promove/rebaixa um membro e espelha o papel no subgrupo admin e no atributo client_role do Keycloak
+func (uc *clientUseCase) ChangeUserRole(ctx context.Context, clientID, actorUserID, memberID, role string) (*domain.ClientUser, error) {
+	if !domain.IsValidClientUserRole(role) {
+		return nil, sharedErrors.ErrInvalidRole
+	}
+
+	member, err := uc.getManagedMember(ctx, clientID, actorUserID, memberID)
+	if err != nil {
+		return nil, err
+	}
+	previousRole := member.Role
+
+	client, err := uc.GetClient(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.clientUserRepo.UpdateRole(ctx, clientID, member.ID, role); err != nil {
+		return nil, err
+	}
+
+	// Falha no Keycloak desfaz o papel no banco para os dois lados não divergirem
+	if err := uc.keycloakSvc.SetSubgroupMembership(ctx, member.UserID, client.KeycloakGroupID, adminSubgroup, role == domain.ClientUserRoleAdmin); err != nil {
+		uc.restoreUserRole(ctx, clientID, member.ID, previousRole)
+		return nil, fmt.Errorf("erro ao atualizar grupo do papel no Keycloak: %w", err)
+	}
+	if err := uc.keycloakSvc.SetUserAttribute(ctx, member.UserID, "client_role", role); err != nil {
+		uc.restoreUserRole(ctx, clientID, member.ID, previousRole)
+		if err := uc.keycloakSvc.SetSubgroupMembership(ctx, member.UserID, client.KeycloakGroupID, adminSubgroup, previousRole == domain.ClientUserRoleAdmin); err != nil {
+			log.Printf("Erro ao restaurar grupo do papel do usuário %s no Keycloak: %v", member.UserID, err)
+		}
+		return nil, fmt.Errorf("erro ao atualizar papel no Keycloak: %w", err)
+	}
+
+	member.Role = role
+	return member, nil
+}
+
+// restoreUserRole devolve o papel anterior no banco após falha no Keycloak
+func (uc *clientUseCase) restoreUserRole(ctx context.Context, clientID, memberID, role string) {
+	if err := uc.clientUserRepo.UpdateRole(ctx, clientID, memberID, role); err != nil {
+		log.Printf("Erro ao restaurar papel do membro %s na compensação: %v", memberID, err)
+	}
+}
+
+// DeactivateUser desativa o membro, desabilita o login e remove do grupo do client no Keycloak
+func (uc *clientUseCase) DeactivateUser(ctx context.Context, clientID, actorUserID, memberID string) (*domain.ClientUser, error) {
+	client, err := uc.GetClient(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	member, err := uc.getManagedMember(ctx, clientID, actorUserID, memberID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.clientUserRepo.Deactivate(ctx, clientID, member.ID); err != nil {
+		return nil, err
+	}
+
+	// Sincroniza mesmo se já estava inativo, para que uma nova chamada corrija falhas parciais
+	if err := uc.keycloakSvc.SetUserEnabled(ctx, member.UserID, false); err != nil {
+		return nil, fmt.Errorf("erro ao desabilitar usuário no Keycloak: %w", err)
+	}
+	if err := uc.keycloakSvc.RemoveUserFromGroup(ctx, member.UserID, client.KeycloakGroupID); err != nil {
+		return nil, fmt.Errorf("erro ao remover usuário do grupo: %w", err)
+	}
+	if err := uc.keycloakSvc.SetSubgroupMembership(ctx, member.UserID, client.KeycloakGroupID, adminSubgroup, false); err != nil {
+		return nil, fmt.Errorf("erro ao remover usuário do grupo do papel: %w", err)
+	}
+
+	member.Active = false
+	return member, nil
+}
+
+// ReactivateUser reativa o membro respeitando max_users e o devolve ao grupo do client no Keycloak
+func (uc *clientUseCase) ReactivateUser(ctx context.Context, clientID, actorUserID, memberID string) (*domain.ClientUser, error) {
+	client, err := uc.GetClient(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+	if !client.Active {
+		return nil, sharedErrors.ErrClientInactive
+	}
+
+	member, err := uc.getManagedMember(ctx, clientID, actorUserID, memberID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !member.Active {
+		if err := uc.clientUserRepo.ReactivateWithinLimit(ctx, clientID, member.ID, client.MaxUsers); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := uc.keycloakSvc.AddUserToGroup(ctx, member.UserID, client.KeycloakGroupID); err != nil {
+		return nil, fmt.Errorf("erro ao adicionar ao grupo: %w", err)
+	}
+	if err := uc.keycloakSvc.SetSubgroupMembership(ctx, member.UserID, client.KeycloakGroupID, adminSubgroup, member.Role == domain.ClientUserRoleAdmin); err != nil {
+		return nil, fmt.Errorf("erro ao atualizar grupo do papel: %w", err)
+	}
+	if err := uc.keycloakSvc.SetUserEnabled(ctx, member.UserID, true); err != nil {
+		return nil, fmt.Errorf("erro ao habilitar usuário no Keycloak: %w", err)
+	}
+
+	member.Active = true
+	return member, nil
+}
+
+// getManagedMember busca um membro do client que não seja o próprio usuário da requisição
+func (uc *clientUseCase) getManagedMember(ctx context.Context, clientID, actorUserID, memberID string) (*domain.ClientUser, error) {
+	member, err := uc.clientUserRepo.GetByID(ctx, clientID, memberID)
+	if err != nil {
+		return nil, err
+	}
+	if member == nil {
+		return nil, sharedErrors.ErrClientUserNotFound
+	}
+	if member.UserID == actorUserID {
+		return nil, sharedErrors.ErrCannotModifySelf
+	}
+	return member, nil
+}
+
+// generateInviteToken gera um token aleatório para o link de convite
+func generateInviteToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("erro ao gerar token de convite: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
 // validateSlug valida o formato do slug
 func validateSlug(slug string) error {
 	if len(slug) < 3 || len(slug) > 100 {
@@ -2,8 +2,13 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -12,6 +17,7 @@ import (
 	"agro-monitoring/internal/modules/clients/dto"
 	"agro-monitoring/internal/modules/clients/repository"
 	"agro-monitoring/internal/modules/clients/service"
+	"agro-monitoring/internal/services/storage"
 	sharedErrors "agro-monitoring/internal/shared/errors"
 )
 
@@ -29,10 +35,60 @@ func setupClientTest() (ClientUseCase, domain.ClientRepository, domain.ClientUse
 	keycloakSvc := service.NewInMemoryKeycloakService()
 	uuidGen := mockUUID()
 
-	uc := NewClientUseCase(clientRepo, clientUserRepo, keycloakSvc, uuidGen)
+	uc := NewClientUseCase(clientRepo, clientUserRepo, repository.NewInMemoryClientInviteRepository(), keycloakSvc, uuidGen, nil)
 	return uc, clientRepo, clientUserRepo, keycloakSvc
 }
 
+// failingKeycloak injeta falha em um passo específico do registro
+type failingKeycloak struct {
+	service.KeycloakService
+	failOn    string
+	deleteErr error
+}
+
+func (f *failingKeycloak) SetUserAttribute(ctx context.Context, userID, key, value string) error {
+	if f.failOn == "SetUserAttribute" {
+		return errors.New("keycloak indisponível")
+	}
+	return f.KeycloakService.SetUserAttribute(ctx, userID, key, value)
+}
+
+func (f *failingKeycloak) AddUserToGroup(ctx context.Context, userID, groupID string) error {
+	if f.failOn == "AddUserToGroup" {
+		return errors.New("keycloak indisponível")
+	}
+	return f.KeycloakService.AddUserToGroup(ctx, userID, groupID)
+}
+
+func (f *failingKeycloak) SetSubgroupMembership(ctx context.Context, userID, parentGroupID, name string, member bool) error {
+	if f.failOn == "SetSubgroupMembership" {
+		return errors.New("keycloak indisponível")
+	}
+	return f.KeycloakService.SetSubgroupMembership(ctx, userID, parentGroupID, name, member)
+}
+
+func (f *failingKeycloak) DeleteUser(ctx context.Context, userID string) error {
+	if f.deleteErr != nil {
+		return f.deleteErr
+	}
+	return f.KeycloakService.DeleteUser(ctx, userID)
+}
+
+// failingClientUserRepo injeta falha no insert de client_users
+type failingClientUserRepo struct {
+	domain.ClientUserRepository
+}
+
+func (f *failingClientUserRepo) CreateWithinLimit(ctx context.Context, cu *domain.ClientUser, maxUsers int) error {
+	return errors.New("conexão perdida")
+}
+
+func setupRegisterFailureTest(kc service.KeycloakService, clientUserRepo domain.ClientUserRepository) ClientUseCase {
+	uc := NewClientUseCase(repository.NewInMemoryRepository(), clientUserRepo, repository.NewInMemoryClientInviteRepository(), kc, mockUUID(), nil)
+	uc.CreateClient(context.Background(), dto.CreateClientRequest{Name: "Test", Slug: "test", MaxUsers: 2})
+	return uc
+}
+
 func TestClientUseCase_CreateClient(t *testing.T) {
 	uc, _, _, _ := setupClientTest()
 
@@ -269,3 +325,456 @@ func TestClientUseCase_ListClientUsers(t *testing.T) {
 	assert.Equal(t, 2, total)
 	assert.Len(t, users, 2)
 }
+
+func TestClientUseCase_GetTenantRole(t *testing.T) {
+	uc, _, clientUserRepo, _ := setupClientTest()
+
+	reqClient := dto.CreateClientRequest{Name: "Test", Slug: "test", MaxUsers: 10}
+	client, _ := uc.CreateClient(context.Background(), reqClient)
+
+	reqUser := dto.RegisterUserRequest{Email: "user1@test.com", Password: "pass", FirstName: "User", LastName: "One"}
+	member, err := uc.RegisterUser(context.Background(), "test", reqUser)
+	require.NoError(t, err)
+
+	role, err := uc.GetTenantRole(context.Background(), client.ID, member.UserID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.ClientUserRoleUser, role)
+
+	// Não membro
+	role, err = uc.GetTenantRole(context.Background(), client.ID, "outro-user")
+	require.NoError(t, err)
+	assert.Empty(t, role)
+
+	// Membro desativado perde o papel
+	clientUserRepo.Deactivate(context.Background(), client.ID, member.ID)
+	role, err = uc.GetTenantRole(context.Background(), client.ID, member.UserID)
+	require.NoError(t, err)
+	assert.Empty(t, role)
+}
+
+func TestClientUseCase_UpdateClient(t *testing.T) {
+	uc, _, _, keycloakSvc := setupClientTest()
+	kc := keycloakSvc.(*service.InMemoryKeycloakService)
+
+	client, _ := uc.CreateClient(context.Background(), dto.CreateClientRequest{Name: "Test", Slug: "test", MaxUsers: 10})
+	assert.Equal(t, "10", kc.GroupAttribute(client.KeycloakGroupID, "max_users"))
+
+	name := "Usina Nova"
+	maxUsers := 5
+	updated, err := uc.UpdateClient(context.Background(), client.ID, dto.UpdateClientRequest{
+		Name:     &name,
+		MaxUsers: &maxUsers,
+		Metadata: map[string]interface{}{"cidade": "Piracicaba"},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "Usina Nova", updated.Name)
+	assert.Equal(t, 5, updated.MaxUsers)
+	assert.Equal(t, "Piracicaba", updated.Metadata["cidade"])
+	assert.Equal(t, "test", updated.Slug)
+	assert.True(t, updated.Active)
+	assert.Equal(t, "5", kc.GroupAttribute(client.KeycloakGroupID, "max_users"))
+}
+
+func TestClientUseCase_UpdateClient_MaxUsersBelowActive(t *testing.T) {
+	uc, _, _, _ := setupClientTest()
+
+	client, _ := uc.CreateClient(context.Background(), dto.CreateClientRequest{Name: "Test", Slug: "test", MaxUsers: 10})
+	for i := 1; i <= 3; i++ {
+		reqUser := dto.RegisterUserRequest{Email: fmt.Sprintf("user%d@test.com", i), Password: "pass"}
+		_, err := uc.RegisterUser(context.Background(), "test", reqUser)
+		require.NoError(t, err)
+	}
+
+	maxUsers := 2
+	_, err := uc.UpdateClient(context.Background(), client.ID, dto.UpdateClientRequest{MaxUsers: &maxUsers})
+	assert.Equal(t, sharedErrors.ErrMaxUsersBelowActive, err)
+
+	maxUsers = 0
+	_, err = uc.UpdateClient(context.Background(), client.ID, dto.UpdateClientRequest{MaxUsers: &maxUsers})
+	assert.Equal(t, sharedErrors.ErrInvalidMaxUsers, err)
+
+	maxUsers = 3
+	updated, err := uc.UpdateClient(context.Background(), client.ID, dto.UpdateClientRequest{MaxUsers: &maxUsers})
+	require.NoError(t, err)
+	assert.Equal(t, 3, updated.MaxUsers)
+}
+
+func TestClientUseCase_UpdateClient_DeactivateDisablesMembers(t *testing.T) {
+	uc, _, _, keycloakSvc := setupClientTest()
+	kc := keycloakSvc.(*service.InMemoryKeycloakService)
+
+	client, _ := uc.CreateClient(context.Background(), dto.CreateClientRequest{Name: "Test", Slug: "test", MaxUsers: 10})
+	member, err := uc.RegisterUser(context.Background(), "test", dto.RegisterUserRequest{Email: "user1@test.com", Password: "pass"})
+	require.NoError(t, err)
+	assert.True(t, kc.IsUserEnabled(member.UserID))
+
+	active := false
+	updated, err := uc.UpdateClient(context.Background(), client.ID, dto.UpdateClientRequest{Active: &active})
+	require.NoError(t, err)
+	assert.False(t, updated.Active)
+	assert.False(t, kc.IsUserEnabled(member.UserID))
+
+	active = true
+	_, err = uc.UpdateClient(context.Background(), client.ID, dto.UpdateClientRequest{Active: &active})
+	require.NoError(t, err)
+	assert.True(t, kc.IsUserEnabled(member.UserID))
+}
+
+func TestClientUseCase_UpdateClient_NotFound(t *testing.T) {
+	uc, _, _, _ := setupClientTest()
+
+	name := "X"
+	_, err := uc.UpdateClient(context.Background(), "nonexistent-id", dto.UpdateClientRequest{Name: &name})
+	assert.Equal(t, sharedErrors.ErrClientNotFound, err)
+}
+
+func TestClientUseCase_DeleteAndRestoreClient(t *testing.T) {
+	uc, clientRepo, _, keycloakSvc := setupClientTest()
+	kc := keycloakSvc.(*service.InMemoryKeycloakService)
+
+	client, _ := uc.CreateClient(context.Background(), dto.CreateClientRequest{Name: "Test", Slug: "test", MaxUsers: 10})
+	member, err := uc.RegisterUser(context.Background(), "test", dto.RegisterUserRequest{Email: "user1@test.com", Password: "pass"})
+	require.NoError(t, err)
+
+	deleted, err := uc.DeleteClient(context.Background(), client.ID)
+	require.NoError(t, err)
+	assert.True(t, deleted.IsDeleted())
+	assert.False(t, deleted.Active)
+	assert.NotNil(t, deleted.PurgeAfter())
+	assert.False(t, kc.IsUserEnabled(member.UserID))
+
+	// Removido some da listagem e não aceita cadastros, mas continua consultável
+	_, total, _ := uc.ListClients(context.Background(), 1, 10)
+	assert.Equal(t, 0, total)
+
+	_, err = uc.RegisterUser(context.Background(), "test", dto.RegisterUserRequest{Email: "user2@test.com", Password: "pass"})
+	assert.Equal(t, sharedErrors.ErrClientInactive, err)
+
+	_, err = uc.DeleteClient(context.Background(), client.ID)
+	assert.Equal(t, sharedErrors.ErrClientNotFound, err)
+
+	// Dentro da carência o purge não apaga nada
+	purged, err := uc.PurgeDeletedClients(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, purged)
+
+	restored, err := uc.RestoreClient(context.Background(), client.ID)
+	require.NoError(t, err)
+	assert.False(t, restored.IsDeleted())
+	assert.True(t, restored.Active)
+	assert.True(t, kc.IsUserEnabled(member.UserID))
+
+	found, _ := clientRepo.GetByID(context.Background(), client.ID)
+	assert.Nil(t, found.DeletedAt)
+}
+
+func TestClientUseCase_PurgeDeletedClients(t *testing.T) {
+	uc, clientRepo, _, _ := setupClientTest()
+
+	client, _ := uc.CreateClient(context.Background(), dto.CreateClientRequest{Name: "Test", Slug: "test", MaxUsers: 10})
+	_, err := uc.DeleteClient(context.Background(), client.ID)
+	require.NoError(t, err)
+
+	// Simula carência expirada
+	stored, _ := clientRepo.GetByID(context.Background(), client.ID)
+	expired := stored.DeletedAt.Add(-domain.ClientDeletionGracePeriod - time.Hour)
+	stored.DeletedAt = &expired
+
+	purged, err := uc.PurgeDeletedClients(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, purged)
+
+	_, err = uc.GetClient(context.Background(), client.ID)
+	assert.Equal(t, sharedErrors.ErrClientNotFound, err)
+}
+
+func TestClientUseCase_GenerateStatsReports(t *testing.T) {
+	files := storage.NewLocalStorage(t.TempDir())
+	uc := NewClientUseCase(repository.NewInMemoryRepository(), repository.NewInMemoryClientUserRepository(), repository.NewInMemoryClientInviteRepository(), service.NewInMemoryKeycloakService(), mockUUID(), files)
+
+	active, _ := uc.CreateClient(context.Background(), dto.CreateClientRequest{Name: "Ativo", Slug: "ativo", MaxUsers: 10})
+	removed, _ := uc.CreateClient(context.Background(), dto.CreateClientRequest{Name: "Removido", Slug: "removido", MaxUsers: 10})
+	_, err := uc.SnapshotStats(context.Background())
+	require.NoError(t, err)
+	_, err = uc.DeleteClient(context.Background(), removed.ID)
+	require.NoError(t, err)
+
+	generated, err := uc.GenerateStatsReports(context.Background(), 7)
+	require.NoError(t, err)
+	assert.Equal(t, 1, generated)
+
+	keys, err := files.List(context.Background(), active.ID+"/relatorios/")
+	require.NoError(t, err)
+	require.Len(t, keys, 1)
+
+	f, err := files.Get(context.Background(), keys[0])
+	require.NoError(t, err)
+	defer f.Close()
+	content, err := io.ReadAll(f)
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(strings.TrimPrefix(string(content), "\xef\xbb\xbf")), "\n")
+	require.Len(t, lines, 2)
+	assert.Equal(t, "Dia;Usuários;Administradores;Inativos;Convites pendentes;Monitoramentos;Áreas", lines[0])
+	assert.True(t, strings.HasPrefix(lines[1], time.Now().UTC().Format("2006-01-02")+";"))
+
+	keys, err = files.List(context.Background(), removed.ID+"/")
+	require.NoError(t, err)
+	assert.Empty(t, keys)
+}
+
+func TestClientUseCase_SnapshotStats(t *testing.T) {
+	uc, _, _, _ := setupClientTest()
+
+	active, _ := uc.CreateClient(context.Background(), dto.CreateClientRequest{Name: "Ativo", Slug: "ativo", MaxUsers: 10})
+	removed, _ := uc.CreateClient(context.Background(), dto.CreateClientRequest{Name: "Removido", Slug: "removido", MaxUsers: 10})
+	_, err := uc.DeleteClient(context.Background(), removed.ID)
+	require.NoError(t, err)
+
+	saved, err := uc.SnapshotStats(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, saved)
+
+	// Rodar de novo no mesmo dia substitui o snapshot
+	_, err = uc.SnapshotStats(context.Background())
+	require.NoError(t, err)
+
+	history, err := uc.GetStatsHistory(context.Background(), active.ID, 7)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, active.ID, history[0].ClientID)
+	today := time.Now().UTC()
+	assert.Equal(t, time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC), history[0].Day)
+
+	history, err = uc.GetStatsHistory(context.Background(), removed.ID, 7)
+	require.NoError(t, err)
+	assert.Empty(t, history)
+
+	_, err = uc.GetStatsHistory(context.Background(), "inexistente", 7)
+	assert.Equal(t, sharedErrors.ErrClientNotFound, err)
+}
+
+func TestClientUseCase_RegisterUser_CompensatesKeycloakFailures(t *testing.T) {
+	for _, step := range []string{"SetUserAttribute", "AddUserToGroup"} {
+		t.Run(step, func(t *testing.T) {
+			mock := service.NewInMemoryKeycloakService().(*service.InMemoryKeycloakService)
+			clientUserRepo := repository.NewInMemoryClientUserRepository()
+			uc := setupRegisterFailureTest(&failingKeycloak{KeycloakService: mock, failOn: step}, clientUserRepo)
+
+			_, err := uc.RegisterUser(context.Background(), "test", dto.RegisterUserRequest{Email: "user1@test.com", Password: "pass"})
+
+			assert.Error(t, err)
+			assert.Equal(t, 0, mock.UserCount())
+		})
+	}
+}
+
+func TestClientUseCase_RegisterUser_CompensatesRepositoryFailure(t *testing.T) {
+	mock := service.NewInMemoryKeycloakService().(*service.InMemoryKeycloakService)
+	clientUserRepo := &failingClientUserRepo{ClientUserRepository: repository.NewInMemoryClientUserRepository()}
+	uc := setupRegisterFailureTest(mock, clientUserRepo)
+
+	_, err := uc.RegisterUser(context.Background(), "test", dto.RegisterUserRequest{Email: "user1@test.com", Password: "pass"})
+
+	assert.Error(t, err)
+	assert.Equal(t, 0, mock.UserCount())
+}
+
+func TestClientUseCase_RegisterUser_DisablesWhenDeleteFails(t *testing.T) {
+	mock := service.NewInMemoryKeycloakService().(*service.InMemoryKeycloakService)
+	kc := &failingKeycloak{KeycloakService: mock, failOn: "AddUserToGroup", deleteErr: errors.New("keycloak indisponível")}
+	uc := setupRegisterFailureTest(kc, repository.NewInMemoryClientUserRepository())
+
+	_, err := uc.RegisterUser(context.Background(), "test", dto.RegisterUserRequest{Email: "user1@test.com", Password: "pass"})
+
+	assert.Error(t, err)
+	require.Equal(t, 1, mock.UserCount())
+	assert.False(t, mock.IsUserEnabled("user-2"))
+}
+
+func TestClientUseCase_RegisterUser_ConcurrentLimit(t *testing.T) {
+	mock := service.NewInMemoryKeycloakService().(*service.InMemoryKeycloakService)
+	clientUserRepo := repository.NewInMemoryClientUserRepository()
+
+	var mu sync.Mutex
+	uuidGen := mockUUID()
+	safeUUID := func() string {
+		mu.Lock()
+		defer mu.Unlock()
+		return uuidGen()
+	}
+
+	uc := NewClientUseCase(repository.NewInMemoryRepository(), clientUserRepo, repository.NewInMemoryClientInviteRepository(), mock, safeUUID, nil)
+	client, err := uc.CreateClient(context.Background(), dto.CreateClientRequest{Name: "Test", Slug: "test", MaxUsers: 2})
+	require.NoError(t, err)
+
+	const attempts = 10
+	var wg sync.WaitGroup
+	results := make(chan error, attempts)
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := uc.RegisterUser(context.Background(), "test", dto.RegisterUserRequest{Email: fmt.Sprintf("user%d@test.com", i), Password: "pass"})
+			results <- err
+		}(i)
+	}
+	wg.Wait()
+	close(results)
+
+	succeeded := 0
+	for err := range results {
+		if err == nil {
+			succeeded++
+			continue
+		}
+		assert.Equal(t, sharedErrors.ErrClientUserLimitReached, err)
+	}
+
+	count, _ := clientUserRepo.CountActiveByClient(context.Background(), client.ID)
+	assert.Equal(t, 2, succeeded)
+	assert.Equal(t, 2, count)
+	// Perdedores da corrida não deixam usuários órfãos no Keycloak
+	assert.Equal(t, 2, mock.UserCount())
+}
+
+func setupMembershipTest(t *testing.T) (ClientUseCase, *domain.Client, *domain.ClientUser, *service.InMemoryKeycloakService) {
+	uc, _, _, keycloakSvc := setupClientTest()
+
+	client, err := uc.CreateClient(context.Background(), dto.CreateClientRequest{Name: "Test", Slug: "test", MaxUsers: 2})
+	require.NoError(t, err)
+
+	member, err := uc.RegisterUser(context.Background(), "test", dto.RegisterUserRequest{Email: "user1@test.com", Password: "pass"})
+	require.NoError(t, err)
+
+	return uc, client, member, keycloakSvc.(*service.InMemoryKeycloakService)
+}
+
+func TestClientUseCase_InviteUser_RegisterWithToken(t *testing.T) {
+	uc, client, _, kc := setupMembershipTest(t)
+
+	invite, err := uc.InviteUser(context.Background(), client.ID, "admin-1", dto.InviteUserRequest{Email: "Chefe@Test.com", Role: domain.ClientUserRoleAdmin})
+	require.NoError(t, err)
+	assert.Equal(t, "chefe@test.com", invite.Email)
+	assert.NotEmpty(t, invite.Token)
+
+	invites, total, err := uc.ListPendingInvites(context.Background(), client.ID, 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Equal(t, invite.ID, invites[0].ID)
+
+	// E-mail diferente do convite é rejeitado
+	_, err = uc.RegisterUser(context.Background(), "test", dto.RegisterUserRequest{Email: "outro@test.com", Password: "pass", InviteToken: invite.Token})
+	assert.Equal(t, sharedErrors.ErrInvalidInvite, err)
+
+	member, err := uc.RegisterUser(context.Background(), "test", dto.RegisterUserRequest{Email: "chefe@test.com", Password: "pass", InviteToken: invite.Token})
+	require.NoError(t, err)
+	assert.Equal(t, domain.ClientUserRoleAdmin, member.Role)
+	assert.Equal(t, domain.ClientUserRoleAdmin, kc.UserAttribute(member.UserID, "client_role"))
+	assert.True(t, kc.IsUserInSubgroup(member.UserID, client.KeycloakGroupID, domain.ClientUserRoleAdmin))
+
+	// Convite aceito não pode ser reutilizado
+	_, total, _ = uc.ListPendingInvites(context.Background(), client.ID, 1, 10)
+	assert.Equal(t, 0, total)
+}
+
+func TestClientUseCase_InviteUser_Validation(t *testing.T) {
+	uc, client, _, _ := setupMembershipTest(t)
+
+	_, err := uc.InviteUser(context.Background(), client.ID, "admin-1", dto.InviteUserRequest{Email: "x@test.com", Role: "owner"})
+	assert.Equal(t, sharedErrors.ErrInvalidRole, err)
+
+	_, err = uc.RegisterUser(context.Background(), "test", dto.RegisterUserRequest{Email: "x@test.com", Password: "pass", InviteToken: "token-inexistente"})
+	assert.Equal(t, sharedErrors.ErrInvalidInvite, err)
+
+	// Limite atingido bloqueia novos convites
+	_, err = uc.RegisterUser(context.Background(), "test", dto.RegisterUserRequest{Email: "user2@test.com", Password: "pass"})
+	require.NoError(t, err)
+	_, err = uc.InviteUser(context.Background(), client.ID, "admin-1", dto.InviteUserRequest{Email: "x@test.com"})
+	assert.Equal(t, sharedErrors.ErrClientUserLimitReached, err)
+}
+
+func TestClientUseCase_ChangeUserRole(t *testing.T) {
+	uc, client, member, kc := setupMembershipTest(t)
+
+	updated, err := uc.ChangeUserRole(context.Background(), client.ID, "admin-1", member.ID, domain.ClientUserRoleAdmin)
+	require.NoError(t, err)
+	assert.Equal(t, domain.ClientUserRoleAdmin, updated.Role)
+	assert.Equal(t, domain.ClientUserRoleAdmin, kc.UserAttribute(member.UserID, "client_role"))
+	assert.True(t, kc.IsUserInSubgroup(member.UserID, client.KeycloakGroupID, domain.ClientUserRoleAdmin))
+
+	role, _ := uc.GetTenantRole(context.Background(), client.ID, member.UserID)
+	assert.Equal(t, domain.ClientUserRoleAdmin, role)
+
+	_, err = uc.ChangeUserRole(context.Background(), client.ID, "admin-1", member.ID, domain.ClientUserRoleUser)
+	require.NoError(t, err)
+	assert.False(t, kc.IsUserInSubgroup(member.UserID, client.KeycloakGroupID, domain.ClientUserRoleAdmin))
+	assert.True(t, kc.IsUserInGroup(member.UserID, client.KeycloakGroupID))
+
+	_, err = uc.ChangeUserRole(context.Background(), client.ID, "admin-1", member.ID, "owner")
+	assert.Equal(t, sharedErrors.ErrInvalidRole, err)
+
+	_, err = uc.ChangeUserRole(context.Background(), client.ID, member.UserID, member.ID, domain.ClientUserRoleUser)
+	assert.Equal(t, sharedErrors.ErrCannotModifySelf, err)
+
+	_, err = uc.ChangeUserRole(context.Background(), "outro-client", "admin-1", member.ID, domain.ClientUserRoleUser)
+	assert.Equal(t, sharedErrors.ErrClientUserNotFound, err)
+}
+
+func TestClientUseCase_ChangeUserRole_RestoresRoleWhenKeycloakFails(t *testing.T) {
+	for _, step := range []string{"SetSubgroupMembership", "SetUserAttribute"} {
+		t.Run(step, func(t *testing.T) {
+			mock := service.NewInMemoryKeycloakService().(*service.InMemoryKeycloakService)
+			kc := &failingKeycloak{KeycloakService: mock}
+			uc := setupRegisterFailureTest(kc, repository.NewInMemoryClientUserRepository())
+			client, err := uc.GetClientBySlug(context.Background(), "test")
+			require.NoError(t, err)
+			member, err := uc.RegisterUser(context.Background(), "test", dto.RegisterUserRequest{Email: "user1@test.com", Password: "pass"})
+			require.NoError(t, err)
+
+			kc.failOn = step
+			_, err = uc.ChangeUserRole(context.Background(), client.ID, "admin-1", member.ID, domain.ClientUserRoleAdmin)
+			assert.Error(t, err)
+
+			role, _ := uc.GetTenantRole(context.Background(), client.ID, member.UserID)
+			assert.Equal(t, domain.ClientUserRoleUser, role)
+			assert.False(t, mock.IsUserInSubgroup(member.UserID, client.KeycloakGroupID, domain.ClientUserRoleAdmin))
+		})
+	}
+}
+
+func TestClientUseCase_DeactivateAndReactivateUser(t *testing.T) {
+	uc, client, member, kc := setupMembershipTest(t)
+
+	deactivated, err := uc.DeactivateUser(context.Background(), client.ID, "admin-1", member.ID)
+	require.NoError(t, err)
+	assert.False(t, deactivated.Active)
+	assert.False(t, kc.IsUserEnabled(member.UserID))
+	assert.False(t, kc.IsUserInGroup(member.UserID, client.KeycloakGroupID))
+
+	role, _ := uc.GetTenantRole(context.Background(), client.ID, member.UserID)
+	assert.Empty(t, role)
+
+	reactivated, err := uc.ReactivateUser(context.Background(), client.ID, "admin-1", member.ID)
+	require.NoError(t, err)
+	assert.True(t, reactivated.Active)
+	assert.True(t, kc.IsUserEnabled(member.UserID))
+	assert.True(t, kc.IsUserInGroup(member.UserID, client.KeycloakGroupID))
+}
+
+func TestClientUseCase_ReactivateUser_RespectsLimit(t *testing.T) {
+	uc, client, member, _ := setupMembershipTest(t)
+
+	_, err := uc.DeactivateUser(context.Background(), client.ID, "admin-1", member.ID)
+	require.NoError(t, err)
+
+	// Vagas ocupadas enquanto o membro estava inativo
+	for i := 2; i <= 3; i++ {
+		_, err := uc.RegisterUser(context.Background(), "test", dto.RegisterUserRequest{Email: fmt.Sprintf("user%d@test.com", i), Password: "pass"})
+		require.NoError(t, err)
+	}
+
+	_, err = uc.ReactivateUser(context.Background(), client.ID, "admin-1", member.ID)
+	assert.Equal(t, sharedErrors.ErrClientUserLimitReached, err)
+}
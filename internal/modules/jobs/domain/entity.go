@@ -2,6 +2,7 @@ package domain
 
 import (
 	"encoding/json"
+	"errors"
 	"time"
 )
 
@@ -12,8 +13,22 @@ const (
 	JobStatusProcessing JobStatus = "processing"
 	JobStatusCompleted  JobStatus = "completed"
 	JobStatusFailed     JobStatus = "failed"
+	JobStatusCancelled  JobStatus = "cancelled"
+	JobStatusPaused     JobStatus = "paused"
 )
 
+// IsValid indica se o status existe
+func (s JobStatus) IsValid() bool {
+	switch s {
+	case JobStatusPending, JobStatusProcessing, JobStatusCompleted, JobStatusFailed, JobStatusCancelled, JobStatusPaused:
+		return true
+	}
+	return false
+}
+
+// DefaultMaxAttempts tentativas de um job antes de ir para a dead-letter queue
+const DefaultMaxAttempts = 3
+
 type JobType string
 
 const (
@@ -21,9 +36,25 @@ const (
 	JobTypeCSVImport      JobType = "csv_import"
 )
 
+// IsValid indica se o tipo existe
+func (t JobType) IsValid() bool {
+	return t == JobTypeBulkAplicacoes || t == JobTypeCSVImport
+}
+
+// JobFilter filtros da listagem de jobs do tenant; campos vazios não filtram
+type JobFilter struct {
+	Status        JobStatus
+	Type          JobType
+	UserID        string     // quem criou o job
+	CreatedAfter  *time.Time // inclusivo
+	CreatedBefore *time.Time // exclusivo
+}
+
 // Job representa um trabalho em background
 type Job struct {
 	ID             string
+	ClientID       string
+	UserID         string
 	Type           JobType
 	Status         JobStatus
 	Payload        json.RawMessage
@@ -33,6 +64,9 @@ type Job struct {
 	ProcessedItems int
 	ErrorCount     int
 	ErrorDetails   json.RawMessage
+	Attempts       int
+	MaxAttempts    int
+	StopRequested  JobStatus // cancelled ou paused pedido pelo usuário, atendido pelo worker entre os lotes
 	StartedAt      *time.Time
 	CompletedAt    *time.Time
 	CreatedAt      time.Time
@@ -47,25 +81,128 @@ func NewJob(id string, jobType JobType, payload interface{}) (*Job, error) {
 	}
 
 	return &Job{
-		ID:        id,
-		Type:      jobType,
-		Status:    JobStatusPending,
-		Payload:   payloadBytes,
-		Progress:  0,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+		ID:          id,
+		Type:        jobType,
+		Status:      JobStatusPending,
+		Payload:     payloadBytes,
+		Progress:    0,
+		MaxAttempts: DefaultMaxAttempts,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
 	}, nil
 }
 
-// Start marca o job como em processamento
+// Start marca o job como em processamento e conta a tentativa.
+// Na retomada após pausa os erros dos itens já processados são mantidos
 func (j *Job) Start(totalItems int) {
 	now := time.Now()
 	j.Status = JobStatusProcessing
 	j.StartedAt = &now
 	j.TotalItems = totalItems
+	j.Attempts++
+	if j.ProcessedItems == 0 {
+		j.ErrorDetails = nil
+	}
 	j.UpdatedAt = now
 }
 
+// IsFinished indica se o job chegou a um status final
+func (j *Job) IsFinished() bool {
+	return j.Status == JobStatusCompleted || j.Status == JobStatusFailed || j.Status == JobStatusCancelled
+}
+
+// CanBeControlled indica se o job pode ser cancelado ou pausado: só o processamento
+// item a item das aplicações em massa para de forma limpa entre os lotes
+func (j *Job) CanBeControlled() bool {
+	return j.Type == JobTypeBulkAplicacoes
+}
+
+// Stop interrompe o job como cancelled ou paused, guardando o que já foi aplicado.
+// O job pausado mantém processed_items e os erros para retomar do mesmo ponto
+func (j *Job) Stop(status JobStatus, result interface{}, errors []JobError) error {
+	now := time.Now()
+	j.Status = status
+	j.StopRequested = ""
+	j.UpdatedAt = now
+	if status == JobStatusCancelled {
+		j.CompletedAt = &now
+	}
+
+	if result != nil {
+		resultBytes, err := json.Marshal(result)
+		if err != nil {
+			return err
+		}
+		j.Result = resultBytes
+	}
+	if len(errors) > 0 {
+		errBytes, err := json.Marshal(errors)
+		if err != nil {
+			return err
+		}
+		j.ErrorCount = len(errors)
+		j.ErrorDetails = errBytes
+	}
+	return nil
+}
+
+// Resume volta o job pausado para pendente; o processamento continua do item em que parou
+func (j *Job) Resume() {
+	j.Status = JobStatusPending
+	j.Attempts = 0
+	j.UpdatedAt = time.Now()
+}
+
+// Errors retorna os erros por item gravados no job
+func (j *Job) Errors() ([]JobError, error) {
+	if len(j.ErrorDetails) == 0 {
+		return nil, nil
+	}
+	var errors []JobError
+	if err := json.Unmarshal(j.ErrorDetails, &errors); err != nil {
+		return nil, err
+	}
+	return errors, nil
+}
+
+// Requeue volta o job para pendente, para ser processado de novo desde o início
+func (j *Job) Requeue() {
+	j.Status = JobStatusPending
+	j.StartedAt = nil
+	j.Progress = 0
+	j.ProcessedItems = 0
+	j.ErrorCount = 0
+	j.UpdatedAt = time.Now()
+}
+
+// CanRetry indica se o job ainda tem tentativas
+func (j *Job) CanRetry() bool {
+	return j.Attempts < j.MaxAttempts
+}
+
+// Retry volta o job para pendente após uma falha temporária, guardando os erros da tentativa
+func (j *Job) Retry(errors []JobError) error {
+	j.Requeue()
+	j.ErrorCount = len(errors)
+
+	if len(errors) > 0 {
+		errBytes, err := json.Marshal(errors)
+		if err != nil {
+			return err
+		}
+		j.ErrorDetails = errBytes
+	}
+	return nil
+}
+
+// Replay volta um job da dead-letter queue para pendente, com as tentativas zeradas
+func (j *Job) Replay() {
+	j.Requeue()
+	j.Attempts = 0
+	j.CompletedAt = nil
+	j.ErrorDetails = nil
+}
+
 // UpdateProgress atualiza o progresso
 func (j *Job) UpdateProgress(processed int) {
 	j.ProcessedItems = processed
@@ -75,12 +212,14 @@ func (j *Job) UpdateProgress(processed int) {
 	j.UpdatedAt = time.Now()
 }
 
-// Complete marca como concluído
-func (j *Job) Complete(result interface{}) error {
+// Complete marca como concluído, guardando os erros dos itens que falharam
+// (incluindo os de antes de uma pausa)
+func (j *Job) Complete(result interface{}, errors []JobError) error {
 	now := time.Now()
 	j.Status = JobStatusCompleted
 	j.CompletedAt = &now
 	j.Progress = 100
+	j.ErrorDetails = nil
 	j.UpdatedAt = now
 
 	if result != nil {
@@ -90,6 +229,14 @@ func (j *Job) Complete(result interface{}) error {
 		}
 		j.Result = resultBytes
 	}
+	if len(errors) > 0 {
+		errBytes, err := json.Marshal(errors)
+		if err != nil {
+			return err
+		}
+		j.ErrorCount = len(errors)
+		j.ErrorDetails = errBytes
+	}
 	return nil
 }
 
@@ -124,6 +271,30 @@ type JobError struct {
 	Message string `json:"message"`
 }
 
+// RetryableError falha temporária (ex.: banco indisponível): o job é reenfileirado com backoff
+type RetryableError struct {
+	Err error
+}
+
+// Retryable marca o erro como temporário
+func Retryable(err error) error {
+	return &RetryableError{Err: err}
+}
+
+func (e *RetryableError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RetryableError) Unwrap() error {
+	return e.Err
+}
+
+// IsRetryable indica se o erro foi marcado como temporário
+func IsRetryable(err error) bool {
+	var retryable *RetryableError
+	return errors.As(err, &retryable)
+}
+
 // BulkAplicacoesPayload payload para job de aplicações em massa
 type BulkAplicacoesPayload struct {
 	Aplicacoes []AplicacaoItem `json:"aplicacoes"`
@@ -143,3 +314,27 @@ type BulkAplicacoesResult struct {
 	Processed int `json:"processed"`
 	Errors    int `json:"errors"`
 }
+
+// CSVImportPayload payload para job de importação de CSV
+type CSVImportPayload struct {
+	MonitoramentoID string `json:"monitoramento_id"`
+	FileKey         string `json:"file_key"` // caminho do arquivo salvo no upload
+	Filename        string `json:"filename"`
+	Validation      string `json:"validation,omitempty"` // lenient (padrão) ou strict
+	Mode            string `json:"mode,omitempty"`       // append (padrão) ou upsert
+	ProfileID       string `json:"profile_id,omitempty"` // perfil de importação do client
+	Format          string `json:"format,omitempty"`     // csv (padrão) ou xlsx
+	Sheet           string `json:"sheet,omitempty"`      // aba da planilha XLSX
+	Encoding        string `json:"encoding,omitempty"`   // encoding declarado do CSV (vazio = detectar)
+}
+
+// CSVImportResult resultado da importação de CSV
+type CSVImportResult struct {
+	MonitoramentoID string `json:"monitoramento_id"`
+	TotalLinhas     int    `json:"total_linhas"`
+	Areas           int    `json:"areas"`
+	TotalErros      int    `json:"total_erros"`
+}
+
+// ProgressFunc reporta o progresso de um job durante o processamento
+type ProgressFunc func(total, processed, errorCount int)
@@ -1,12 +1,25 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // JobRepository interface de persistência
 type JobRepository interface {
 	Create(ctx context.Context, job *Job) error
 	GetByID(ctx context.Context, id string) (*Job, error)
 	Update(ctx context.Context, job *Job) error
+	// UpdateIfStatus atualiza o job somente se o status no banco for um dos informados;
+	// retorna false se o job mudou de status (ex.: o worker começou a processá-lo)
+	UpdateIfStatus(ctx context.Context, job *Job, from ...JobStatus) (bool, error)
+	// RequestStop registra o pedido de cancelamento/pausa de um job em processing;
+	// retorna false se o job não está mais em processing
+	RequestStop(ctx context.Context, id string, status JobStatus) (bool, error)
 	UpdateProgress(ctx context.Context, id string, processed, errorCount int) error
-	List(ctx context.Context, status *JobStatus, limit, offset int) ([]*Job, int, error)
+	List(ctx context.Context, filter JobFilter, limit, offset int) ([]*Job, int, error)
+	// ListStale lista, em todos os tenants, os jobs no status sem atualização desde updatedBefore
+	ListStale(ctx context.Context, status JobStatus, updatedBefore time.Time) ([]*Job, error)
+	// DeleteFinishedBefore apaga, em todos os tenants, os jobs completed/failed/cancelled finalizados antes de completedBefore
+	DeleteFinishedBefore(ctx context.Context, completedBefore time.Time) (int, error)
 }
@@ -46,6 +46,10 @@ type JobResponse struct {
 	ProcessedItems int              `json:"processed_items"`
 	ErrorCount     int              `json:"error_count"`
 	Errors         []JobErrorDetail `json:"errors,omitempty"`
+	Result         json.RawMessage  `json:"result,omitempty"` // inclui os itens aplicados antes de um cancelamento
+	Attempts       int              `json:"attempts"`
+	MaxAttempts    int              `json:"max_attempts"`
+	StopRequested  string           `json:"stop_requested,omitempty"` // cancelamento/pausa aguardando o worker
 	StartedAt      *time.Time       `json:"started_at,omitempty"`
 	CompletedAt    *time.Time       `json:"completed_at,omitempty"`
 	CreatedAt      time.Time        `json:"created_at"`
@@ -68,6 +72,10 @@ func ToJobResponse(j *domain.Job) JobResponse {
 		TotalItems:     j.TotalItems,
 		ProcessedItems: j.ProcessedItems,
 		ErrorCount:     j.ErrorCount,
+		Result:         j.Result,
+		Attempts:       j.Attempts,
+		MaxAttempts:    j.MaxAttempts,
+		StopRequested:  string(j.StopRequested),
 		StartedAt:      j.StartedAt,
 		CompletedAt:    j.CompletedAt,
 		CreatedAt:      j.CreatedAt,
@@ -90,3 +98,55 @@ type CreateJobResponse struct {
 	Status  string `json:"status"`
 	Message string `json:"message"`
 }
+
+// ListJobsResponse resposta paginada dos jobs do client
+type ListJobsResponse struct {
+	Data       []JobResponse `json:"data"`
+	Page       int           `json:"page"`
+	PageSize   int           `json:"page_size"`
+	TotalCount int           `json:"total_count"`
+}
+
+// ToListJobsResponse converte lista para DTO
+func ToListJobsResponse(jobs []*domain.Job, page, pageSize, total int) ListJobsResponse {
+	data := make([]JobResponse, len(jobs))
+	for i, j := range jobs {
+		data[i] = ToJobResponse(j)
+	}
+
+	return ListJobsResponse{
+		Data:       data,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalCount: total,
+	}
+}
+
+// DeadJobResponse job da dead-letter queue (admin), com o tenant de origem
+type DeadJobResponse struct {
+	JobResponse
+	ClientID string `json:"client_id"`
+}
+
+// ListDeadJobsResponse resposta paginada da dead-letter queue
+type ListDeadJobsResponse struct {
+	Data       []DeadJobResponse `json:"data"`
+	Page       int               `json:"page"`
+	PageSize   int               `json:"page_size"`
+	TotalCount int               `json:"total_count"`
+}
+
+// ToListDeadJobsResponse converte lista para DTO
+func ToListDeadJobsResponse(jobs []*domain.Job, page, pageSize, total int) ListDeadJobsResponse {
+	data := make([]DeadJobResponse, len(jobs))
+	for i, j := range jobs {
+		data[i] = DeadJobResponse{JobResponse: ToJobResponse(j), ClientID: j.ClientID}
+	}
+
+	return ListDeadJobsResponse{
+		Data:       data,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalCount: total,
+	}
+}
@@ -2,10 +2,14 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 
+	"agro-monitoring/internal/modules/jobs/domain"
 	"agro-monitoring/internal/modules/jobs/dto"
 	"agro-monitoring/internal/modules/jobs/usecase"
 	sharedErrors "agro-monitoring/internal/shared/errors"
@@ -25,8 +29,21 @@ func NewHandler(uc usecase.JobUseCase) *Handler {
 // RegisterRoutes registra as rotas de jobs
 func (h *Handler) RegisterRoutes(r chi.Router) {
 	r.Route("/jobs", func(r chi.Router) {
+		r.Get("/", h.ListJobs)
 		r.Post("/aplicacoes", h.CreateBulkAplicacoes)
 		r.Get("/{id}", h.GetJobStatus)
+		r.Post("/{id}/cancel", h.CancelJob)
+		r.Post("/{id}/pause", h.PauseJob)
+		r.Post("/{id}/resume", h.ResumeJob)
+	})
+}
+
+// RegisterAdminRoutes registra as rotas admin da dead-letter queue (/v1/admin/jobs/dead)
+func (h *Handler) RegisterAdminRoutes(r chi.Router) {
+	r.Route("/jobs/dead", func(r chi.Router) {
+		r.Get("/", h.ListDeadJobs)
+		r.Post("/{id}/replay", h.ReplayDeadJob)
+		r.Delete("/{id}", h.DiscardDeadJob)
 	})
 }
 
@@ -65,6 +82,52 @@ func (h *Handler) CreateBulkAplicacoes(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// ListJobs lista os jobs do client, com filtros opcionais status, type, user_id (quem criou)
+// e created_from/created_to (data YYYY-MM-DD ou RFC 3339; a data final inclui o dia inteiro)
+func (h *Handler) ListJobs(w http.ResponseWriter, r *http.Request) {
+	page := getQueryInt(r, "page", 1)
+	pageSize := getQueryInt(r, "page_size", 20)
+
+	q := r.URL.Query()
+	filter := domain.JobFilter{
+		Status: domain.JobStatus(q.Get("status")),
+		Type:   domain.JobType(q.Get("type")),
+		UserID: q.Get("user_id"),
+	}
+
+	if val := q.Get("created_from"); val != "" {
+		from, _, err := parseDateParam(val)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "created_from inválido: use YYYY-MM-DD ou RFC 3339")
+			return
+		}
+		filter.CreatedAfter = &from
+	}
+	if val := q.Get("created_to"); val != "" {
+		to, dateOnly, err := parseDateParam(val)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "created_to inválido: use YYYY-MM-DD ou RFC 3339")
+			return
+		}
+		if dateOnly {
+			to = to.AddDate(0, 0, 1)
+		}
+		filter.CreatedBefore = &to
+	}
+
+	jobs, total, err := h.uc.ListJobs(r.Context(), filter, page, pageSize)
+	if err != nil {
+		if errors.Is(err, sharedErrors.ErrInvalidJobFilter) {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "Erro ao listar jobs")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, dto.ToListJobsResponse(jobs, page, pageSize, total))
+}
+
 // GetJobStatus retorna status de um job
 func (h *Handler) GetJobStatus(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
@@ -82,6 +145,89 @@ func (h *Handler) GetJobStatus(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, http.StatusOK, dto.ToJobResponse(job))
 }
 
+// CancelJob cancela um job; em processamento o worker para no próximo lote (202)
+func (h *Handler) CancelJob(w http.ResponseWriter, r *http.Request) {
+	job, err := h.uc.CancelJob(r.Context(), chi.URLParam(r, "id"))
+	h.respondJobControl(w, job, err)
+}
+
+// PauseJob pausa um job; em processamento o worker para no próximo lote (202)
+func (h *Handler) PauseJob(w http.ResponseWriter, r *http.Request) {
+	job, err := h.uc.PauseJob(r.Context(), chi.URLParam(r, "id"))
+	h.respondJobControl(w, job, err)
+}
+
+// ResumeJob volta um job pausado para a fila
+func (h *Handler) ResumeJob(w http.ResponseWriter, r *http.Request) {
+	job, err := h.uc.ResumeJob(r.Context(), chi.URLParam(r, "id"))
+	h.respondJobControl(w, job, err)
+}
+
+func (h *Handler) respondJobControl(w http.ResponseWriter, job *domain.Job, err error) {
+	if err != nil {
+		switch err {
+		case sharedErrors.ErrJobNotFound:
+			respondError(w, http.StatusNotFound, "Job não encontrado")
+		case sharedErrors.ErrJobNotControllable:
+			respondError(w, http.StatusBadRequest, "Somente jobs bulk_aplicacoes podem ser cancelados ou pausados")
+		case sharedErrors.ErrInvalidJobTransition:
+			respondError(w, http.StatusConflict, err.Error())
+		default:
+			respondError(w, http.StatusInternalServerError, "Erro interno")
+		}
+		return
+	}
+
+	status := http.StatusOK
+	if job.Status == domain.JobStatusPending || job.StopRequested != "" {
+		status = http.StatusAccepted
+	}
+	respondJSON(w, status, dto.ToJobResponse(job))
+}
+
+// ListDeadJobs lista os jobs que esgotaram as tentativas (admin)
+func (h *Handler) ListDeadJobs(w http.ResponseWriter, r *http.Request) {
+	page := getQueryInt(r, "page", 1)
+	pageSize := getQueryInt(r, "page_size", 20)
+
+	jobs, total, err := h.uc.ListDeadJobs(r.Context(), page, pageSize)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Erro ao listar dead-letter queue")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, dto.ToListDeadJobsResponse(jobs, page, pageSize, total))
+}
+
+// ReplayDeadJob reenfileira um job da dead-letter queue (admin)
+func (h *Handler) ReplayDeadJob(w http.ResponseWriter, r *http.Request) {
+	job, err := h.uc.ReplayDeadJob(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		if err == sharedErrors.ErrJobNotFound {
+			respondError(w, http.StatusNotFound, "Job não encontrado na dead-letter queue")
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "Erro ao reenfileirar job: "+err.Error())
+		return
+	}
+
+	respondJSON(w, http.StatusAccepted, dto.ToJobResponse(job))
+}
+
+// DiscardDeadJob remove um job da dead-letter queue sem reprocessar (admin)
+func (h *Handler) DiscardDeadJob(w http.ResponseWriter, r *http.Request) {
+	if err := h.uc.DiscardDeadJob(r.Context(), chi.URLParam(r, "id")); err != nil {
+		if err == sharedErrors.ErrJobNotFound {
+			respondError(w, http.StatusNotFound, "Job não encontrado na dead-letter queue")
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "Erro ao remover job")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
 func respondJSON(w http.ResponseWriter, status int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
@@ -91,3 +237,24 @@ func respondJSON(w http.ResponseWriter, status int, data interface{}) {
 func respondError(w http.ResponseWriter, status int, message string) {
 	respondJSON(w, status, response.ErrorResponse{Message: message})
 }
+
+// parseDateParam aceita data (YYYY-MM-DD, meia-noite) ou RFC 3339; indica se veio só a data
+func parseDateParam(val string) (time.Time, bool, error) {
+	if t, err := time.ParseInLocation("2006-01-02", val, time.Local); err == nil {
+		return t, true, nil
+	}
+	t, err := time.Parse(time.RFC3339, val)
+	return t, false, err
+}
+
+func getQueryInt(r *http.Request, key string, defaultVal int) int {
+	val := r.URL.Query().Get(key)
+	if val == "" {
+		return defaultVal
+	}
+	i, err := strconv.Atoi(val)
+	if err != nil {
+		return defaultVal
+	}
+	return i
+}
@@ -0,0 +1,217 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"agro-monitoring/internal/modules/jobs/domain"
+	sharedContext "agro-monitoring/internal/shared/context"
+	sharedErrors "agro-monitoring/internal/shared/errors"
+)
+
+// InMemoryRepository implementação em memória dos jobs para testes.
+// Guarda cópias, como o banco: alterações no job só valem após Update
+type InMemoryRepository struct {
+	mu    sync.RWMutex
+	items map[string]*domain.Job
+}
+
+// NewInMemoryRepository cria um novo repository de jobs em memória
+func NewInMemoryRepository() *InMemoryRepository {
+	return &InMemoryRepository{
+		items: make(map[string]*domain.Job),
+	}
+}
+
+func (r *InMemoryRepository) Create(ctx context.Context, job *domain.Job) error {
+	clientID, err := sharedContext.RequireClientID(ctx)
+	if err != nil {
+		return err
+	}
+	userID, _ := sharedContext.GetUserID(ctx)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	job.ClientID = clientID
+	job.UserID = userID
+	stored := *job
+	r.items[job.ID] = &stored
+	return nil
+}
+
+func (r *InMemoryRepository) GetByID(ctx context.Context, id string) (*domain.Job, error) {
+	clientID, err := sharedContext.RequireClientID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	job, ok := r.items[id]
+	if !ok || job.ClientID != clientID {
+		return nil, sharedErrors.ErrJobNotFound
+	}
+	result := *job
+	return &result, nil
+}
+
+func (r *InMemoryRepository) Update(ctx context.Context, job *domain.Job) error {
+	_, err := r.UpdateIfStatus(ctx, job)
+	return err
+}
+
+func (r *InMemoryRepository) UpdateIfStatus(ctx context.Context, job *domain.Job, from ...domain.JobStatus) (bool, error) {
+	clientID, err := sharedContext.RequireClientID(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.items[job.ID]
+	if !ok || existing.ClientID != clientID || !hasStatus(existing.Status, from) {
+		return false, nil
+	}
+
+	stored := *job
+	stored.ClientID = existing.ClientID
+	stored.UserID = existing.UserID
+	// Como no Postgres: o pedido de cancelamento/pausa é mantido enquanto o job está ativo
+	stored.StopRequested = ""
+	if job.Status == domain.JobStatusPending || job.Status == domain.JobStatusProcessing {
+		stored.StopRequested = existing.StopRequested
+	}
+	r.items[job.ID] = &stored
+	return true, nil
+}
+
+func (r *InMemoryRepository) RequestStop(ctx context.Context, id string, status domain.JobStatus) (bool, error) {
+	clientID, err := sharedContext.RequireClientID(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	job, ok := r.items[id]
+	if !ok || job.ClientID != clientID || job.Status != domain.JobStatusProcessing {
+		return false, nil
+	}
+	job.StopRequested = status
+	job.UpdatedAt = time.Now()
+	return true, nil
+}
+
+func (r *InMemoryRepository) UpdateProgress(ctx context.Context, id string, processed, errorCount int) error {
+	clientID, err := sharedContext.RequireClientID(ctx)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	job, ok := r.items[id]
+	if !ok || job.ClientID != clientID {
+		return nil
+	}
+	job.ErrorCount = errorCount
+	job.UpdateProgress(processed)
+	return nil
+}
+
+func (r *InMemoryRepository) List(ctx context.Context, filter domain.JobFilter, limit, offset int) ([]*domain.Job, int, error) {
+	clientID, err := sharedContext.RequireClientID(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	all := make([]*domain.Job, 0)
+	for _, job := range r.items {
+		if job.ClientID != clientID || !matches(job, filter) {
+			continue
+		}
+		item := *job
+		all = append(all, &item)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].CreatedAt.After(all[j].CreatedAt) })
+
+	total := len(all)
+	if offset >= len(all) {
+		return []*domain.Job{}, total, nil
+	}
+	all = all[offset:]
+	if limit > 0 && limit < len(all) {
+		all = all[:limit]
+	}
+	return all, total, nil
+}
+
+func (r *InMemoryRepository) ListStale(ctx context.Context, status domain.JobStatus, updatedBefore time.Time) ([]*domain.Job, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]*domain.Job, 0)
+	for _, job := range r.items {
+		if job.Status == status && job.UpdatedAt.Before(updatedBefore) {
+			item := *job
+			result = append(result, &item)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].CreatedAt.Before(result[j].CreatedAt) })
+	return result, nil
+}
+
+func (r *InMemoryRepository) DeleteFinishedBefore(ctx context.Context, completedBefore time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	deleted := 0
+	for id, job := range r.items {
+		if job.IsFinished() && job.CompletedAt != nil && job.CompletedAt.Before(completedBefore) {
+			delete(r.items, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func hasStatus(status domain.JobStatus, from []domain.JobStatus) bool {
+	if len(from) == 0 {
+		return true
+	}
+	for _, s := range from {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+func matches(job *domain.Job, filter domain.JobFilter) bool {
+	if filter.Status != "" && job.Status != filter.Status {
+		return false
+	}
+	if filter.Type != "" && job.Type != filter.Type {
+		return false
+	}
+	if filter.UserID != "" && job.UserID != filter.UserID {
+		return false
+	}
+	if filter.CreatedAfter != nil && job.CreatedAt.Before(*filter.CreatedAfter) {
+		return false
+	}
+	if filter.CreatedBefore != nil && !job.CreatedAt.Before(*filter.CreatedBefore) {
+		return false
+	}
+	return true
+}
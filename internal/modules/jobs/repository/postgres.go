@@ -5,45 +5,69 @@ import (
 	"database/sql"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
 
 	"agro-monitoring/internal/modules/jobs/domain"
+	sharedContext "agro-monitoring/internal/shared/context"
+	"agro-monitoring/internal/shared/database"
 	sharedErrors "agro-monitoring/internal/shared/errors"
 )
 
+const selectJobColumns = `
+		SELECT
+			id, client_id, COALESCE(user_id, ''), type, status, payload, result,
+			progress, total_items, processed_items, error_count, error_details,
+			attempts, max_attempts, COALESCE(stop_requested, ''), started_at, completed_at, created_at, updated_at
+		FROM jobs`
+
 type PostgresJobRepository struct {
-	db *sql.DB
+	db *database.TenantDB
 }
 
-func NewPostgresRepository(db *sql.DB) domain.JobRepository {
+func NewPostgresRepository(db *database.TenantDB) domain.JobRepository {
 	return &PostgresJobRepository{db: db}
 }
 
 func (r *PostgresJobRepository) Create(ctx context.Context, job *domain.Job) error {
+	clientID, err := sharedContext.RequireClientID(ctx)
+	if err != nil {
+		return err
+	}
+	userID, _ := sharedContext.GetUserID(ctx)
+
+	job.ClientID = clientID
+	job.UserID = userID
+
 	query := `
-		INSERT INTO jobs (id, type, status, payload, total_items, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO jobs (id, client_id, user_id, type, status, payload, total_items, max_attempts, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 	`
-	_, err := r.db.ExecContext(ctx, query, job.ID, job.Type, job.Status, job.Payload, job.TotalItems, job.CreatedAt, job.UpdatedAt)
-	return err
+	return r.db.InTenant(ctx, clientID, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, query, job.ID, job.ClientID, job.UserID, job.Type, job.Status, job.Payload, job.TotalItems, job.MaxAttempts, job.CreatedAt, job.UpdatedAt)
+		return err
+	})
 }
 
 func (r *PostgresJobRepository) GetByID(ctx context.Context, id string) (*domain.Job, error) {
-	query := `
-		SELECT 
-			id, type, status, payload, result, 
-			progress, total_items, processed_items, error_count, error_details,
-			started_at, completed_at, created_at, updated_at
-		FROM jobs
-		WHERE id = $1
+	clientID, err := sharedContext.RequireClientID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := selectJobColumns + `
+		WHERE id = $1 AND client_id = $2
 	`
-	job := &domain.Job{}
-	var payload, result, errorDetails sql.NullString
 
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&job.ID, &job.Type, &job.Status, &payload, &result,
-		&job.Progress, &job.TotalItems, &job.ProcessedItems, &job.ErrorCount, &errorDetails,
-		&job.StartedAt, &job.CompletedAt, &job.CreatedAt, &job.UpdatedAt,
-	)
+	var job *domain.Job
+	err = r.db.InTenant(ctx, clientID, func(tx *sql.Tx) error {
+		var err error
+		job, err = scanJob(tx.QueryRowContext(ctx, query, id, clientID))
+		return err
+	})
 
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -52,20 +76,26 @@ func (r *PostgresJobRepository) GetByID(ctx context.Context, id string) (*domain
 		return nil, err
 	}
 
-	if payload.Valid {
-		job.Payload = json.RawMessage(payload.String)
-	}
-	if result.Valid {
-		job.Result = json.RawMessage(result.String)
-	}
-	if errorDetails.Valid {
-		job.ErrorDetails = json.RawMessage(errorDetails.String)
-	}
-
 	return job, nil
 }
 
 func (r *PostgresJobRepository) Update(ctx context.Context, job *domain.Job) error {
+	_, err := r.update(ctx, job, nil)
+	return err
+}
+
+func (r *PostgresJobRepository) UpdateIfStatus(ctx context.Context, job *domain.Job, from ...domain.JobStatus) (bool, error) {
+	return r.update(ctx, job, from)
+}
+
+// update grava o job; com from, somente se o status no banco for um deles.
+// O pedido de cancelamento/pausa é mantido enquanto o job está ativo e limpo ao sair dele
+func (r *PostgresJobRepository) update(ctx context.Context, job *domain.Job, from []domain.JobStatus) (bool, error) {
+	clientID, err := sharedContext.RequireClientID(ctx)
+	if err != nil {
+		return false, err
+	}
+
 	query := `
 		UPDATE jobs
 		SET
@@ -79,8 +109,11 @@ func (r *PostgresJobRepository) Update(ctx context.Context, job *domain.Job) err
 			error_details = $9,
 			started_at = $10,
 			completed_at = $11,
-			updated_at = $12
-		WHERE id = $1
+			updated_at = $12,
+			attempts = $14,
+			max_attempts = $15,
+			stop_requested = CASE WHEN $2 IN ('pending', 'processing') THEN stop_requested END
+		WHERE id = $1 AND client_id = $13
 	`
 
 	// Trata campos JSON nulos
@@ -95,15 +128,71 @@ func (r *PostgresJobRepository) Update(ctx context.Context, job *domain.Job) err
 		errorDetails = job.ErrorDetails
 	}
 
-	_, err := r.db.ExecContext(ctx, query,
+	args := []interface{}{
 		job.ID, job.Status, payload, result,
 		job.Progress, job.TotalItems, job.ProcessedItems, job.ErrorCount, errorDetails,
-		job.StartedAt, job.CompletedAt, job.UpdatedAt,
-	)
-	return err
+		job.StartedAt, job.CompletedAt, job.UpdatedAt, clientID,
+		job.Attempts, job.MaxAttempts,
+	}
+	if len(from) > 0 {
+		statuses := make([]string, len(from))
+		for i, status := range from {
+			statuses[i] = string(status)
+		}
+		query += ` AND status = ANY($16)`
+		args = append(args, pq.Array(statuses))
+	}
+
+	var updated bool
+	err = r.db.InTenant(ctx, clientID, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		rows, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		updated = rows > 0
+		return nil
+	})
+	return updated, err
+}
+
+func (r *PostgresJobRepository) RequestStop(ctx context.Context, id string, status domain.JobStatus) (bool, error) {
+	clientID, err := sharedContext.RequireClientID(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	query := `
+		UPDATE jobs
+		SET stop_requested = $2, updated_at = NOW()
+		WHERE id = $1 AND client_id = $3 AND status = 'processing'
+	`
+
+	var requested bool
+	err = r.db.InTenant(ctx, clientID, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, query, id, status, clientID)
+		if err != nil {
+			return err
+		}
+		rows, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		requested = rows > 0
+		return nil
+	})
+	return requested, err
 }
 
 func (r *PostgresJobRepository) UpdateProgress(ctx context.Context, id string, processed, errorCount int) error {
+	clientID, err := sharedContext.RequireClientID(ctx)
+	if err != nil {
+		return err
+	}
+
 	query := `
         UPDATE jobs
         SET 
@@ -114,13 +203,198 @@ func (r *PostgresJobRepository) UpdateProgress(ctx context.Context, id string, p
                 ELSE 0
             END,
             updated_at = NOW()
-        WHERE id = $1
+        WHERE id = $1 AND client_id = $4
     `
-	_, err := r.db.ExecContext(ctx, query, id, processed, errorCount)
-	return err
+	return r.db.InTenant(ctx, clientID, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, query, id, processed, errorCount, clientID)
+		return err
+	})
 }
 
-func (r *PostgresJobRepository) List(ctx context.Context, status *domain.JobStatus, limit, offset int) ([]*domain.Job, int, error) {
-	// Implementação futura, se necessário
-	return nil, 0, errors.New("not implemented")
+func (r *PostgresJobRepository) List(ctx context.Context, filter domain.JobFilter, limit, offset int) ([]*domain.Job, int, error) {
+	clientID, err := sharedContext.RequireClientID(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	where := []string{"client_id = $1"}
+	args := []interface{}{clientID}
+	addFilter := func(condition string, value interface{}) {
+		args = append(args, value)
+		where = append(where, fmt.Sprintf(condition, len(args)))
+	}
+	if filter.Status != "" {
+		addFilter("status = $%d", filter.Status)
+	}
+	if filter.Type != "" {
+		addFilter("type = $%d", filter.Type)
+	}
+	if filter.UserID != "" {
+		addFilter("user_id = $%d", filter.UserID)
+	}
+	if filter.CreatedAfter != nil {
+		addFilter("created_at >= $%d", *filter.CreatedAfter)
+	}
+	if filter.CreatedBefore != nil {
+		addFilter("created_at < $%d", *filter.CreatedBefore)
+	}
+	conditions := " WHERE " + strings.Join(where, " AND ")
+
+	countQuery := `SELECT COUNT(*) FROM jobs` + conditions
+	query := selectJobColumns + conditions + fmt.Sprintf(`
+		ORDER BY created_at DESC
+		LIMIT $%d OFFSET $%d
+	`, len(args)+1, len(args)+2)
+
+	var total int
+	result := make([]*domain.Job, 0)
+
+	err = r.db.InTenant(ctx, clientID, func(tx *sql.Tx) error {
+		if err := tx.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+			return err
+		}
+
+		rows, err := tx.QueryContext(ctx, query, append(args, limit, offset)...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			job, err := scanJob(rows)
+			if err != nil {
+				return err
+			}
+			result = append(result, job)
+		}
+
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return result, total, nil
+}
+
+// ListStale percorre os clients (tabela sem RLS) e consulta os jobs de cada tenant,
+// pois as policies dos jobs só mostram as linhas do app.client_id da transação
+func (r *PostgresJobRepository) ListStale(ctx context.Context, status domain.JobStatus, updatedBefore time.Time) ([]*domain.Job, error) {
+	clientIDs, err := r.clientIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := selectJobColumns + `
+		WHERE client_id = $1 AND status = $2 AND updated_at < $3
+		ORDER BY created_at
+	`
+
+	jobs := make([]*domain.Job, 0)
+	for _, clientID := range clientIDs {
+		err := r.db.InTenant(ctx, clientID, func(tx *sql.Tx) error {
+			rows, err := tx.QueryContext(ctx, query, clientID, status, updatedBefore)
+			if err != nil {
+				return err
+			}
+			defer rows.Close()
+
+			for rows.Next() {
+				job, err := scanJob(rows)
+				if err != nil {
+					return err
+				}
+				jobs = append(jobs, job)
+			}
+			return rows.Err()
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return jobs, nil
+}
+
+// DeleteFinishedBefore apaga, em todos os tenants, os jobs finalizados antes de completedBefore
+func (r *PostgresJobRepository) DeleteFinishedBefore(ctx context.Context, completedBefore time.Time) (int, error) {
+	clientIDs, err := r.clientIDs(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	query := `
+		DELETE FROM jobs
+		WHERE client_id = $1 AND status IN ('completed', 'failed', 'cancelled') AND completed_at < $2
+	`
+
+	deleted := 0
+	for _, clientID := range clientIDs {
+		err := r.db.InTenant(ctx, clientID, func(tx *sql.Tx) error {
+			result, err := tx.ExecContext(ctx, query, clientID, completedBefore)
+			if err != nil {
+				return err
+			}
+			rows, err := result.RowsAffected()
+			if err != nil {
+				return err
+			}
+			deleted += int(rows)
+			return nil
+		})
+		if err != nil {
+			return deleted, err
+		}
+	}
+
+	return deleted, nil
+}
+
+// clientIDs lista os clients (tabela sem RLS) para as operações que percorrem todos os tenants
+func (r *PostgresJobRepository) clientIDs(ctx context.Context) ([]string, error) {
+	rows, err := r.db.DB().QueryContext(ctx, `SELECT id FROM clients`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanJob(row rowScanner) (*domain.Job, error) {
+	job := &domain.Job{}
+	var payload, result, errorDetails sql.NullString
+
+	err := row.Scan(
+		&job.ID, &job.ClientID, &job.UserID, &job.Type, &job.Status, &payload, &result,
+		&job.Progress, &job.TotalItems, &job.ProcessedItems, &job.ErrorCount, &errorDetails,
+		&job.Attempts, &job.MaxAttempts, &job.StopRequested, &job.StartedAt, &job.CompletedAt, &job.CreatedAt, &job.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if payload.Valid {
+		job.Payload = json.RawMessage(payload.String)
+	}
+	if result.Valid {
+		job.Result = json.RawMessage(result.String)
+	}
+	if errorDetails.Valid {
+		job.ErrorDetails = json.RawMessage(errorDetails.String)
+	}
+
+	return job, nil
 }
@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"time"
 
 	areaDomain "agro-monitoring/internal/modules/area/domain"
 	"agro-monitoring/internal/modules/jobs/domain"
@@ -11,10 +12,30 @@ import (
 // JobUseCase define a interface para os casos de uso de jobs
 type JobUseCase interface {
 	CreateBulkAplicacoesJob(ctx context.Context, payload domain.BulkAplicacoesPayload) (*domain.Job, error)
+	CreateCSVImportJob(ctx context.Context, payload domain.CSVImportPayload) (*domain.Job, error)
 	GetJobStatus(ctx context.Context, jobID string) (*domain.Job, error)
+	ListJobs(ctx context.Context, filter domain.JobFilter, page, pageSize int) ([]*domain.Job, int, error)
+	// Cancelamento e pausa (somente bulk_aplicacoes); em processamento o worker para no próximo lote
+	CancelJob(ctx context.Context, jobID string) (*domain.Job, error)
+	PauseJob(ctx context.Context, jobID string) (*domain.Job, error)
+	ResumeJob(ctx context.Context, jobID string) (*domain.Job, error)
+	// Dead-letter queue (admin da plataforma, todos os tenants)
+	ListDeadJobs(ctx context.Context, page, pageSize int) ([]*domain.Job, int, error)
+	ReplayDeadJob(ctx context.Context, jobID string) (*domain.Job, error)
+	DiscardDeadJob(ctx context.Context, jobID string) error
+	// CleanupJobs apaga os jobs finalizados há mais de olderThan (agendamento cleanup_jobs)
+	CleanupJobs(ctx context.Context, olderThan time.Duration) (int, error)
+	// FailStaleJobs marca como failed os jobs pending/processing sem atualização há mais de staleAfter (cleanup_jobs)
+	FailStaleJobs(ctx context.Context, staleAfter time.Duration) (int, error)
+	RegisterProcessor(jobType domain.JobType, processor Processor)
 	RegisterAndProcessJobs(ctx context.Context)
 }
 
+// Processor executa um job de um tipo específico.
+// Retorna o resultado (nil = falha com os erros por item) ou um erro fatal. Erros fatais
+// temporários (domain.Retryable ou falhas de conexão com o banco) reenfileiram o job com backoff.
+type Processor func(ctx context.Context, job *domain.Job, progress domain.ProgressFunc) (interface{}, []domain.JobError, error)
+
 // Config contém as dependências para o usecase
 type Config struct {
 	UUIDGenerator func() string
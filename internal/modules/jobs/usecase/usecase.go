@@ -3,43 +3,123 @@ package usecase
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
+	"time"
 
 	areaDomain "agro-monitoring/internal/modules/area/domain"
 	"agro-monitoring/internal/modules/jobs/domain"
 	"agro-monitoring/internal/services/queue"
+	sharedContext "agro-monitoring/internal/shared/context"
+	"agro-monitoring/internal/shared/database"
+	sharedErrors "agro-monitoring/internal/shared/errors"
 )
 
 const (
 	QueueBulkAplicacoes = "jobs:bulk_aplicacoes"
+	QueueCSVImport      = "jobs:csv_import"
 )
 
+// jobQueues fila de cada tipo de job, na ordem de prioridade do worker
+var jobQueues = []struct {
+	jobType domain.JobType
+	queue   string
+}{
+	{domain.JobTypeBulkAplicacoes, QueueBulkAplicacoes},
+	{domain.JobTypeCSVImport, QueueCSVImport},
+}
+
+const (
+	// reaperInterval intervalo entre as verificações de workers sem heartbeat
+	reaperInterval = 30 * time.Second
+	// reconcileInterval intervalo entre as verificações de jobs presos em processing no banco
+	reconcileInterval = 5 * time.Minute
+	// staleJobAfter jobs em processing sem atualização há mais tempo que isso e fora das
+	// filas são considerados perdidos (ex.: worker que caiu antes da fila confiável)
+	staleJobAfter = 15 * time.Minute
+	// promoteInterval intervalo entre as verificações dos jobs agendados (retries com backoff)
+	promoteInterval = time.Second
+
+	// retryBaseDelay espera antes da segunda tentativa; dobra a cada nova falha até retryMaxDelay
+	retryBaseDelay = 30 * time.Second
+	retryMaxDelay  = 10 * time.Minute
+
+	// itemMaxAttempts tentativas de cada item do lote antes de registrá-lo como erro
+	itemMaxAttempts = 3
+	// itemRetryDelay espera antes de repetir um item com falha temporária; dobra a cada tentativa
+	itemRetryDelay = 200 * time.Millisecond
+	// itemBatchSize itens entre as atualizações de progresso e as verificações de cancelamento/pausa
+	itemBatchSize = 100
+)
+
+// errJobStopped o processador parou atendendo ao pedido de cancelamento/pausa (job.StopRequested)
+var errJobStopped = errors.New("job interrompido pelo usuário")
+
 type jobUseCase struct {
-	uuidGen  func() string
-	jobRepo  domain.JobRepository
-	areaRepo areaDomain.AreaMonitoramentoRepository
-	queue    queue.Service
+	uuidGen    func() string
+	jobRepo    domain.JobRepository
+	areaRepo   areaDomain.AreaMonitoramentoRepository
+	queue      queue.Service
+	processors map[domain.JobType]Processor
 }
 
 // NewJobUseCase cria um novo usecase de jobs
 func NewJobUseCase(cfg Config) JobUseCase {
-	return &jobUseCase{
-		uuidGen:  cfg.UUIDGenerator,
-		jobRepo:  cfg.JobRepo,
-		areaRepo: cfg.AreaRepo,
-		queue:    cfg.Queue,
+	uc := &jobUseCase{
+		uuidGen:    cfg.UUIDGenerator,
+		jobRepo:    cfg.JobRepo,
+		areaRepo:   cfg.AreaRepo,
+		queue:      cfg.Queue,
+		processors: make(map[domain.JobType]Processor),
+	}
+	uc.processors[domain.JobTypeBulkAplicacoes] = uc.processBulkAplicacoes
+	return uc
+}
+
+// RegisterProcessor associa um tipo de job ao seu processador (ex.: csv_import → monitoring)
+func (uc *jobUseCase) RegisterProcessor(jobType domain.JobType, processor Processor) {
+	uc.processors[jobType] = processor
+}
+
+// queueNames filas consumidas pelo worker, em ordem de prioridade
+func queueNames() []string {
+	names := make([]string, len(jobQueues))
+	for i, q := range jobQueues {
+		names[i] = q.queue
 	}
+	return names
+}
+
+// queueFor retorna a fila do tipo de job
+func queueFor(jobType domain.JobType) (string, bool) {
+	for _, q := range jobQueues {
+		if q.jobType == jobType {
+			return q.queue, true
+		}
+	}
+	return "", false
 }
 
 // CreateBulkAplicacoesJob cria um job para processar aplicações em massa
 func (uc *jobUseCase) CreateBulkAplicacoesJob(ctx context.Context, payload domain.BulkAplicacoesPayload) (*domain.Job, error) {
+	return uc.createJob(ctx, domain.JobTypeBulkAplicacoes, QueueBulkAplicacoes, payload, len(payload.Aplicacoes))
+}
+
+// CreateCSVImportJob cria um job para importar um CSV já salvo
+func (uc *jobUseCase) CreateCSVImportJob(ctx context.Context, payload domain.CSVImportPayload) (*domain.Job, error) {
+	return uc.createJob(ctx, domain.JobTypeCSVImport, QueueCSVImport, payload, 0)
+}
+
+// createJob salva o job e o enfileira na fila do seu tipo
+func (uc *jobUseCase) createJob(ctx context.Context, jobType domain.JobType, queueName string, payload interface{}, totalItems int) (*domain.Job, error) {
 	// Cria o job
-	job, err := domain.NewJob(uc.uuidGen(), domain.JobTypeBulkAplicacoes, payload)
+	job, err := domain.NewJob(uc.uuidGen(), jobType, payload)
 	if err != nil {
 		return nil, err
 	}
 
-	job.TotalItems = len(payload.Aplicacoes)
+	job.TotalItems = totalItems
 
 	// Salva no banco
 	if err := uc.jobRepo.Create(ctx, job); err != nil {
@@ -49,11 +129,11 @@ func (uc *jobUseCase) CreateBulkAplicacoesJob(ctx context.Context, payload domai
 	// Enfileira para processamento
 	queueJob := &queue.Job{
 		ID:        job.ID,
-		Queue:     QueueBulkAplicacoes,
+		Queue:     queueName,
 		JobEntity: job,
 	}
 
-	if err := uc.queue.Enqueue(ctx, queueJob, &queue.EnqueueOptions{QueueName: QueueBulkAplicacoes}); err != nil {
+	if err := uc.queue.Enqueue(ctx, queueJob, &queue.EnqueueOptions{QueueName: queueName}); err != nil {
 		// Atualiza status para falha se não conseguir enfileirar
 		job.Fail([]domain.JobError{{Message: "Falha ao enfileirar job: " + err.Error()}})
 		uc.jobRepo.Update(ctx, job)
@@ -68,9 +148,239 @@ func (uc *jobUseCase) GetJobStatus(ctx context.Context, jobID string) (*domain.J
 	return uc.jobRepo.GetByID(ctx, jobID)
 }
 
+// ListJobs lista os jobs do tenant com os filtros, do mais recente para o mais antigo
+func (uc *jobUseCase) ListJobs(ctx context.Context, filter domain.JobFilter, page, pageSize int) ([]*domain.Job, int, error) {
+	if filter.Status != "" && !filter.Status.IsValid() {
+		return nil, 0, fmt.Errorf("%w: status desconhecido %q", sharedErrors.ErrInvalidJobFilter, filter.Status)
+	}
+	if filter.Type != "" && !filter.Type.IsValid() {
+		return nil, 0, fmt.Errorf("%w: tipo desconhecido %q", sharedErrors.ErrInvalidJobFilter, filter.Type)
+	}
+	if filter.CreatedAfter != nil && filter.CreatedBefore != nil && !filter.CreatedAfter.Before(*filter.CreatedBefore) {
+		return nil, 0, fmt.Errorf("%w: created_from deve ser anterior a created_to", sharedErrors.ErrInvalidJobFilter)
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 20
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+
+	return uc.jobRepo.List(ctx, filter, pageSize, (page-1)*pageSize)
+}
+
+// ListDeadJobs lista os jobs que esgotaram as tentativas
+func (uc *jobUseCase) ListDeadJobs(ctx context.Context, page, pageSize int) ([]*domain.Job, int, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 20
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+
+	dead, total, err := uc.queue.DeadJobs(ctx, (page-1)*pageSize, pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	jobs := make([]*domain.Job, len(dead))
+	for i, queueJob := range dead {
+		jobs[i] = queueJob.JobEntity
+	}
+	return jobs, total, nil
+}
+
+// ReplayDeadJob retira o job da dead-letter queue e o reenfileira com as tentativas zeradas
+func (uc *jobUseCase) ReplayDeadJob(ctx context.Context, jobID string) (*domain.Job, error) {
+	dead, err := uc.queue.RemoveDead(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := uc.replay(ctx, dead.JobEntity)
+	if err != nil {
+		// Devolve para a dead-letter queue para não perder o job
+		if dlqErr := uc.queue.DeadLetter(ctx, dead); dlqErr != nil {
+			log.Printf("Erro ao devolver job %s para a dead-letter queue: %v", jobID, dlqErr)
+		}
+		return nil, err
+	}
+
+	log.Printf("Job %s reenfileirado a partir da dead-letter queue", job.ID)
+	return job, nil
+}
+
+// replay volta o job para pending no banco do seu tenant e o enfileira
+func (uc *jobUseCase) replay(ctx context.Context, dead *domain.Job) (*domain.Job, error) {
+	queueName, ok := queueFor(dead.Type)
+	if !ok {
+		return nil, fmt.Errorf("tipo de job desconhecido: %s", dead.Type)
+	}
+
+	// O admin não pertence ao tenant do job
+	jobCtx := sharedContext.WithTenant(ctx, dead.ClientID, dead.UserID)
+	job, err := uc.jobRepo.GetByID(jobCtx, dead.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	job.Replay()
+	if err := uc.jobRepo.Update(jobCtx, job); err != nil {
+		return nil, err
+	}
+
+	if err := uc.queue.Enqueue(jobCtx, &queue.Job{ID: job.ID, Queue: queueName, JobEntity: job}, &queue.EnqueueOptions{QueueName: queueName}); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// DiscardDeadJob remove o job da dead-letter queue; no banco ele continua failed
+func (uc *jobUseCase) DiscardDeadJob(ctx context.Context, jobID string) error {
+	_, err := uc.queue.RemoveDead(ctx, jobID)
+	return err
+}
+
+// CancelJob cancela o job; se já estiver em processamento, o worker para no próximo lote
+func (uc *jobUseCase) CancelJob(ctx context.Context, jobID string) (*domain.Job, error) {
+	return uc.stop(ctx, jobID, domain.JobStatusCancelled)
+}
+
+// PauseJob pausa o job; se já estiver em processamento, o worker para no próximo lote
+func (uc *jobUseCase) PauseJob(ctx context.Context, jobID string) (*domain.Job, error) {
+	return uc.stop(ctx, jobID, domain.JobStatusPaused)
+}
+
+// stop aplica o cancelamento/pausa direto no job parado ou o pede ao worker que o processa.
+// Se o worker mudar o status entre a leitura e a gravação, tenta de novo com o status atual
+func (uc *jobUseCase) stop(ctx context.Context, jobID string, target domain.JobStatus) (*domain.Job, error) {
+	for attempt := 0; attempt < 3; attempt++ {
+		job, err := uc.jobRepo.GetByID(ctx, jobID)
+		if err != nil {
+			return nil, err
+		}
+		if !job.CanBeControlled() {
+			return nil, sharedErrors.ErrJobNotControllable
+		}
+
+		switch {
+		case job.Status == domain.JobStatusProcessing:
+			requested, err := uc.jobRepo.RequestStop(ctx, job.ID, target)
+			if err != nil {
+				return nil, err
+			}
+			if requested {
+				job.StopRequested = target
+				log.Printf("Job %s: %s pedido, o worker para no próximo lote", job.ID, target)
+				return job, nil
+			}
+
+		case job.Status == domain.JobStatusPending || (job.Status == domain.JobStatusPaused && target == domain.JobStatusCancelled):
+			previous := job.Status
+			if err := job.Stop(target, nil, nil); err != nil {
+				return nil, err
+			}
+			updated, err := uc.jobRepo.UpdateIfStatus(ctx, job, previous)
+			if err != nil {
+				return nil, err
+			}
+			if updated {
+				// Se ainda estiver na fila, o worker o descarta pelo status
+				log.Printf("Job %s %s antes do processamento", job.ID, target)
+				return job, nil
+			}
+
+		default:
+			return nil, sharedErrors.ErrInvalidJobTransition
+		}
+	}
+	return nil, sharedErrors.ErrInvalidJobTransition
+}
+
+// ResumeJob volta o job pausado para a fila; o processamento continua do item em que parou
+func (uc *jobUseCase) ResumeJob(ctx context.Context, jobID string) (*domain.Job, error) {
+	job, err := uc.jobRepo.GetByID(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if !job.CanBeControlled() {
+		return nil, sharedErrors.ErrJobNotControllable
+	}
+	if job.Status != domain.JobStatusPaused {
+		return nil, sharedErrors.ErrInvalidJobTransition
+	}
+
+	job.Resume()
+	updated, err := uc.jobRepo.UpdateIfStatus(ctx, job, domain.JobStatusPaused)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, sharedErrors.ErrInvalidJobTransition
+	}
+
+	// Pausado antes do processamento: o job pode ainda estar na fila
+	queueName, _ := queueFor(job.Type)
+	queued, err := uc.queue.QueuedIDs(ctx, queueName)
+	if err != nil {
+		return nil, err
+	}
+	if !queued[job.ID] {
+		// Se falhar o job fica pending fora das filas e o reaper o reenfileira
+		if err := uc.queue.Enqueue(ctx, &queue.Job{ID: job.ID, Queue: queueName, JobEntity: job}, &queue.EnqueueOptions{QueueName: queueName}); err != nil {
+			return nil, err
+		}
+	}
+
+	log.Printf("Job %s retomado a partir do item %d", job.ID, job.ProcessedItems+1)
+	return job, nil
+}
+
+// CleanupJobs apaga os jobs finalizados há mais de olderThan
+func (uc *jobUseCase) CleanupJobs(ctx context.Context, olderThan time.Duration) (int, error) {
+	return uc.jobRepo.DeleteFinishedBefore(ctx, time.Now().Add(-olderThan))
+}
+
+// FailStaleJobs marca como failed os jobs parados em pending ou processing há mais de staleAfter.
+// O reaper reenfileira jobs perdidos; os que continuam parados depois disso não voltam sozinhos
+func (uc *jobUseCase) FailStaleJobs(ctx context.Context, staleAfter time.Duration) (int, error) {
+	failed := 0
+	for _, status := range []domain.JobStatus{domain.JobStatusPending, domain.JobStatusProcessing} {
+		stale, err := uc.jobRepo.ListStale(ctx, status, time.Now().Add(-staleAfter))
+		if err != nil {
+			return failed, err
+		}
+
+		for _, job := range stale {
+			jobCtx := sharedContext.WithTenant(ctx, job.ClientID, job.UserID)
+			job.Fail([]domain.JobError{{Message: fmt.Sprintf("job sem atualização em %s há mais de %s", status, staleAfter)}})
+			updated, err := uc.jobRepo.UpdateIfStatus(jobCtx, job, status)
+			if err != nil {
+				return failed, err
+			}
+			if updated {
+				failed++
+			}
+		}
+	}
+	return failed, nil
+}
+
 // RegisterAndProcessJobs inicia o worker para processar jobs (chamado pelo cmd/worker)
 func (uc *jobUseCase) RegisterAndProcessJobs(ctx context.Context) {
-	log.Println("Worker iniciado, aguardando jobs na fila:", QueueBulkAplicacoes)
+	queues := queueNames()
+	log.Println("Worker iniciado, aguardando jobs nas filas:", queues)
+
+	go uc.heartbeat(ctx)
+	go uc.runReaper(ctx, queues)
+	go uc.runPromoter(ctx, queues)
 
 	for {
 		select {
@@ -78,8 +388,8 @@ func (uc *jobUseCase) RegisterAndProcessJobs(ctx context.Context) {
 			log.Println("Worker encerrado")
 			return
 		default:
-			// Bloqueia esperando job na fila
-			queueJob, err := uc.queue.Dequeue(ctx, QueueBulkAplicacoes)
+			// Bloqueia esperando job em qualquer uma das filas
+			queueJob, err := uc.queue.Dequeue(ctx, queues...)
 			if err != nil {
 				if ctx.Err() != nil {
 					return // Context cancelado
@@ -89,82 +399,419 @@ func (uc *jobUseCase) RegisterAndProcessJobs(ctx context.Context) {
 			}
 
 			if queueJob == nil {
-				log.Println("Nothing in queue")
 				continue
 			}
 
-			if queueJob.JobEntity != nil {
-				uc.processJob(ctx, queueJob.JobEntity)
+			// O worker roda fora do HTTP: o tenant vem do próprio job
+			jobCtx := sharedContext.WithTenant(ctx, queueJob.JobEntity.ClientID, queueJob.JobEntity.UserID)
+			uc.processJob(jobCtx, queueJob.JobEntity)
+
+			// Encerramento no meio do job: devolve para a fila para outro worker retomar.
+			// O context do worker já foi cancelado, então a fila usa um novo
+			queueCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			if ctx.Err() != nil {
+				err = uc.queue.Nack(queueCtx, queueJob)
+			} else {
+				err = uc.queue.Ack(queueCtx, queueJob)
+			}
+			cancel()
+			if err != nil {
+				log.Printf("Erro ao confirmar job %s na fila: %v", queueJob.ID, err)
 			}
 		}
 	}
 }
 
-// processJob processa um job de aplicações
+// heartbeat renova o lease do worker enquanto ele roda, inclusive durante jobs longos
+func (uc *jobUseCase) heartbeat(ctx context.Context) {
+	ticker := time.NewTicker(queue.HeartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := uc.queue.Heartbeat(ctx); err != nil && ctx.Err() == nil {
+				log.Printf("Erro ao renovar lease do worker: %v", err)
+			}
+		}
+	}
+}
+
+// runPromoter move para as filas os jobs agendados cujo prazo venceu
+func (uc *jobUseCase) runPromoter(ctx context.Context, queues []string) {
+	ticker := time.NewTicker(promoteInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := uc.queue.PromoteDelayed(ctx, queues...); err != nil && ctx.Err() == nil {
+				log.Printf("Erro ao mover jobs agendados para as filas: %v", err)
+			}
+		}
+	}
+}
+
+// runReaper devolve para as filas os jobs de workers que pararam e reconcilia os jobs
+// presos em processing no banco
+func (uc *jobUseCase) runReaper(ctx context.Context, queues []string) {
+	ticker := time.NewTicker(reaperInterval)
+	defer ticker.Stop()
+	lastReconcile := time.Now()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			uc.requeueExpired(ctx, queues)
+			if time.Since(lastReconcile) >= reconcileInterval {
+				uc.reconcileStaleJobs(ctx, queues)
+				lastReconcile = time.Now()
+			}
+		}
+	}
+}
+
+// requeueExpired devolve os jobs de workers sem heartbeat e volta os jobs para pending
+func (uc *jobUseCase) requeueExpired(ctx context.Context, queues []string) {
+	requeued, err := uc.queue.RequeueExpired(ctx, queues...)
+	if err != nil {
+		log.Printf("Erro ao devolver jobs de workers parados: %v", err)
+	}
+
+	for _, queueJob := range requeued {
+		log.Printf("Job %s devolvido para a fila %s (worker sem heartbeat)", queueJob.ID, queueJob.Queue)
+
+		jobCtx := sharedContext.WithTenant(ctx, queueJob.JobEntity.ClientID, queueJob.JobEntity.UserID)
+		job, err := uc.jobRepo.GetByID(jobCtx, queueJob.ID)
+		if err != nil {
+			log.Printf("Erro ao buscar job devolvido %s: %v", queueJob.ID, err)
+			continue
+		}
+		if job.Status != domain.JobStatusProcessing {
+			continue
+		}
+		job.Requeue()
+		if err := uc.jobRepo.Update(jobCtx, job); err != nil {
+			log.Printf("Erro ao voltar job %s para pending: %v", job.ID, err)
+		}
+	}
+}
+
+// reconcileStaleJobs reenfileira os jobs pending ou processing no banco que não estão em nenhuma
+// fila, agendamento nem lista de processamento (o job se perdeu, ex.: worker que caiu com a fila
+// antiga ou falha do Redis ao reagendar uma nova tentativa)
+func (uc *jobUseCase) reconcileStaleJobs(ctx context.Context, queues []string) {
+	var stale []*domain.Job
+	for _, status := range []domain.JobStatus{domain.JobStatusProcessing, domain.JobStatusPending} {
+		jobs, err := uc.jobRepo.ListStale(ctx, status, time.Now().Add(-staleJobAfter))
+		if err != nil {
+			log.Printf("Erro ao buscar jobs presos em %s: %v", status, err)
+			return
+		}
+		stale = append(stale, jobs...)
+	}
+	if len(stale) == 0 {
+		return
+	}
+
+	queued, err := uc.queue.QueuedIDs(ctx, queues...)
+	if err != nil {
+		log.Printf("Erro ao ler as filas: %v", err)
+		return
+	}
+
+	for _, job := range stale {
+		if queued[job.ID] {
+			continue
+		}
+		queueName, ok := queueFor(job.Type)
+		if !ok {
+			continue
+		}
+
+		jobCtx := sharedContext.WithTenant(ctx, job.ClientID, job.UserID)
+		job.Requeue()
+		if err := uc.jobRepo.Update(jobCtx, job); err != nil {
+			log.Printf("Erro ao voltar job %s para pending: %v", job.ID, err)
+			continue
+		}
+		if err := uc.queue.Enqueue(jobCtx, &queue.Job{ID: job.ID, Queue: queueName, JobEntity: job}, &queue.EnqueueOptions{QueueName: queueName}); err != nil {
+			log.Printf("Erro ao reenfileirar job %s: %v", job.ID, err)
+			continue
+		}
+		log.Printf("Job %s perdido reenfileirado na fila %s", job.ID, queueName)
+	}
+}
+
+// processJob busca o job, despacha para o processador do seu tipo e grava o resultado
 func (uc *jobUseCase) processJob(ctx context.Context, job *domain.Job) {
 	log.Printf("Processando job %s tipo %s", job.ID, job.Type)
 
 	// Busca job atualizado do banco
-	job, err := uc.jobRepo.GetByID(ctx, job.ID)
+	jobID := job.ID
+	job, err := uc.jobRepo.GetByID(ctx, jobID)
 	if err != nil {
-		log.Printf("Erro ao buscar job %s: %v", job.ID, err)
+		log.Printf("Erro ao buscar job %s: %v", jobID, err)
 		return
 	}
 
-	// Parse do payload
-	var payload domain.BulkAplicacoesPayload
-	if err := json.Unmarshal(job.Payload, &payload); err != nil {
-		log.Printf("Erro ao parsear payload do job %s: %v", job.ID, err)
-		job.Fail([]domain.JobError{{Message: "Payload inválido: " + err.Error()}})
+	// Entrega repetida (at-least-once): o job terminou, mas o worker caiu antes do Ack.
+	// Jobs cancelados ou pausados antes do processamento continuam na fila e são descartados aqui
+	if job.IsFinished() || job.Status == domain.JobStatusPaused {
+		log.Printf("Job %s com status %s, ignorando", job.ID, job.Status)
+		return
+	}
+
+	processor, ok := uc.processors[job.Type]
+	if !ok {
+		log.Printf("Tipo de job desconhecido %s (job %s)", job.Type, job.ID)
+		job.Fail([]domain.JobError{{Message: "Tipo de job desconhecido: " + string(job.Type)}})
 		uc.jobRepo.Update(ctx, job)
 		return
 	}
 
-	// Marca como processando
-	job.Start(len(payload.Aplicacoes))
-	if err := uc.jobRepo.Update(ctx, job); err != nil {
+	// Entrega repetida de um job interrompido no meio (ex.: encerramento do worker): os erros
+	// dos itens já processados não foram gravados, então ele recomeça do início. Só o job
+	// retomado após pausa continua de processed_items
+	if job.Status == domain.JobStatusProcessing {
+		job.Requeue()
+	}
+
+	// Marca como processando, a menos que o job tenha sido cancelado ou pausado desde a leitura
+	job.Start(job.TotalItems)
+	started, err := uc.jobRepo.UpdateIfStatus(ctx, job, domain.JobStatusPending, domain.JobStatusProcessing)
+	if err != nil {
+		// Sem o status gravado o job não é processado; fora da fila após o Ack,
+		// ele é reenfileirado pelo reaper
 		log.Printf("Erro ao atualizar status do job %s para processing: %v", job.ID, err)
+		return
+	}
+	if !started {
+		log.Printf("Job %s cancelado ou pausado antes do processamento, ignorando", job.ID)
+		return
+	}
+
+	progress := func(total, processed, errorCount int) {
+		if total != job.TotalItems {
+			job.TotalItems = total
+			if err := uc.jobRepo.Update(ctx, job); err != nil {
+				log.Printf("Erro ao atualizar total do job %s: %v", job.ID, err)
+			}
+		}
+		job.ErrorCount = errorCount
+		job.UpdateProgress(processed)
+		uc.jobRepo.UpdateProgress(ctx, job.ID, job.ProcessedItems, job.ErrorCount)
+	}
+
+	result, jobErrors, err := processor(ctx, job, progress)
+
+	// Worker encerrado no meio do job: o Nack devolve o job, que recomeça em outro worker
+	if ctx.Err() != nil {
+		log.Printf("Job %s interrompido pelo encerramento do worker", job.ID)
+		return
+	}
+
+	if errors.Is(err, errJobStopped) {
+		uc.stopProcessing(ctx, job, job.StopRequested, result, jobErrors)
+		return
+	}
+
+	if err != nil && isRetryable(err) {
+		// Cancelado ou pausado durante a falha: não há nova tentativa
+		if target := uc.stopRequest(ctx, job); target != "" {
+			uc.stopProcessing(ctx, job, target, result, jobErrors)
+			return
+		}
+
+		jobErrors = append(jobErrors, domain.JobError{Message: err.Error()})
+		if job.CanRetry() {
+			uc.retryJob(ctx, job, jobErrors)
+			return
+		}
+		uc.deadLetter(ctx, job, jobErrors)
+		return
+	}
+
+	// Finaliza job
+	switch {
+	case err != nil:
+		job.Fail(append(jobErrors, domain.JobError{Message: err.Error()}))
+	case result == nil && len(jobErrors) > 0:
+		job.Fail(jobErrors)
+	default:
+		job.Complete(result, jobErrors)
+	}
+
+	if err := uc.jobRepo.Update(ctx, job); err != nil {
+		log.Printf("Erro ao atualizar job %s para status final: %v", job.ID, err)
+	}
+	log.Printf("Job %s finalizado com status %s: %d processados, %d erros", job.ID, job.Status, job.ProcessedItems, job.ErrorCount)
+}
+
+// stopRequest relê o job e retorna o cancelamento/pausa pedido pelo usuário, ou vazio
+func (uc *jobUseCase) stopRequest(ctx context.Context, job *domain.Job) domain.JobStatus {
+	current, err := uc.jobRepo.GetByID(ctx, job.ID)
+	if err != nil {
+		log.Printf("Erro ao verificar cancelamento do job %s: %v", job.ID, err)
+		return ""
+	}
+	return current.StopRequested
+}
+
+// stopProcessing grava o job interrompido pelo usuário com os itens já aplicados
+func (uc *jobUseCase) stopProcessing(ctx context.Context, job *domain.Job, target domain.JobStatus, result interface{}, jobErrors []domain.JobError) {
+	job.Stop(target, result, jobErrors)
+	if err := uc.jobRepo.Update(ctx, job); err != nil {
+		log.Printf("Erro ao atualizar job %s para %s: %v", job.ID, target, err)
+	}
+	log.Printf("Job %s %s pelo usuário após %d de %d itens", job.ID, target, job.ProcessedItems, job.TotalItems)
+}
+
+// isRetryable indica se a falha é temporária e vale uma nova tentativa
+func isRetryable(err error) bool {
+	return domain.IsRetryable(err) || database.IsTransient(err)
+}
+
+// retryDelay backoff exponencial a partir do número de tentativas já feitas
+func retryDelay(attempts int) time.Duration {
+	delay := retryBaseDelay
+	for i := 1; i < attempts && delay < retryMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > retryMaxDelay {
+		delay = retryMaxDelay
+	}
+	return delay
+}
+
+// retryJob volta o job para pending e o agenda na sua fila com backoff
+func (uc *jobUseCase) retryJob(ctx context.Context, job *domain.Job, jobErrors []domain.JobError) {
+	queueName, _ := queueFor(job.Type)
+	delay := retryDelay(job.Attempts)
+
+	job.Retry(jobErrors)
+	if err := uc.jobRepo.Update(ctx, job); err != nil {
+		log.Printf("Erro ao voltar job %s para pending: %v", job.ID, err)
 	}
 
-	var errors []domain.JobError
-	processed := 0
+	// Se o agendamento falhar o job fica pending fora das filas e o reaper o reenfileira
+	if err := uc.queue.Enqueue(ctx, &queue.Job{ID: job.ID, Queue: queueName, JobEntity: job}, &queue.EnqueueOptions{QueueName: queueName, Delay: delay}); err != nil {
+		log.Printf("Erro ao agendar nova tentativa do job %s: %v", job.ID, err)
+		return
+	}
+	log.Printf("Job %s falhou na tentativa %d de %d, nova tentativa em %s", job.ID, job.Attempts, job.MaxAttempts, delay)
+}
+
+// deadLetter marca como failed o job que esgotou as tentativas e o guarda na dead-letter queue
+func (uc *jobUseCase) deadLetter(ctx context.Context, job *domain.Job, jobErrors []domain.JobError) {
+	queueName, _ := queueFor(job.Type)
+
+	job.Fail(jobErrors)
+	if err := uc.jobRepo.Update(ctx, job); err != nil {
+		log.Printf("Erro ao atualizar job %s para status final: %v", job.ID, err)
+	}
+
+	if err := uc.queue.DeadLetter(ctx, &queue.Job{ID: job.ID, Queue: queueName, JobEntity: job}); err != nil {
+		log.Printf("Erro ao mover job %s para a dead-letter queue: %v", job.ID, err)
+		return
+	}
+	log.Printf("Job %s esgotou %d tentativas e foi movido para a dead-letter queue", job.ID, job.Attempts)
+}
+
+// processBulkAplicacoes processa um job de aplicações em massa. Entre os lotes verifica se o
+// usuário cancelou ou pausou o job; o job retomado continua do item em que parou
+func (uc *jobUseCase) processBulkAplicacoes(ctx context.Context, job *domain.Job, progress domain.ProgressFunc) (interface{}, []domain.JobError, error) {
+	var payload domain.BulkAplicacoesPayload
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		return nil, nil, fmt.Errorf("payload inválido: %w", err)
+	}
+
+	// Retomada após pausa: os itens até processed_items já foram processados
+	itemErrors, err := job.Errors()
+	if err != nil {
+		return nil, nil, fmt.Errorf("erros gravados inválidos: %w", err)
+	}
+	start := job.ProcessedItems
+	processed := start - len(itemErrors)
+	transient := 0
+	total := len(payload.Aplicacoes)
 
 	// Processa cada aplicação
-	for i, item := range payload.Aplicacoes {
-		err := uc.processAplicacao(ctx, item)
+	for i := start; i < total; i++ {
+		// Encerramento do worker: o job volta para a fila e recomeça em outro worker
+		if ctx.Err() != nil {
+			return nil, itemErrors, ctx.Err()
+		}
+		if i%itemBatchSize == 0 {
+			if target := uc.stopRequest(ctx, job); target != "" {
+				job.StopRequested = target
+				return domain.BulkAplicacoesResult{Processed: processed, Errors: len(itemErrors)}, itemErrors, errJobStopped
+			}
+		}
+
+		item := payload.Aplicacoes[i]
+		err := uc.applyAplicacao(ctx, item)
 		if err != nil {
-			errors = append(errors, domain.JobError{
+			itemErrors = append(itemErrors, domain.JobError{
 				Line:    i + 1,
 				ItemID:  item.AreaID,
 				Message: err.Error(),
 			})
-			job.AddError()
+			if isRetryable(err) {
+				transient++
+			}
 		} else {
 			processed++
 		}
 
-		// Atualiza progresso a cada 100 itens ou no final
-		if (i+1)%100 == 0 || i == len(payload.Aplicacoes)-1 {
-			job.UpdateProgress(processed + len(errors))
-			uc.jobRepo.UpdateProgress(ctx, job.ID, job.ProcessedItems, job.ErrorCount)
+		// Atualiza progresso a cada lote ou no final
+		if (i+1)%itemBatchSize == 0 || i == total-1 {
+			progress(total, processed+len(itemErrors), len(itemErrors))
 		}
 	}
 
-	// Finaliza job
-	if len(errors) > 0 && processed == 0 {
-		job.Fail(errors)
-	} else {
-		result := domain.BulkAplicacoesResult{
-			Processed: processed,
-			Errors:    len(errors),
-		}
-		job.Complete(result)
+	result := domain.BulkAplicacoesResult{
+		Processed: processed,
+		Errors:    len(itemErrors),
 	}
 
-	if err := uc.jobRepo.Update(ctx, job); err != nil {
-		log.Printf("Erro ao atualizar job %s para status final: %v", job.ID, err)
+	// Falha temporária em algum item: o job é repetido desde o início (o upsert por posição
+	// torna a repetição idempotente). Esgotadas as tentativas, o job com parte das aplicações
+	// processada é concluído com os erros por item; sem nenhuma, vai para a dead-letter queue
+	if transient > 0 && (processed == 0 || job.CanRetry()) {
+		return result, itemErrors, domain.Retryable(fmt.Errorf("%d aplicações com erro temporário", transient))
+	}
+
+	// Nenhuma aplicação processada: o job falha com os erros por item
+	if len(itemErrors) > 0 && processed == 0 {
+		return nil, itemErrors, nil
+	}
+
+	return result, itemErrors, nil
+}
+
+// applyAplicacao processa a aplicação repetindo as falhas temporárias com backoff
+func (uc *jobUseCase) applyAplicacao(ctx context.Context, item domain.AplicacaoItem) error {
+	delay := itemRetryDelay
+	for attempt := 1; ; attempt++ {
+		err := uc.processAplicacao(ctx, item)
+		if err == nil || attempt == itemMaxAttempts || !isRetryable(err) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(delay):
+		}
+		delay *= 2
 	}
-	log.Printf("Job %s concluído: %d processados, %d erros", job.ID, processed, len(errors))
 }
 
 // processAplicacao processa uma aplicação individual
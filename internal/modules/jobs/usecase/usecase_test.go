@@ -0,0 +1,504 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	areaDomain "agro-monitoring/internal/modules/area/domain"
+	areaRepo "agro-monitoring/internal/modules/area/repository"
+	"agro-monitoring/internal/modules/jobs/domain"
+	"agro-monitoring/internal/modules/jobs/repository"
+	"agro-monitoring/internal/services/queue"
+	sharedContext "agro-monitoring/internal/shared/context"
+	sharedErrors "agro-monitoring/internal/shared/errors"
+)
+
+var tenantCtx = sharedContext.WithTenant(context.Background(), "client-a", "user-a")
+
+func mockUUID() func() string {
+	counter := 0
+	return func() string {
+		counter++
+		return fmt.Sprintf("uuid-%d", counter)
+	}
+}
+
+// fakeQueue guarda em memória os jobs enfileirados e a dead-letter queue
+type fakeQueue struct {
+	mu       sync.Mutex
+	enqueued []*queue.Job
+	delays   []queue.EnqueueOptions
+	dead     []*queue.Job
+}
+
+func (q *fakeQueue) Enqueue(ctx context.Context, job *queue.Job, opts *queue.EnqueueOptions) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.enqueued = append(q.enqueued, job)
+	q.delays = append(q.delays, *opts)
+	return nil
+}
+
+func (q *fakeQueue) Dequeue(ctx context.Context, queueNames ...string) (*queue.Job, error) {
+	return nil, nil
+}
+
+func (q *fakeQueue) Ack(ctx context.Context, job *queue.Job) error  { return nil }
+func (q *fakeQueue) Nack(ctx context.Context, job *queue.Job) error { return nil }
+func (q *fakeQueue) Heartbeat(ctx context.Context) error            { return nil }
+
+func (q *fakeQueue) RequeueExpired(ctx context.Context, queueNames ...string) ([]*queue.Job, error) {
+	return nil, nil
+}
+
+func (q *fakeQueue) QueuedIDs(ctx context.Context, queueNames ...string) (map[string]bool, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	ids := make(map[string]bool)
+	for _, job := range q.enqueued {
+		ids[job.ID] = true
+	}
+	return ids, nil
+}
+
+func (q *fakeQueue) PromoteDelayed(ctx context.Context, queueNames ...string) (int, error) {
+	return 0, nil
+}
+
+func (q *fakeQueue) DeadLetter(ctx context.Context, job *queue.Job) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.dead = append(q.dead, job)
+	return nil
+}
+
+func (q *fakeQueue) DeadJobs(ctx context.Context, offset, limit int) ([]*queue.Job, int, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.dead, len(q.dead), nil
+}
+
+func (q *fakeQueue) RemoveDead(ctx context.Context, id string) (*queue.Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for i, job := range q.dead {
+		if job.ID == id {
+			q.dead = append(q.dead[:i], q.dead[i+1:]...)
+			return job, nil
+		}
+	}
+	return nil, sharedErrors.ErrJobNotFound
+}
+
+func (q *fakeQueue) Close() error { return nil }
+
+// failingStartRepo simula falha do banco ao marcar o job como processing
+type failingStartRepo struct {
+	*repository.InMemoryRepository
+}
+
+func (r *failingStartRepo) UpdateIfStatus(ctx context.Context, job *domain.Job, from ...domain.JobStatus) (bool, error) {
+	if job.Status == domain.JobStatusProcessing {
+		return false, errors.New("conexão perdida")
+	}
+	return r.InMemoryRepository.UpdateIfStatus(ctx, job, from...)
+}
+
+// flakyAreaRepo falha temporariamente ao gravar as áreas em transient
+type flakyAreaRepo struct {
+	areaDomain.AreaMonitoramentoRepository
+	transient map[string]bool
+}
+
+func (r *flakyAreaRepo) UpdatePragasData(ctx context.Context, id string, pragasData areaDomain.PragasData) error {
+	if r.transient[id] {
+		return domain.Retryable(errors.New("banco indisponível"))
+	}
+	return r.AreaMonitoramentoRepository.UpdatePragasData(ctx, id, pragasData)
+}
+
+// seedAreas cria as áreas com a praga Camalote presente
+func seedAreas(t *testing.T, repo areaDomain.AreaMonitoramentoRepository, ids ...string) {
+	areas := make([]*areaDomain.AreaMonitoramento, len(ids))
+	for i, id := range ids {
+		pragas := areaDomain.NewPragasData()
+		pragas.AddPraga("Camalote")
+		areas[i] = &areaDomain.AreaMonitoramento{ID: id, PragasData: pragas}
+	}
+	require.NoError(t, repo.CreateBatch(tenantCtx, areas))
+}
+
+// cancelBeforeStartRepo cancela o job logo antes do worker marcá-lo como processing
+type cancelBeforeStartRepo struct {
+	*repository.InMemoryRepository
+	cancel func(ctx context.Context, jobID string)
+}
+
+func (r *cancelBeforeStartRepo) UpdateIfStatus(ctx context.Context, job *domain.Job, from ...domain.JobStatus) (bool, error) {
+	if job.Status == domain.JobStatusProcessing {
+		r.cancel(ctx, job.ID)
+	}
+	return r.InMemoryRepository.UpdateIfStatus(ctx, job, from...)
+}
+
+func setupJobTest() (*jobUseCase, *repository.InMemoryRepository, *fakeQueue) {
+	jobRepo := repository.NewInMemoryRepository()
+	q := &fakeQueue{}
+	uc := NewJobUseCase(Config{
+		UUIDGenerator: mockUUID(),
+		JobRepo:       jobRepo,
+		AreaRepo:      areaRepo.NewInMemoryRepository(),
+		Queue:         q,
+	}).(*jobUseCase)
+	return uc, jobRepo, q
+}
+
+func bulkPayload(n int) domain.BulkAplicacoesPayload {
+	items := make([]domain.AplicacaoItem, n)
+	for i := range items {
+		items[i] = domain.AplicacaoItem{AreaID: fmt.Sprintf("area-%d", i), Praga: "Camalote", Posicao: 1, Herbicida: "Glifosato", Dose: 2}
+	}
+	return domain.BulkAplicacoesPayload{Aplicacoes: items}
+}
+
+func TestJobUseCase_CrossTenantIsolation(t *testing.T) {
+	uc, _, _ := setupJobTest()
+
+	job, err := uc.CreateBulkAplicacoesJob(tenantCtx, bulkPayload(1))
+	require.NoError(t, err)
+	assert.Equal(t, "client-a", job.ClientID)
+	assert.Equal(t, "user-a", job.UserID)
+
+	otherCtx := sharedContext.WithTenant(context.Background(), "client-b", "user-b")
+
+	_, err = uc.GetJobStatus(otherCtx, job.ID)
+	assert.Equal(t, sharedErrors.ErrJobNotFound, err)
+
+	_, err = uc.CancelJob(otherCtx, job.ID)
+	assert.Equal(t, sharedErrors.ErrJobNotFound, err)
+
+	list, total, err := uc.ListJobs(otherCtx, domain.JobFilter{}, 1, 20)
+	require.NoError(t, err)
+	assert.Equal(t, 0, total)
+	assert.Empty(t, list)
+
+	_, err = uc.GetJobStatus(context.Background(), job.ID)
+	assert.Equal(t, sharedErrors.ErrTenantRequired, err)
+}
+
+func TestJobUseCase_ProcessJob_StartUpdateError(t *testing.T) {
+	uc, jobRepo, _ := setupJobTest()
+	uc.jobRepo = &failingStartRepo{jobRepo}
+
+	called := false
+	uc.RegisterProcessor(domain.JobTypeBulkAplicacoes, func(ctx context.Context, job *domain.Job, progress domain.ProgressFunc) (interface{}, []domain.JobError, error) {
+		called = true
+		return nil, nil, nil
+	})
+
+	job, err := uc.CreateBulkAplicacoesJob(tenantCtx, bulkPayload(1))
+	require.NoError(t, err)
+
+	uc.processJob(tenantCtx, job)
+
+	assert.False(t, called)
+	stored, err := jobRepo.GetByID(tenantCtx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.JobStatusPending, stored.Status)
+}
+
+func TestRetryDelay(t *testing.T) {
+	tests := []struct {
+		attempts int
+		expected time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{5, 8 * time.Minute},
+		{6, 10 * time.Minute},
+		{50, 10 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, retryDelay(tt.attempts), "tentativas: %d", tt.attempts)
+	}
+}
+
+func TestJobUseCase_ProcessJob_RetryThenDeadLetter(t *testing.T) {
+	uc, jobRepo, q := setupJobTest()
+	uc.RegisterProcessor(domain.JobTypeBulkAplicacoes, func(ctx context.Context, job *domain.Job, progress domain.ProgressFunc) (interface{}, []domain.JobError, error) {
+		return nil, nil, domain.Retryable(errors.New("banco indisponível"))
+	})
+
+	job, err := uc.CreateBulkAplicacoesJob(tenantCtx, bulkPayload(1))
+	require.NoError(t, err)
+
+	for attempt := 1; attempt < domain.DefaultMaxAttempts; attempt++ {
+		uc.processJob(tenantCtx, job)
+
+		stored, err := jobRepo.GetByID(tenantCtx, job.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.JobStatusPending, stored.Status)
+		assert.Equal(t, attempt, stored.Attempts)
+		assert.Equal(t, retryDelay(attempt), q.delays[len(q.delays)-1].Delay)
+	}
+	assert.Empty(t, q.dead)
+
+	uc.processJob(tenantCtx, job)
+
+	stored, err := jobRepo.GetByID(tenantCtx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.JobStatusFailed, stored.Status)
+	assert.Equal(t, domain.DefaultMaxAttempts, stored.Attempts)
+	require.Len(t, q.dead, 1)
+	assert.Equal(t, job.ID, q.dead[0].ID)
+
+	jobErrors, err := stored.Errors()
+	require.NoError(t, err)
+	require.NotEmpty(t, jobErrors)
+	assert.Equal(t, "banco indisponível", jobErrors[len(jobErrors)-1].Message)
+}
+
+func TestJobUseCase_ProcessBulkAplicacoes_PartialSuccess(t *testing.T) {
+	uc, jobRepo, q := setupJobTest()
+	areas := areaRepo.NewInMemoryRepository()
+	seedAreas(t, areas, "area-0", "area-1")
+	uc.areaRepo = &flakyAreaRepo{AreaMonitoramentoRepository: areas, transient: map[string]bool{"area-1": true}}
+
+	// area-0 aplicada, area-1 com falha temporária, area-2 inexistente
+	job, err := uc.CreateBulkAplicacoesJob(tenantCtx, bulkPayload(3))
+	require.NoError(t, err)
+	job.MaxAttempts = 2
+	require.NoError(t, jobRepo.Update(tenantCtx, job))
+
+	// Falha temporária com tentativas sobrando: o job é repetido
+	uc.processJob(tenantCtx, job)
+
+	stored, err := jobRepo.GetByID(tenantCtx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.JobStatusPending, stored.Status)
+	assert.Len(t, q.enqueued, 2)
+
+	// Última tentativa: conclui com as aplicações feitas e os erros por item
+	uc.processJob(tenantCtx, job)
+
+	stored, err = jobRepo.GetByID(tenantCtx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.JobStatusCompleted, stored.Status)
+	assert.Empty(t, q.dead)
+	assert.Equal(t, 2, stored.ErrorCount)
+	assert.JSONEq(t, `{"processed":1,"errors":2}`, string(stored.Result))
+
+	jobErrors, err := stored.Errors()
+	require.NoError(t, err)
+	require.Len(t, jobErrors, 2)
+	assert.Equal(t, "area-1", jobErrors[0].ItemID)
+	assert.Equal(t, "area-2", jobErrors[1].ItemID)
+}
+
+func TestJobUseCase_FailStaleJobs(t *testing.T) {
+	uc, jobRepo, _ := setupJobTest()
+
+	stale, err := uc.CreateBulkAplicacoesJob(tenantCtx, bulkPayload(1))
+	require.NoError(t, err)
+	stale.UpdatedAt = time.Now().Add(-48 * time.Hour)
+	require.NoError(t, jobRepo.Update(tenantCtx, stale))
+
+	recent, err := uc.CreateBulkAplicacoesJob(tenantCtx, bulkPayload(1))
+	require.NoError(t, err)
+
+	failed, err := uc.FailStaleJobs(context.Background(), 24*time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, 1, failed)
+
+	stored, err := jobRepo.GetByID(tenantCtx, stale.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.JobStatusFailed, stored.Status)
+	assert.NotNil(t, stored.CompletedAt)
+
+	stored, err = jobRepo.GetByID(tenantCtx, recent.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.JobStatusPending, stored.Status)
+}
+
+// interruptedJob grava o job como se a primeira aplicação tivesse falhado e a segunda
+// sido aplicada antes da interrupção
+func interruptedJob(t *testing.T, uc *jobUseCase, jobRepo *repository.InMemoryRepository, status domain.JobStatus) *domain.Job {
+	job, err := uc.CreateBulkAplicacoesJob(tenantCtx, bulkPayload(3))
+	require.NoError(t, err)
+
+	job.Start(3)
+	job.UpdateProgress(2)
+	job.ErrorCount = 1
+	job.ErrorDetails = json.RawMessage(`[{"line":1,"item_id":"area-0","message":"área indisponível"}]`)
+	job.Status = status
+	require.NoError(t, jobRepo.Update(tenantCtx, job))
+	return job
+}
+
+func aplicacoes(t *testing.T, uc *jobUseCase, areaID string) int {
+	area, err := uc.areaRepo.GetByID(tenantCtx, areaID)
+	require.NoError(t, err)
+	return len(area.PragasData.Pragas["Camalote"].Aplicacoes)
+}
+
+func TestJobUseCase_ResumeJob_ContinuesFromProcessedItems(t *testing.T) {
+	uc, jobRepo, _ := setupJobTest()
+	seedAreas(t, uc.areaRepo, "area-0", "area-1", "area-2")
+	job := interruptedJob(t, uc, jobRepo, domain.JobStatusPaused)
+
+	_, err := uc.ResumeJob(tenantCtx, job.ID)
+	require.NoError(t, err)
+	uc.processJob(tenantCtx, job)
+
+	stored, err := jobRepo.GetByID(tenantCtx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.JobStatusCompleted, stored.Status)
+	assert.Equal(t, 0, aplicacoes(t, uc, "area-0"))
+	assert.Equal(t, 1, aplicacoes(t, uc, "area-2"))
+
+	// O erro de antes da pausa continua no job
+	assert.JSONEq(t, `{"processed":2,"errors":1}`, string(stored.Result))
+	jobErrors, err := stored.Errors()
+	require.NoError(t, err)
+	require.Len(t, jobErrors, 1)
+	assert.Equal(t, "area-0", jobErrors[0].ItemID)
+}
+
+func TestJobUseCase_ProcessJob_RedeliveryRestarts(t *testing.T) {
+	uc, jobRepo, _ := setupJobTest()
+	seedAreas(t, uc.areaRepo, "area-0", "area-1", "area-2")
+	job := interruptedJob(t, uc, jobRepo, domain.JobStatusProcessing)
+
+	uc.processJob(tenantCtx, job)
+
+	stored, err := jobRepo.GetByID(tenantCtx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.JobStatusCompleted, stored.Status)
+	assert.JSONEq(t, `{"processed":3,"errors":0}`, string(stored.Result))
+	assert.Empty(t, stored.ErrorDetails)
+	for _, id := range []string{"area-0", "area-1", "area-2"} {
+		assert.Equal(t, 1, aplicacoes(t, uc, id), id)
+	}
+}
+
+func TestJobUseCase_ProcessJob_CancelledBeforeStart(t *testing.T) {
+	uc, jobRepo, _ := setupJobTest()
+	uc.jobRepo = &cancelBeforeStartRepo{
+		InMemoryRepository: jobRepo,
+		cancel: func(ctx context.Context, jobID string) {
+			_, err := uc.CancelJob(ctx, jobID)
+			require.NoError(t, err)
+		},
+	}
+
+	called := false
+	uc.RegisterProcessor(domain.JobTypeBulkAplicacoes, func(ctx context.Context, job *domain.Job, progress domain.ProgressFunc) (interface{}, []domain.JobError, error) {
+		called = true
+		return nil, nil, nil
+	})
+
+	job, err := uc.CreateBulkAplicacoesJob(tenantCtx, bulkPayload(1))
+	require.NoError(t, err)
+
+	uc.processJob(tenantCtx, job)
+
+	assert.False(t, called)
+	stored, err := jobRepo.GetByID(tenantCtx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.JobStatusCancelled, stored.Status)
+}
+
+func TestJobUseCase_ProcessBulkAplicacoes_StopsOnShutdown(t *testing.T) {
+	uc, _, _ := setupJobTest()
+	seedAreas(t, uc.areaRepo, "area-0")
+
+	job, err := uc.CreateBulkAplicacoesJob(tenantCtx, bulkPayload(1))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(tenantCtx)
+	cancel()
+	_, _, err = uc.processBulkAplicacoes(ctx, job, func(total, processed, errorCount int) {})
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 0, aplicacoes(t, uc, "area-0"))
+}
+
+func TestJobUseCase_ListJobs_InvalidFilter(t *testing.T) {
+	uc, _, _ := setupJobTest()
+	now := time.Now()
+	earlier := now.Add(-time.Hour)
+
+	filters := []domain.JobFilter{
+		{Status: "executando"},
+		{Type: "relatorio"},
+		{CreatedAfter: &now, CreatedBefore: &earlier},
+		{CreatedAfter: &now, CreatedBefore: &now},
+	}
+
+	for _, filter := range filters {
+		_, _, err := uc.ListJobs(tenantCtx, filter, 1, 20)
+		assert.ErrorIs(t, err, sharedErrors.ErrInvalidJobFilter, "%+v", filter)
+	}
+}
+
+func TestJobUseCase_ListJobs_Filters(t *testing.T) {
+	uc, jobRepo, _ := setupJobTest()
+	base := time.Now().Add(-72 * time.Hour)
+
+	// Três jobs em dias seguidos; o do meio de outro usuário e o último de importação
+	bulk, err := uc.CreateBulkAplicacoesJob(tenantCtx, bulkPayload(1))
+	require.NoError(t, err)
+	other, err := uc.CreateBulkAplicacoesJob(sharedContext.WithTenant(context.Background(), "client-a", "user-b"), bulkPayload(1))
+	require.NoError(t, err)
+	csvJob, err := uc.CreateCSVImportJob(tenantCtx, domain.CSVImportPayload{MonitoramentoID: "mon-1"})
+	require.NoError(t, err)
+
+	for i, job := range []*domain.Job{bulk, other, csvJob} {
+		job.CreatedAt = base.Add(time.Duration(i) * 24 * time.Hour)
+		require.NoError(t, jobRepo.Update(tenantCtx, job))
+	}
+	bulk.Fail(nil)
+	require.NoError(t, jobRepo.Update(tenantCtx, bulk))
+
+	ids := func(filter domain.JobFilter, page, pageSize int) ([]string, int) {
+		jobs, total, err := uc.ListJobs(tenantCtx, filter, page, pageSize)
+		require.NoError(t, err)
+		result := make([]string, len(jobs))
+		for i, job := range jobs {
+			result[i] = job.ID
+		}
+		return result, total
+	}
+
+	list, total := ids(domain.JobFilter{}, 1, 20)
+	assert.Equal(t, 3, total)
+	assert.Equal(t, []string{csvJob.ID, other.ID, bulk.ID}, list)
+
+	list, _ = ids(domain.JobFilter{Status: domain.JobStatusFailed}, 1, 20)
+	assert.Equal(t, []string{bulk.ID}, list)
+
+	list, _ = ids(domain.JobFilter{Type: domain.JobTypeCSVImport}, 1, 20)
+	assert.Equal(t, []string{csvJob.ID}, list)
+
+	list, _ = ids(domain.JobFilter{UserID: "user-b"}, 1, 20)
+	assert.Equal(t, []string{other.ID}, list)
+
+	// created_from inclusivo, created_to exclusivo
+	from, to := base.Add(24*time.Hour), base.Add(48*time.Hour)
+	list, _ = ids(domain.JobFilter{CreatedAfter: &from, CreatedBefore: &to}, 1, 20)
+	assert.Equal(t, []string{other.ID}, list)
+
+	list, total = ids(domain.JobFilter{}, 2, 2)
+	assert.Equal(t, 3, total)
+	assert.Equal(t, []string{bulk.ID}, list)
+}
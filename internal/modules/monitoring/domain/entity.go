@@ -1,7 +1,11 @@
 package domain
 
 import (
+	"path/filepath"
+	"strings"
 	"time"
+
+	areaDomain "agro-monitoring/internal/modules/area/domain"
 )
 
 // MonitoramentoStatus representa o status do processamento
@@ -22,15 +26,96 @@ func (s MonitoramentoStatus) IsValid() bool {
 	return false
 }
 
+// ImportMode define como as linhas do CSV se relacionam com áreas já importadas
+type ImportMode string
+
+const (
+	// ImportModeAppend cria observações sem vínculo com importações anteriores
+	ImportModeAppend ImportMode = "append"
+	// ImportModeUpsert vincula cada linha à área física com a mesma chave externa, criando-a se necessário
+	ImportModeUpsert ImportMode = "upsert"
+)
+
+// IsValid verifica se o modo de importação é válido
+func (m ImportMode) IsValid() bool {
+	switch m {
+	case ImportModeAppend, ImportModeUpsert:
+		return true
+	}
+	return false
+}
+
+// FileFormat formato do arquivo enviado
+type FileFormat string
+
+const (
+	FileFormatCSV  FileFormat = "csv"
+	FileFormatXLSX FileFormat = "xlsx"
+)
+
+// xlsxContentType content type de planilhas do Excel 2007+
+const xlsxContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+
+// ContentType content type usado no download de arquivos do formato
+func (f FileFormat) ContentType() string {
+	if f == FileFormatXLSX {
+		return xlsxContentType
+	}
+	return "text/csv"
+}
+
+// DetectFileFormat identifica XLSX pelo content type ou pela extensão; o restante é tratado como CSV
+func DetectFileFormat(filename, contentType string) FileFormat {
+	if strings.HasPrefix(strings.ToLower(contentType), xlsxContentType) ||
+		strings.EqualFold(filepath.Ext(filename), ".xlsx") {
+		return FileFormatXLSX
+	}
+	return FileFormatCSV
+}
+
 // Monitoramento representa um upload de CSV
 type Monitoramento struct {
 	ID          string
+	ClientID    string
+	UserID      string
 	DataUpload  time.Time
 	NomeArquivo string
 	Status      MonitoramentoStatus
 	TotalLinhas int
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
+	TotalErros  int
+	// Checksum SHA-256 (hex) do conteúdo enviado, usado para detectar uploads repetidos
+	Checksum string
+	// FileKey chave do arquivo original no storage ("<client_id>/<id>.<ext>"); vazio se não foi guardado
+	FileKey string
+	// Cabecalho header original do arquivo, usado no CSV de erros
+	Cabecalho []string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// MonitoramentoErro linha do CSV que não pôde ser importada
+type MonitoramentoErro struct {
+	MonitoramentoID string
+	Linha           int
+	Erro            string
+	// Registro valores originais da linha, na ordem do cabeçalho
+	Registro []string
+}
+
+// ImportPreview resultado do parsing de um arquivo sem gravar nada (dry-run)
+type ImportPreview struct {
+	Format    FileFormat
+	Separador string // vazio em planilhas
+	Encoding  string
+	Header    []string
+	// Colunas campo fixo -> coluna do arquivo
+	Colunas     map[string]string
+	Pragas      []string
+	TotalLinhas int
+	TotalErros  int
+	// Areas e Erros trazem apenas as primeiras linhas
+	Areas []*areaDomain.AreaMonitoramento
+	Erros []*MonitoramentoErro
 }
 
 // NewMonitoramento cria um novo monitoramento com status processando
@@ -59,3 +144,36 @@ func (m *Monitoramento) MarkAsError() {
 	m.Status = StatusErro
 	m.UpdatedAt = time.Now()
 }
+
+// ImportProfile perfil de importação do client: adapta o layout dos arquivos ao parser
+type ImportProfile struct {
+	ID       string
+	ClientID string
+	Nome     string
+	// Separador separador de colunas ("" detecta automaticamente, "tab" para TAB)
+	Separador string
+	// Encoding encoding do arquivo ("" = UTF-8)
+	Encoding string
+	// Colunas header do arquivo -> campo canônico
+	Colunas map[string]string
+	// Pragas header do arquivo -> nome da praga
+	Pragas map[string]string
+	// Vocabulario valor da célula de praga -> nível (A, B, M, X; vazio = ausente)
+	Vocabulario map[string]string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// NewImportProfile cria um novo perfil de importação
+func NewImportProfile(id, nome string) *ImportProfile {
+	now := time.Now()
+	return &ImportProfile{
+		ID:          id,
+		Nome:        strings.TrimSpace(nome),
+		Colunas:     make(map[string]string),
+		Pragas:      make(map[string]string),
+		Vocabulario: make(map[string]string),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
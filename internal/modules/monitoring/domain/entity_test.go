@@ -57,3 +57,30 @@ func TestMonitoramento_MarkAsError(t *testing.T) {
 	assert.Equal(t, StatusErro, m.Status)
 	assert.True(t, m.UpdatedAt.After(oldUpdatedAt) || m.UpdatedAt.Equal(oldUpdatedAt))
 }
+
+func TestImportMode_IsValid(t *testing.T) {
+	assert.True(t, ImportModeAppend.IsValid())
+	assert.True(t, ImportModeUpsert.IsValid())
+	assert.False(t, ImportMode("replace").IsValid())
+	assert.False(t, ImportMode("").IsValid())
+}
+
+func TestDetectFileFormat(t *testing.T) {
+	tests := []struct {
+		filename    string
+		contentType string
+		expected    FileFormat
+	}{
+		{"dados.csv", "text/csv", FileFormatCSV},
+		{"dados.XLSX", "application/octet-stream", FileFormatXLSX},
+		{"dados", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", FileFormatXLSX},
+		{"dados.xls", "", FileFormatCSV},
+		{"", "", FileFormatCSV},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filename, func(t *testing.T) {
+			assert.Equal(t, tt.expected, DetectFileFormat(tt.filename, tt.contentType))
+		})
+	}
+}
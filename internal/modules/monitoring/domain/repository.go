@@ -8,4 +8,30 @@ type MonitoramentoRepository interface {
 	GetByID(ctx context.Context, id string) (*Monitoramento, error)
 	List(ctx context.Context, limit, offset int) ([]*Monitoramento, int, error)
 	UpdateStatus(ctx context.Context, id string, status MonitoramentoStatus, totalLinhas int) error
+	// UpdateStatusIf atualiza o status somente se o status no banco for um dos informados;
+	// retorna false quando nenhuma linha mudou
+	UpdateStatusIf(ctx context.Context, id string, status MonitoramentoStatus, totalLinhas int, from ...MonitoramentoStatus) (bool, error)
+	// UpdateResumoErros grava o cabeçalho do arquivo e a quantidade de linhas rejeitadas
+	UpdateResumoErros(ctx context.Context, id string, cabecalho []string, totalErros int) error
+	// Delete remove o monitoramento; no banco as áreas e os erros são removidos em cascata
+	Delete(ctx context.Context, id string) error
+	// FindByChecksum retorna o monitoramento mais recente do tenant com o checksum e status informados
+	FindByChecksum(ctx context.Context, checksum string, status MonitoramentoStatus) (*Monitoramento, error)
+}
+
+// MonitoramentoErroRepository persiste os erros de parsing por linha
+type MonitoramentoErroRepository interface {
+	CreateBatch(ctx context.Context, erros []*MonitoramentoErro) error
+	ListByMonitoramento(ctx context.Context, monitoramentoID string) ([]*MonitoramentoErro, error)
+	DeleteByMonitoramento(ctx context.Context, monitoramentoID string) error
+}
+
+// ImportProfileRepository persiste os perfis de importação do client
+type ImportProfileRepository interface {
+	Create(ctx context.Context, p *ImportProfile) error
+	GetByID(ctx context.Context, id string) (*ImportProfile, error)
+	GetByNome(ctx context.Context, nome string) (*ImportProfile, error)
+	List(ctx context.Context) ([]*ImportProfile, error)
+	Update(ctx context.Context, p *ImportProfile) error
+	Delete(ctx context.Context, id string) error
 }
@@ -3,6 +3,7 @@ package dto
 import (
 	"time"
 
+	areaDto "agro-monitoring/internal/modules/area/dto"
 	"agro-monitoring/internal/modules/monitoring/domain"
 )
 
@@ -13,9 +14,50 @@ type MonitoramentoResponse struct {
 	NomeArquivo string    `json:"nome_arquivo"`
 	Status      string    `json:"status"`
 	TotalLinhas int       `json:"total_linhas"`
+	TotalErros  int       `json:"total_erros"`
+	Checksum    string    `json:"checksum,omitempty"`
 	CreatedAt   time.Time `json:"created_at"`
 }
 
+// MonitoramentoDetailResponse monitoramento com as linhas rejeitadas na importação
+type MonitoramentoDetailResponse struct {
+	MonitoramentoResponse
+	Erros []ErroResponse `json:"erros"`
+}
+
+// ErroResponse erro de parsing de uma linha do CSV
+type ErroResponse struct {
+	Linha int    `json:"linha"`
+	Erro  string `json:"erro"`
+}
+
+// UploadAcceptedResponse resposta do upload assíncrono
+type UploadAcceptedResponse struct {
+	Monitoramento MonitoramentoResponse `json:"monitoramento"`
+	JobID         string                `json:"job_id"`
+	Message       string                `json:"message"`
+}
+
+// DuplicateUploadResponse resposta 409 com o monitoramento que já importou o mesmo arquivo
+type DuplicateUploadResponse struct {
+	Message         string `json:"message"`
+	MonitoramentoID string `json:"monitoramento_id"`
+}
+
+// PreviewResponse resultado do dry-run de um upload; nada é gravado
+type PreviewResponse struct {
+	Formato     string                 `json:"formato"`
+	Separador   string                 `json:"separador,omitempty"`
+	Encoding    string                 `json:"encoding,omitempty"`
+	Header      []string               `json:"header"`
+	Colunas     map[string]string      `json:"colunas"`
+	Pragas      []string               `json:"pragas"`
+	TotalLinhas int                    `json:"total_linhas"`
+	TotalErros  int                    `json:"total_erros"`
+	Areas       []areaDto.AreaResponse `json:"areas"`
+	Erros       []ErroResponse         `json:"erros"`
+}
+
 // ListMonitoramentosResponse resposta paginada
 type ListMonitoramentosResponse struct {
 	Data       []MonitoramentoResponse `json:"data"`
@@ -32,10 +74,50 @@ func ToMonitoramentoResponse(m *domain.Monitoramento) MonitoramentoResponse {
 		NomeArquivo: m.NomeArquivo,
 		Status:      string(m.Status),
 		TotalLinhas: m.TotalLinhas,
+		TotalErros:  m.TotalErros,
+		Checksum:    m.Checksum,
 		CreatedAt:   m.CreatedAt,
 	}
 }
 
+// ToMonitoramentoDetailResponse converte monitoramento e erros para DTO
+func ToMonitoramentoDetailResponse(m *domain.Monitoramento, erros []*domain.MonitoramentoErro) MonitoramentoDetailResponse {
+	items := make([]ErroResponse, len(erros))
+	for i, e := range erros {
+		items[i] = ErroResponse{Linha: e.Linha, Erro: e.Erro}
+	}
+
+	return MonitoramentoDetailResponse{
+		MonitoramentoResponse: ToMonitoramentoResponse(m),
+		Erros:                 items,
+	}
+}
+
+// ToPreviewResponse converte a prévia para DTO
+func ToPreviewResponse(p *domain.ImportPreview) PreviewResponse {
+	areas := make([]areaDto.AreaResponse, len(p.Areas))
+	for i, a := range p.Areas {
+		areas[i] = areaDto.ToAreaResponse(a)
+	}
+	erros := make([]ErroResponse, len(p.Erros))
+	for i, e := range p.Erros {
+		erros[i] = ErroResponse{Linha: e.Linha, Erro: e.Erro}
+	}
+
+	return PreviewResponse{
+		Formato:     string(p.Format),
+		Separador:   p.Separador,
+		Encoding:    p.Encoding,
+		Header:      p.Header,
+		Colunas:     p.Colunas,
+		Pragas:      p.Pragas,
+		TotalLinhas: p.TotalLinhas,
+		TotalErros:  p.TotalErros,
+		Areas:       areas,
+		Erros:       erros,
+	}
+}
+
 // ToListMonitoramentosResponse converte lista para DTO
 func ToListMonitoramentosResponse(items []*domain.Monitoramento, page, pageSize, total int) ListMonitoramentosResponse {
 	data := make([]MonitoramentoResponse, len(items))
@@ -50,3 +132,50 @@ func ToListMonitoramentosResponse(items []*domain.Monitoramento, page, pageSize,
 		TotalCount: total,
 	}
 }
+
+// ImportProfileRequest request para criar/substituir um perfil de importação
+type ImportProfileRequest struct {
+	Nome        string            `json:"nome"`
+	Separador   string            `json:"separador"`
+	Encoding    string            `json:"encoding"`
+	Colunas     map[string]string `json:"colunas"`
+	Pragas      map[string]string `json:"pragas"`
+	Vocabulario map[string]string `json:"vocabulario"`
+}
+
+// ImportProfileResponse resposta de perfil de importação
+type ImportProfileResponse struct {
+	ID          string            `json:"id"`
+	Nome        string            `json:"nome"`
+	Separador   string            `json:"separador"`
+	Encoding    string            `json:"encoding"`
+	Colunas     map[string]string `json:"colunas"`
+	Pragas      map[string]string `json:"pragas"`
+	Vocabulario map[string]string `json:"vocabulario"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+}
+
+// ToImportProfileResponse converte domain para DTO
+func ToImportProfileResponse(p *domain.ImportProfile) ImportProfileResponse {
+	return ImportProfileResponse{
+		ID:          p.ID,
+		Nome:        p.Nome,
+		Separador:   p.Separador,
+		Encoding:    p.Encoding,
+		Colunas:     p.Colunas,
+		Pragas:      p.Pragas,
+		Vocabulario: p.Vocabulario,
+		CreatedAt:   p.CreatedAt,
+		UpdatedAt:   p.UpdatedAt,
+	}
+}
+
+// ToImportProfileListResponse converte lista para DTO
+func ToImportProfileListResponse(items []*domain.ImportProfile) []ImportProfileResponse {
+	data := make([]ImportProfileResponse, len(items))
+	for i, p := range items {
+		data[i] = ToImportProfileResponse(p)
+	}
+	return data
+}
@@ -2,64 +2,181 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"mime/multipart"
 	"net/http"
 	"strconv"
 
 	"github.com/go-chi/chi/v5"
 
+	clientsDomain "agro-monitoring/internal/modules/clients/domain"
+	"agro-monitoring/internal/modules/monitoring/domain"
 	"agro-monitoring/internal/modules/monitoring/dto"
 	"agro-monitoring/internal/modules/monitoring/usecase"
+	"agro-monitoring/internal/services/csv"
 	sharedErrors "agro-monitoring/internal/shared/errors"
+	sharedMiddleware "agro-monitoring/internal/shared/middleware"
 	"agro-monitoring/internal/shared/response"
 )
 
 // Handler handler para monitoramentos
 type Handler struct {
-	uc usecase.MonitoringUseCase
+	uc    usecase.MonitoringUseCase
+	roles sharedMiddleware.TenantRoleResolver
 }
 
-// NewHandler cria novo handler
-func NewHandler(uc usecase.MonitoringUseCase) *Handler {
-	return &Handler{uc: uc}
+// NewHandler cria novo handler; roles restringe a gestão de perfis de importação aos admins do tenant
+func NewHandler(uc usecase.MonitoringUseCase, roles sharedMiddleware.TenantRoleResolver) *Handler {
+	return &Handler{uc: uc, roles: roles}
 }
 
 // RegisterRoutes registra as rotas de monitoramento
 func (h *Handler) RegisterRoutes(r chi.Router) {
 	r.Route("/monitoramentos", func(r chi.Router) {
 		r.Post("/", h.Upload)
+		r.Post("/preview", h.Preview)
 		r.Get("/", h.List)
 		r.Get("/{id}", h.GetByID)
+		r.Delete("/{id}", h.Delete)
+		r.Post("/{id}/reprocess", h.Reprocess)
+		r.Get("/{id}/erros.csv", h.DownloadErros)
+		r.Get("/{id}/file", h.DownloadFile)
+		r.Get("/{id}/export", h.Export)
+	})
+
+	// Perfis de importação: leitura para todos do tenant, alteração apenas para admins
+	r.Route("/perfis-importacao", func(r chi.Router) {
+		r.Get("/", h.ListProfiles)
+		r.Get("/{id}", h.GetProfile)
+
+		r.Group(func(r chi.Router) {
+			r.Use(sharedMiddleware.RequireTenantRole(h.roles, clientsDomain.ClientUserRoleAdmin))
+			r.Post("/", h.CreateProfile)
+			r.Put("/{id}", h.UpdateProfile)
+			r.Delete("/{id}", h.DeleteProfile)
+		})
 	})
 }
 
-// Upload processa upload de CSV
+// Upload salva o arquivo (CSV ou XLSX) e agenda a importação em background (202 + job_id)
 func (h *Handler) Upload(w http.ResponseWriter, r *http.Request) {
-	if err := r.ParseMultipartForm(10 << 20); err != nil {
-		respondError(w, http.StatusBadRequest, "Erro ao processar formulário: "+err.Error())
+	file, header, opts, ok := readUploadForm(w, r)
+	if !ok {
 		return
 	}
+	defer file.Close()
 
-	file, header, err := r.FormFile("file")
+	mon, job, err := h.uc.UploadCSVAsync(r.Context(), file, header.Filename, opts)
 	if err != nil {
-		respondError(w, http.StatusBadRequest, "Arquivo 'file' não encontrado")
+		if err == sharedErrors.ErrDuplicateUpload {
+			respondJSON(w, http.StatusConflict, dto.DuplicateUploadResponse{
+				Message:         "Arquivo idêntico já importado. Envie force=true para importar novamente.",
+				MonitoramentoID: mon.ID,
+			})
+			return
+		}
+		if respondUploadError(w, err, opts) {
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "Erro ao agendar importação do CSV")
+		return
+	}
+
+	respondJSON(w, http.StatusAccepted, dto.UploadAcceptedResponse{
+		Monitoramento: dto.ToMonitoramentoResponse(mon),
+		JobID:         job.ID,
+		Message:       "Importação agendada. Use GET /v1/jobs/" + job.ID + " para acompanhar o progresso.",
+	})
+}
+
+// Preview faz o parsing do arquivo com as mesmas opções do upload, sem gravar nada,
+// para conferir o layout reconhecido e os erros antes de importar. limit=N áreas na resposta
+func (h *Handler) Preview(w http.ResponseWriter, r *http.Request) {
+	file, header, opts, ok := readUploadForm(w, r)
+	if !ok {
 		return
 	}
 	defer file.Close()
 
-	mon, err := h.uc.UploadAndProcessCSV(r.Context(), file, header.Filename)
+	limit, _ := strconv.Atoi(r.FormValue("limit"))
+
+	preview, err := h.uc.PreviewUpload(r.Context(), file, header.Filename, opts, limit)
 	if err != nil {
-		if err == sharedErrors.ErrInvalidCSV {
-			respondError(w, http.StatusBadRequest, "CSV inválido")
+		if respondUploadError(w, err, opts) {
+			return
+		}
+		// Header sem os campos obrigatórios, encoding ou separador que não permitem ler o arquivo
+		if errors.Is(err, sharedErrors.ErrInvalidCSV) {
+			respondError(w, http.StatusBadRequest, err.Error())
 			return
 		}
-		respondError(w, http.StatusInternalServerError, "Erro ao processar CSV")
+		respondError(w, http.StatusInternalServerError, "Erro ao analisar arquivo")
 		return
 	}
 
-	respondJSON(w, http.StatusCreated, dto.ToMonitoramentoResponse(mon))
+	respondJSON(w, http.StatusOK, dto.ToPreviewResponse(preview))
+}
+
+// readUploadForm lê o arquivo e as opções do formulário multipart; responde 400 se faltar o arquivo
+func readUploadForm(w http.ResponseWriter, r *http.Request) (multipart.File, *multipart.FileHeader, usecase.UploadOptions, bool) {
+	if err := r.ParseMultipartForm(10 << 20); err != nil {
+		respondError(w, http.StatusBadRequest, "Erro ao processar formulário: "+err.Error())
+		return nil, nil, usecase.UploadOptions{}, false
+	}
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Arquivo 'file' não encontrado")
+		return nil, nil, usecase.UploadOptions{}, false
+	}
+
+	opts := formUploadOptions(r)
+	opts.Format = domain.DetectFileFormat(header.Filename, header.Header.Get("Content-Type"))
+	return file, header, opts, true
+}
+
+// formUploadOptions opções de importação enviadas no formulário ou na query:
+// validation=strict rejeita linhas com números inválidos ou códigos de praga desconhecidos;
+// mode=upsert vincula as linhas às áreas já importadas com a mesma chave externa;
+// perfil=<nome> aplica o perfil de importação do client; planilha=<aba> escolhe a aba do XLSX;
+// encoding=windows-1252|iso-8859-1|utf-8 declara o encoding do CSV (padrão: detectar);
+// force=true importa mesmo um arquivo idêntico a uma importação concluída
+func formUploadOptions(r *http.Request) usecase.UploadOptions {
+	force, _ := strconv.ParseBool(r.FormValue("force"))
+	return usecase.UploadOptions{
+		Validation: csv.ValidationMode(r.FormValue("validation")),
+		Mode:       domain.ImportMode(r.FormValue("mode")),
+		Profile:    r.FormValue("perfil"),
+		Sheet:      r.FormValue("planilha"),
+		Encoding:   r.FormValue("encoding"),
+		Force:      force,
+	}
+}
+
+// respondUploadError responde 400 para opções de upload inválidas; false se o erro não é desse tipo
+func respondUploadError(w http.ResponseWriter, err error, opts usecase.UploadOptions) bool {
+	switch {
+	case err == sharedErrors.ErrInvalidValidationMode:
+		respondError(w, http.StatusBadRequest, "Parâmetro 'validation' deve ser 'lenient' ou 'strict'")
+	case err == sharedErrors.ErrInvalidImportMode:
+		respondError(w, http.StatusBadRequest, "Parâmetro 'mode' deve ser 'append' ou 'upsert'")
+	case err == sharedErrors.ErrInvalidEncoding:
+		respondError(w, http.StatusBadRequest, "Parâmetro 'encoding' deve ser 'utf-8', 'windows-1252' ou 'iso-8859-1'")
+	case err == sharedErrors.ErrImportProfileNotFound:
+		respondError(w, http.StatusBadRequest, "Perfil de importação '"+opts.Profile+"' não encontrado")
+	case errors.Is(err, sharedErrors.ErrInvalidSpreadsheet):
+		respondError(w, http.StatusBadRequest, err.Error())
+	default:
+		return false
+	}
+	return true
 }
 
-// GetByID retorna um monitoramento
+// GetByID retorna um monitoramento com as linhas rejeitadas na importação
 func (h *Handler) GetByID(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
 
@@ -73,7 +190,144 @@ func (h *Handler) GetByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	respondJSON(w, http.StatusOK, dto.ToMonitoramentoResponse(mon))
+	erros, err := h.uc.ListErros(r.Context(), id)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Erro interno")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, dto.ToMonitoramentoDetailResponse(mon, erros))
+}
+
+// Delete remove o monitoramento, suas áreas e o arquivo original
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	if err := h.uc.DeleteMonitoramento(r.Context(), id); err != nil {
+		if err == sharedErrors.ErrMonitoramentoNotFound {
+			respondError(w, http.StatusNotFound, "Monitoramento não encontrado")
+			return
+		}
+		if err == sharedErrors.ErrMonitoramentoProcessing {
+			respondError(w, http.StatusConflict, "Monitoramento em processamento; aguarde o fim da importação")
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "Erro ao remover monitoramento")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Reprocess agenda nova importação do arquivo original (ex.: após alterar o perfil de importação).
+// Aceita as mesmas opções do upload (validation, mode, perfil, planilha, encoding)
+func (h *Handler) Reprocess(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	opts := formUploadOptions(r)
+
+	mon, job, err := h.uc.ReprocessMonitoramento(r.Context(), id, opts)
+	if err != nil {
+		if err == sharedErrors.ErrMonitoramentoNotFound {
+			respondError(w, http.StatusNotFound, "Monitoramento não encontrado")
+			return
+		}
+		if err == sharedErrors.ErrMonitoramentoProcessing {
+			respondError(w, http.StatusConflict, "Monitoramento em processamento; aguarde o fim da importação")
+			return
+		}
+		if err == sharedErrors.ErrUploadFileNotFound {
+			respondError(w, http.StatusConflict, "Arquivo original do upload não está disponível para reprocessamento")
+			return
+		}
+		if respondUploadError(w, err, opts) {
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "Erro ao agendar reprocessamento")
+		return
+	}
+
+	respondJSON(w, http.StatusAccepted, dto.UploadAcceptedResponse{
+		Monitoramento: dto.ToMonitoramentoResponse(mon),
+		JobID:         job.ID,
+		Message:       "Reprocessamento agendado. Use GET /v1/jobs/" + job.ID + " para acompanhar o progresso.",
+	})
+}
+
+// DownloadErros baixa o CSV com as linhas rejeitadas e o motivo, para correção e reenvio
+func (h *Handler) DownloadErros(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	// Checa antes de escrever: depois dos headers não dá mais para responder com erro JSON
+	if _, err := h.uc.GetMonitoramento(r.Context(), id); err != nil {
+		if err == sharedErrors.ErrMonitoramentoNotFound {
+			respondError(w, http.StatusNotFound, "Monitoramento não encontrado")
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "Erro ao gerar CSV de erros")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="erros-%s.csv"`, id))
+	w.WriteHeader(http.StatusOK)
+	if err := h.uc.WriteErrosCSV(r.Context(), id, w); err != nil {
+		log.Printf("Erro ao enviar CSV de erros do monitoramento %s: %v", id, err)
+	}
+}
+
+// DownloadFile baixa o arquivo original enviado no upload
+func (h *Handler) DownloadFile(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	mon, file, err := h.uc.OpenUploadFile(r.Context(), id)
+	if err != nil {
+		if err == sharedErrors.ErrMonitoramentoNotFound || err == sharedErrors.ErrUploadFileNotFound {
+			respondError(w, http.StatusNotFound, "Arquivo do monitoramento não encontrado")
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "Erro ao abrir arquivo")
+		return
+	}
+	defer file.Close()
+
+	w.Header().Set("Content-Type", domain.DetectFileFormat(mon.FileKey, "").ContentType())
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": mon.NomeArquivo}))
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(w, file); err != nil {
+		log.Printf("Erro ao enviar arquivo do monitoramento %s: %v", id, err)
+	}
+}
+
+// Export gera um CSV com as áreas atuais do monitoramento, no layout aceito pelo upload
+func (h *Handler) Export(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	if format := r.URL.Query().Get("format"); format != "" && format != string(domain.FileFormatCSV) {
+		respondError(w, http.StatusBadRequest, "Formato de exportação inválido (use csv)")
+		return
+	}
+
+	// Checa antes de escrever: depois dos headers não dá mais para responder com erro JSON
+	mon, err := h.uc.GetMonitoramento(r.Context(), id)
+	if err != nil {
+		if err == sharedErrors.ErrMonitoramentoNotFound {
+			respondError(w, http.StatusNotFound, "Monitoramento não encontrado")
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "Erro ao exportar CSV")
+		return
+	}
+	if mon.Status == domain.StatusProcessando {
+		respondError(w, http.StatusConflict, "Monitoramento em processamento; aguarde o fim da importação")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="monitoramento-%s.csv"`, id))
+	w.WriteHeader(http.StatusOK)
+	if err := h.uc.ExportCSV(r.Context(), id, w); err != nil {
+		log.Printf("Erro ao exportar CSV do monitoramento %s: %v", id, err)
+	}
 }
 
 // List lista monitoramentos
@@ -90,6 +344,86 @@ func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, http.StatusOK, dto.ToListMonitoramentosResponse(items, page, pageSize, total))
 }
 
+// ListProfiles lista os perfis de importação do client
+func (h *Handler) ListProfiles(w http.ResponseWriter, r *http.Request) {
+	items, err := h.uc.ListImportProfiles(r.Context())
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Erro ao listar perfis")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, dto.ToImportProfileListResponse(items))
+}
+
+// GetProfile retorna um perfil de importação
+func (h *Handler) GetProfile(w http.ResponseWriter, r *http.Request) {
+	profile, err := h.uc.GetImportProfile(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		respondProfileError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, dto.ToImportProfileResponse(profile))
+}
+
+// CreateProfile cria um perfil de importação
+func (h *Handler) CreateProfile(w http.ResponseWriter, r *http.Request) {
+	var req dto.ImportProfileRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "JSON inválido")
+		return
+	}
+
+	profile, err := h.uc.CreateImportProfile(r.Context(), req)
+	if err != nil {
+		respondProfileError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, dto.ToImportProfileResponse(profile))
+}
+
+// UpdateProfile substitui um perfil de importação
+func (h *Handler) UpdateProfile(w http.ResponseWriter, r *http.Request) {
+	var req dto.ImportProfileRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "JSON inválido")
+		return
+	}
+
+	profile, err := h.uc.UpdateImportProfile(r.Context(), chi.URLParam(r, "id"), req)
+	if err != nil {
+		respondProfileError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, dto.ToImportProfileResponse(profile))
+}
+
+// DeleteProfile remove um perfil de importação
+func (h *Handler) DeleteProfile(w http.ResponseWriter, r *http.Request) {
+	if err := h.uc.DeleteImportProfile(r.Context(), chi.URLParam(r, "id")); err != nil {
+		respondProfileError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func respondProfileError(w http.ResponseWriter, err error) {
+	switch {
+	case err == sharedErrors.ErrImportProfileNotFound:
+		respondError(w, http.StatusNotFound, "Perfil de importação não encontrado")
+	case err == sharedErrors.ErrDuplicateImportProfile:
+		respondError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, sharedErrors.ErrInvalidImportProfile):
+		// A mensagem indica o campo inválido do perfil
+		respondError(w, http.StatusBadRequest, err.Error())
+	default:
+		respondError(w, http.StatusInternalServerError, "Erro interno")
+	}
+}
+
 func respondJSON(w http.ResponseWriter, status int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
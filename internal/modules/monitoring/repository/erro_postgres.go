@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"agro-monitoring/internal/modules/monitoring/domain"
+	sharedContext "agro-monitoring/internal/shared/context"
+	"agro-monitoring/internal/shared/database"
+)
+
+// PostgresErroRepository implementação PostgreSQL dos erros de parsing
+type PostgresErroRepository struct {
+	db *database.TenantDB
+}
+
+// NewPostgresErroRepository cria um novo repository de erros PostgreSQL
+func NewPostgresErroRepository(db *database.TenantDB) *PostgresErroRepository {
+	return &PostgresErroRepository{db: db}
+}
+
+func (r *PostgresErroRepository) CreateBatch(ctx context.Context, erros []*domain.MonitoramentoErro) error {
+	if len(erros) == 0 {
+		return nil
+	}
+
+	clientID, err := sharedContext.RequireClientID(ctx)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO monitoramento_erros (monitoramento_id, client_id, linha, erro, registro)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	return r.db.InTenant(ctx, clientID, func(tx *sql.Tx) error {
+		stmt, err := tx.PrepareContext(ctx, query)
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
+
+		for _, e := range erros {
+			registroJSON, err := json.Marshal(e.Registro)
+			if err != nil {
+				return fmt.Errorf("erro ao serializar registro: %w", err)
+			}
+
+			if _, err := stmt.ExecContext(ctx, e.MonitoramentoID, clientID, e.Linha, e.Erro, registroJSON); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (r *PostgresErroRepository) ListByMonitoramento(ctx context.Context, monitoramentoID string) ([]*domain.MonitoramentoErro, error) {
+	clientID, err := sharedContext.RequireClientID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT monitoramento_id, linha, erro, registro
+		FROM monitoramento_erros
+		WHERE monitoramento_id = $1 AND client_id = $2
+		ORDER BY linha
+	`
+
+	result := make([]*domain.MonitoramentoErro, 0)
+	err = r.db.InTenant(ctx, clientID, func(tx *sql.Tx) error {
+		rows, err := tx.QueryContext(ctx, query, monitoramentoID, clientID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			e := &domain.MonitoramentoErro{}
+			var registroJSON []byte
+			if err := rows.Scan(&e.MonitoramentoID, &e.Linha, &e.Erro, &registroJSON); err != nil {
+				return err
+			}
+			if err := json.Unmarshal(registroJSON, &e.Registro); err != nil {
+				return fmt.Errorf("erro ao ler registro: %w", err)
+			}
+			result = append(result, e)
+		}
+
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (r *PostgresErroRepository) DeleteByMonitoramento(ctx context.Context, monitoramentoID string) error {
+	clientID, err := sharedContext.RequireClientID(ctx)
+	if err != nil {
+		return err
+	}
+
+	query := `DELETE FROM monitoramento_erros WHERE monitoramento_id = $1 AND client_id = $2`
+
+	return r.db.InTenant(ctx, clientID, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, query, monitoramentoID, clientID)
+		return err
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"sync"
 
 	"agro-monitoring/internal/modules/monitoring/domain"
+	sharedContext "agro-monitoring/internal/shared/context"
 	sharedErrors "agro-monitoring/internal/shared/errors"
 )
 
@@ -22,31 +23,51 @@ func NewInMemoryRepository() *InMemoryRepository {
 }
 
 func (r *InMemoryRepository) Create(ctx context.Context, m *domain.Monitoramento) error {
+	clientID, err := sharedContext.RequireClientID(ctx)
+	if err != nil {
+		return err
+	}
+	userID, _ := sharedContext.GetUserID(ctx)
+
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	m.ClientID = clientID
+	m.UserID = userID
 	r.items[m.ID] = m
 	return nil
 }
 
 func (r *InMemoryRepository) GetByID(ctx context.Context, id string) (*domain.Monitoramento, error) {
+	clientID, err := sharedContext.RequireClientID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
 	m, ok := r.items[id]
-	if !ok {
+	if !ok || m.ClientID != clientID {
 		return nil, sharedErrors.ErrMonitoramentoNotFound
 	}
 	return m, nil
 }
 
 func (r *InMemoryRepository) List(ctx context.Context, limit, offset int) ([]*domain.Monitoramento, int, error) {
+	clientID, err := sharedContext.RequireClientID(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
 	all := make([]*domain.Monitoramento, 0, len(r.items))
 	for _, m := range r.items {
-		all = append(all, m)
+		if m.ClientID == clientID {
+			all = append(all, m)
+		}
 	}
 
 	total := len(all)
@@ -64,16 +85,104 @@ func (r *InMemoryRepository) List(ctx context.Context, limit, offset int) ([]*do
 }
 
 func (r *InMemoryRepository) UpdateStatus(ctx context.Context, id string, status domain.MonitoramentoStatus, totalLinhas int) error {
+	updated, err := r.UpdateStatusIf(ctx, id, status, totalLinhas)
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return sharedErrors.ErrMonitoramentoNotFound
+	}
+	return nil
+}
+
+func (r *InMemoryRepository) UpdateStatusIf(ctx context.Context, id string, status domain.MonitoramentoStatus, totalLinhas int, from ...domain.MonitoramentoStatus) (bool, error) {
+	clientID, err := sharedContext.RequireClientID(ctx)
+	if err != nil {
+		return false, err
+	}
+
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
 	m, ok := r.items[id]
-	if !ok {
-		return sharedErrors.ErrMonitoramentoNotFound
+	if !ok || m.ClientID != clientID || !hasStatus(m.Status, from) {
+		return false, nil
 	}
 
 	m.Status = status
 	m.TotalLinhas = totalLinhas
+	return true, nil
+}
+
+func hasStatus(status domain.MonitoramentoStatus, from []domain.MonitoramentoStatus) bool {
+	if len(from) == 0 {
+		return true
+	}
+	for _, s := range from {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *InMemoryRepository) UpdateResumoErros(ctx context.Context, id string, cabecalho []string, totalErros int) error {
+	clientID, err := sharedContext.RequireClientID(ctx)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	m, ok := r.items[id]
+	if !ok || m.ClientID != clientID {
+		return sharedErrors.ErrMonitoramentoNotFound
+	}
+
+	m.Cabecalho = cabecalho
+	m.TotalErros = totalErros
+	return nil
+}
+
+func (r *InMemoryRepository) FindByChecksum(ctx context.Context, checksum string, status domain.MonitoramentoStatus) (*domain.Monitoramento, error) {
+	clientID, err := sharedContext.RequireClientID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var found *domain.Monitoramento
+	for _, m := range r.items {
+		if m.ClientID != clientID || m.Checksum != checksum || m.Status != status {
+			continue
+		}
+		if found == nil || m.CreatedAt.After(found.CreatedAt) {
+			found = m
+		}
+	}
+	if found == nil {
+		return nil, sharedErrors.ErrMonitoramentoNotFound
+	}
+	return found, nil
+}
+
+func (r *InMemoryRepository) Delete(ctx context.Context, id string) error {
+	clientID, err := sharedContext.RequireClientID(ctx)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	m, ok := r.items[id]
+	if !ok || m.ClientID != clientID {
+		return sharedErrors.ErrMonitoramentoNotFound
+	}
+	delete(r.items, id)
 	return nil
 }
 
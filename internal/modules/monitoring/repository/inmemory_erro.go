@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"agro-monitoring/internal/modules/monitoring/domain"
+	sharedContext "agro-monitoring/internal/shared/context"
+)
+
+type inMemoryErro struct {
+	clientID string
+	erro     *domain.MonitoramentoErro
+}
+
+// InMemoryErroRepository implementação em memória dos erros de parsing para testes
+type InMemoryErroRepository struct {
+	mu    sync.RWMutex
+	items []inMemoryErro
+}
+
+// NewInMemoryErroRepository cria um novo repository de erros em memória
+func NewInMemoryErroRepository() *InMemoryErroRepository {
+	return &InMemoryErroRepository{}
+}
+
+func (r *InMemoryErroRepository) CreateBatch(ctx context.Context, erros []*domain.MonitoramentoErro) error {
+	clientID, err := sharedContext.RequireClientID(ctx)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, e := range erros {
+		r.items = append(r.items, inMemoryErro{clientID: clientID, erro: e})
+	}
+	return nil
+}
+
+func (r *InMemoryErroRepository) ListByMonitoramento(ctx context.Context, monitoramentoID string) ([]*domain.MonitoramentoErro, error) {
+	clientID, err := sharedContext.RequireClientID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]*domain.MonitoramentoErro, 0)
+	for _, item := range r.items {
+		if item.clientID == clientID && item.erro.MonitoramentoID == monitoramentoID {
+			result = append(result, item.erro)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].Linha < result[j].Linha })
+	return result, nil
+}
+
+func (r *InMemoryErroRepository) DeleteByMonitoramento(ctx context.Context, monitoramentoID string) error {
+	clientID, err := sharedContext.RequireClientID(ctx)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	kept := r.items[:0]
+	for _, item := range r.items {
+		if item.clientID != clientID || item.erro.MonitoramentoID != monitoramentoID {
+			kept = append(kept, item)
+		}
+	}
+	r.items = kept
+	return nil
+}
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"agro-monitoring/internal/modules/monitoring/domain"
+	sharedContext "agro-monitoring/internal/shared/context"
+	sharedErrors "agro-monitoring/internal/shared/errors"
+)
+
+// InMemoryProfileRepository implementação em memória dos perfis de importação para testes
+type InMemoryProfileRepository struct {
+	mu    sync.RWMutex
+	items map[string]*domain.ImportProfile
+}
+
+// NewInMemoryProfileRepository cria um novo repository de perfis em memória
+func NewInMemoryProfileRepository() *InMemoryProfileRepository {
+	return &InMemoryProfileRepository{
+		items: make(map[string]*domain.ImportProfile),
+	}
+}
+
+func (r *InMemoryProfileRepository) Create(ctx context.Context, p *domain.ImportProfile) error {
+	clientID, err := sharedContext.RequireClientID(ctx)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.nomeTaken(clientID, p.Nome, "") {
+		return sharedErrors.ErrDuplicateImportProfile
+	}
+
+	p.ClientID = clientID
+	r.items[p.ID] = p
+	return nil
+}
+
+func (r *InMemoryProfileRepository) GetByID(ctx context.Context, id string) (*domain.ImportProfile, error) {
+	clientID, err := sharedContext.RequireClientID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	p, ok := r.items[id]
+	if !ok || p.ClientID != clientID {
+		return nil, sharedErrors.ErrImportProfileNotFound
+	}
+	return p, nil
+}
+
+func (r *InMemoryProfileRepository) GetByNome(ctx context.Context, nome string) (*domain.ImportProfile, error) {
+	clientID, err := sharedContext.RequireClientID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, p := range r.items {
+		if p.ClientID == clientID && p.Nome == nome {
+			return p, nil
+		}
+	}
+	return nil, sharedErrors.ErrImportProfileNotFound
+}
+
+func (r *InMemoryProfileRepository) List(ctx context.Context) ([]*domain.ImportProfile, error) {
+	clientID, err := sharedContext.RequireClientID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]*domain.ImportProfile, 0)
+	for _, p := range r.items {
+		if p.ClientID == clientID {
+			result = append(result, p)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool { return strings.Compare(result[i].Nome, result[j].Nome) < 0 })
+	return result, nil
+}
+
+func (r *InMemoryProfileRepository) Update(ctx context.Context, p *domain.ImportProfile) error {
+	clientID, err := sharedContext.RequireClientID(ctx)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.items[p.ID]
+	if !ok || existing.ClientID != clientID {
+		return sharedErrors.ErrImportProfileNotFound
+	}
+	if r.nomeTaken(clientID, p.Nome, p.ID) {
+		return sharedErrors.ErrDuplicateImportProfile
+	}
+
+	p.ClientID = clientID
+	r.items[p.ID] = p
+	return nil
+}
+
+func (r *InMemoryProfileRepository) Delete(ctx context.Context, id string) error {
+	clientID, err := sharedContext.RequireClientID(ctx)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	p, ok := r.items[id]
+	if !ok || p.ClientID != clientID {
+		return sharedErrors.ErrImportProfileNotFound
+	}
+	delete(r.items, id)
+	return nil
+}
+
+func (r *InMemoryProfileRepository) nomeTaken(clientID, nome, exceptID string) bool {
+	for _, p := range r.items {
+		if p.ClientID == clientID && p.Nome == nome && p.ID != exceptID {
+			return true
+		}
+	}
+	return false
+}
@@ -3,57 +3,82 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"fmt"
 	"time"
 
+	"github.com/lib/pq"
+
 	"agro-monitoring/internal/modules/monitoring/domain"
+	sharedContext "agro-monitoring/internal/shared/context"
+	"agro-monitoring/internal/shared/database"
 	sharedErrors "agro-monitoring/internal/shared/errors"
 )
 
+const selectMonitoramentoColumns = `
+		SELECT id, client_id, COALESCE(user_id, ''), data_upload, nome_arquivo, status, total_linhas,
+			total_erros, cabecalho, COALESCE(checksum, ''), COALESCE(file_key, ''), created_at, updated_at
+		FROM monitoramentos`
+
 // PostgresRepository implementação PostgreSQL
 type PostgresRepository struct {
-	db *sql.DB
+	db *database.TenantDB
 }
 
 // NewPostgresRepository cria um novo repository PostgreSQL
-func NewPostgresRepository(db *sql.DB) *PostgresRepository {
+func NewPostgresRepository(db *database.TenantDB) *PostgresRepository {
 	return &PostgresRepository{db: db}
 }
 
 func (r *PostgresRepository) Create(ctx context.Context, m *domain.Monitoramento) error {
+	clientID, err := sharedContext.RequireClientID(ctx)
+	if err != nil {
+		return err
+	}
+	userID, _ := sharedContext.GetUserID(ctx)
+
+	m.ClientID = clientID
+	m.UserID = userID
+
 	query := `
-		INSERT INTO monitoramentos (id, data_upload, nome_arquivo, status, total_linhas, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO monitoramentos (id, client_id, user_id, data_upload, nome_arquivo, status, total_linhas, checksum, file_key, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''), NULLIF($9, ''), $10, $11)
 	`
 
-	_, err := r.db.ExecContext(ctx, query,
-		m.ID,
-		m.DataUpload,
-		m.NomeArquivo,
-		m.Status,
-		m.TotalLinhas,
-		m.CreatedAt,
-		m.UpdatedAt,
-	)
-	return err
+	return r.db.InTenant(ctx, clientID, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, query,
+			m.ID,
+			m.ClientID,
+			m.UserID,
+			m.DataUpload,
+			m.NomeArquivo,
+			m.Status,
+			m.TotalLinhas,
+			m.Checksum,
+			m.FileKey,
+			m.CreatedAt,
+			m.UpdatedAt,
+		)
+		return err
+	})
 }
 
 func (r *PostgresRepository) GetByID(ctx context.Context, id string) (*domain.Monitoramento, error) {
-	query := `
-		SELECT id, data_upload, nome_arquivo, status, total_linhas, created_at, updated_at
-		FROM monitoramentos
-		WHERE id = $1
+	clientID, err := sharedContext.RequireClientID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := selectMonitoramentoColumns + `
+		WHERE id = $1 AND client_id = $2
 	`
 
-	m := &domain.Monitoramento{}
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&m.ID,
-		&m.DataUpload,
-		&m.NomeArquivo,
-		&m.Status,
-		&m.TotalLinhas,
-		&m.CreatedAt,
-		&m.UpdatedAt,
-	)
+	var m *domain.Monitoramento
+	err = r.db.InTenant(ctx, clientID, func(tx *sql.Tx) error {
+		var err error
+		m, err = scanMonitoramento(tx.QueryRowContext(ctx, query, id, clientID))
+		return err
+	})
 
 	if err == sql.ErrNoRows {
 		return nil, sharedErrors.ErrMonitoramentoNotFound
@@ -66,64 +91,215 @@ func (r *PostgresRepository) GetByID(ctx context.Context, id string) (*domain.Mo
 }
 
 func (r *PostgresRepository) List(ctx context.Context, limit, offset int) ([]*domain.Monitoramento, int, error) {
-	var total int
-	countQuery := `SELECT COUNT(*) FROM monitoramentos`
-	if err := r.db.QueryRowContext(ctx, countQuery).Scan(&total); err != nil {
+	clientID, err := sharedContext.RequireClientID(ctx)
+	if err != nil {
 		return nil, 0, err
 	}
 
-	query := `
-		SELECT id, data_upload, nome_arquivo, status, total_linhas, created_at, updated_at
-		FROM monitoramentos
+	countQuery := `SELECT COUNT(*) FROM monitoramentos WHERE client_id = $1`
+	query := selectMonitoramentoColumns + `
+		WHERE client_id = $1
 		ORDER BY created_at DESC
-		LIMIT $1 OFFSET $2
+		LIMIT $2 OFFSET $3
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, limit, offset)
-	if err != nil {
-		return nil, 0, err
-	}
-	defer rows.Close()
-
+	var total int
 	var result []*domain.Monitoramento
-	for rows.Next() {
-		m := &domain.Monitoramento{}
-		if err := rows.Scan(
-			&m.ID,
-			&m.DataUpload,
-			&m.NomeArquivo,
-			&m.Status,
-			&m.TotalLinhas,
-			&m.CreatedAt,
-			&m.UpdatedAt,
-		); err != nil {
-			return nil, 0, err
+
+	err = r.db.InTenant(ctx, clientID, func(tx *sql.Tx) error {
+		if err := tx.QueryRowContext(ctx, countQuery, clientID).Scan(&total); err != nil {
+			return err
+		}
+
+		rows, err := tx.QueryContext(ctx, query, clientID, limit, offset)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			m, err := scanMonitoramento(rows)
+			if err != nil {
+				return err
+			}
+			result = append(result, m)
 		}
-		result = append(result, m)
+
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, 0, err
 	}
 
-	return result, total, rows.Err()
+	return result, total, nil
 }
 
 func (r *PostgresRepository) UpdateStatus(ctx context.Context, id string, status domain.MonitoramentoStatus, totalLinhas int) error {
+	updated, err := r.UpdateStatusIf(ctx, id, status, totalLinhas)
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return sharedErrors.ErrMonitoramentoNotFound
+	}
+	return nil
+}
+
+func (r *PostgresRepository) UpdateStatusIf(ctx context.Context, id string, status domain.MonitoramentoStatus, totalLinhas int, from ...domain.MonitoramentoStatus) (bool, error) {
+	clientID, err := sharedContext.RequireClientID(ctx)
+	if err != nil {
+		return false, err
+	}
+
 	query := `
 		UPDATE monitoramentos
 		SET status = $1, total_linhas = $2, updated_at = $3
-		WHERE id = $4
+		WHERE id = $4 AND client_id = $5
 	`
+	args := []interface{}{status, totalLinhas, time.Now(), id, clientID}
+	if len(from) > 0 {
+		statuses := make([]string, len(from))
+		for i, s := range from {
+			statuses[i] = string(s)
+		}
+		query += ` AND status = ANY($6)`
+		args = append(args, pq.Array(statuses))
+	}
+
+	var updated bool
+	err = r.db.InTenant(ctx, clientID, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		updated = rows > 0
+		return nil
+	})
+	return updated, err
+}
 
-	result, err := r.db.ExecContext(ctx, query, status, totalLinhas, time.Now(), id)
+func (r *PostgresRepository) Delete(ctx context.Context, id string) error {
+	clientID, err := sharedContext.RequireClientID(ctx)
 	if err != nil {
 		return err
 	}
 
-	rows, err := result.RowsAffected()
+	query := `DELETE FROM monitoramentos WHERE id = $1 AND client_id = $2`
+
+	return r.db.InTenant(ctx, clientID, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, query, id, clientID)
+		if err != nil {
+			return err
+		}
+
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return sharedErrors.ErrMonitoramentoNotFound
+		}
+
+		return nil
+	})
+}
+
+func (r *PostgresRepository) UpdateResumoErros(ctx context.Context, id string, cabecalho []string, totalErros int) error {
+	clientID, err := sharedContext.RequireClientID(ctx)
 	if err != nil {
 		return err
 	}
-	if rows == 0 {
-		return sharedErrors.ErrMonitoramentoNotFound
+
+	cabecalhoJSON, err := json.Marshal(cabecalho)
+	if err != nil {
+		return fmt.Errorf("erro ao serializar cabeçalho: %w", err)
 	}
 
-	return nil
+	query := `
+		UPDATE monitoramentos
+		SET cabecalho = $1, total_erros = $2, updated_at = $3
+		WHERE id = $4 AND client_id = $5
+	`
+
+	return r.db.InTenant(ctx, clientID, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, query, cabecalhoJSON, totalErros, time.Now(), id, clientID)
+		if err != nil {
+			return err
+		}
+
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return sharedErrors.ErrMonitoramentoNotFound
+		}
+
+		return nil
+	})
+}
+
+func (r *PostgresRepository) FindByChecksum(ctx context.Context, checksum string, status domain.MonitoramentoStatus) (*domain.Monitoramento, error) {
+	clientID, err := sharedContext.RequireClientID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := selectMonitoramentoColumns + `
+		WHERE client_id = $1 AND checksum = $2 AND status = $3
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+
+	var m *domain.Monitoramento
+	err = r.db.InTenant(ctx, clientID, func(tx *sql.Tx) error {
+		var err error
+		m, err = scanMonitoramento(tx.QueryRowContext(ctx, query, clientID, checksum, status))
+		return err
+	})
+
+	if err == sql.ErrNoRows {
+		return nil, sharedErrors.ErrMonitoramentoNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanMonitoramento(row rowScanner) (*domain.Monitoramento, error) {
+	m := &domain.Monitoramento{}
+	var cabecalhoJSON []byte
+	err := row.Scan(
+		&m.ID,
+		&m.ClientID,
+		&m.UserID,
+		&m.DataUpload,
+		&m.NomeArquivo,
+		&m.Status,
+		&m.TotalLinhas,
+		&m.TotalErros,
+		&cabecalhoJSON,
+		&m.Checksum,
+		&m.FileKey,
+		&m.CreatedAt,
+		&m.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(cabecalhoJSON, &m.Cabecalho); err != nil {
+		return nil, fmt.Errorf("erro ao ler cabeçalho: %w", err)
+	}
+	return m, nil
 }
@@ -0,0 +1,259 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"agro-monitoring/internal/modules/monitoring/domain"
+	sharedContext "agro-monitoring/internal/shared/context"
+	"agro-monitoring/internal/shared/database"
+	sharedErrors "agro-monitoring/internal/shared/errors"
+)
+
+const selectImportProfileColumns = `
+		SELECT id, client_id, nome, separador, encoding, colunas, pragas, vocabulario, created_at, updated_at
+		FROM import_profiles`
+
+// PostgresProfileRepository implementação PostgreSQL dos perfis de importação
+type PostgresProfileRepository struct {
+	db *database.TenantDB
+}
+
+// NewPostgresProfileRepository cria um novo repository de perfis PostgreSQL
+func NewPostgresProfileRepository(db *database.TenantDB) *PostgresProfileRepository {
+	return &PostgresProfileRepository{db: db}
+}
+
+func (r *PostgresProfileRepository) Create(ctx context.Context, p *domain.ImportProfile) error {
+	clientID, err := sharedContext.RequireClientID(ctx)
+	if err != nil {
+		return err
+	}
+	p.ClientID = clientID
+
+	colunas, pragas, vocabulario, err := marshalProfileMaps(p)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO import_profiles (id, client_id, nome, separador, encoding, colunas, pragas, vocabulario, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (client_id, nome) DO NOTHING
+	`
+
+	return r.db.InTenant(ctx, clientID, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, query,
+			p.ID,
+			p.ClientID,
+			p.Nome,
+			p.Separador,
+			p.Encoding,
+			colunas,
+			pragas,
+			vocabulario,
+			p.CreatedAt,
+			p.UpdatedAt,
+		)
+		if err != nil {
+			return err
+		}
+
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return sharedErrors.ErrDuplicateImportProfile
+		}
+		return nil
+	})
+}
+
+func (r *PostgresProfileRepository) GetByID(ctx context.Context, id string) (*domain.ImportProfile, error) {
+	return r.getOne(ctx, "id = $1", id)
+}
+
+func (r *PostgresProfileRepository) GetByNome(ctx context.Context, nome string) (*domain.ImportProfile, error) {
+	return r.getOne(ctx, "nome = $1", nome)
+}
+
+func (r *PostgresProfileRepository) getOne(ctx context.Context, where string, arg string) (*domain.ImportProfile, error) {
+	clientID, err := sharedContext.RequireClientID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := selectImportProfileColumns + `
+		WHERE ` + where + ` AND client_id = $2
+	`
+
+	var p *domain.ImportProfile
+	err = r.db.InTenant(ctx, clientID, func(tx *sql.Tx) error {
+		var err error
+		p, err = scanImportProfile(tx.QueryRowContext(ctx, query, arg, clientID))
+		return err
+	})
+
+	if err == sql.ErrNoRows {
+		return nil, sharedErrors.ErrImportProfileNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+func (r *PostgresProfileRepository) List(ctx context.Context) ([]*domain.ImportProfile, error) {
+	clientID, err := sharedContext.RequireClientID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := selectImportProfileColumns + `
+		WHERE client_id = $1
+		ORDER BY nome
+	`
+
+	result := make([]*domain.ImportProfile, 0)
+	err = r.db.InTenant(ctx, clientID, func(tx *sql.Tx) error {
+		rows, err := tx.QueryContext(ctx, query, clientID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			p, err := scanImportProfile(rows)
+			if err != nil {
+				return err
+			}
+			result = append(result, p)
+		}
+
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (r *PostgresProfileRepository) Update(ctx context.Context, p *domain.ImportProfile) error {
+	clientID, err := sharedContext.RequireClientID(ctx)
+	if err != nil {
+		return err
+	}
+
+	colunas, pragas, vocabulario, err := marshalProfileMaps(p)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE import_profiles
+		SET nome = $1, separador = $2, encoding = $3, colunas = $4, pragas = $5, vocabulario = $6, updated_at = $7
+		WHERE id = $8 AND client_id = $9
+		AND NOT EXISTS (
+			SELECT 1 FROM import_profiles o WHERE o.client_id = $9 AND o.nome = $1 AND o.id <> $8
+		)
+	`
+
+	return r.db.InTenant(ctx, clientID, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, query,
+			p.Nome, p.Separador, p.Encoding, colunas, pragas, vocabulario, p.UpdatedAt, p.ID, clientID,
+		)
+		if err != nil {
+			return err
+		}
+
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			var exists bool
+			if err := tx.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM import_profiles WHERE id = $1 AND client_id = $2)`, p.ID, clientID).Scan(&exists); err != nil {
+				return err
+			}
+			if exists {
+				return sharedErrors.ErrDuplicateImportProfile
+			}
+			return sharedErrors.ErrImportProfileNotFound
+		}
+		return nil
+	})
+}
+
+func (r *PostgresProfileRepository) Delete(ctx context.Context, id string) error {
+	clientID, err := sharedContext.RequireClientID(ctx)
+	if err != nil {
+		return err
+	}
+
+	query := `DELETE FROM import_profiles WHERE id = $1 AND client_id = $2`
+
+	return r.db.InTenant(ctx, clientID, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, query, id, clientID)
+		if err != nil {
+			return err
+		}
+
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return sharedErrors.ErrImportProfileNotFound
+		}
+		return nil
+	})
+}
+
+func marshalProfileMaps(p *domain.ImportProfile) (colunas, pragas, vocabulario []byte, err error) {
+	if colunas, err = json.Marshal(p.Colunas); err != nil {
+		return nil, nil, nil, fmt.Errorf("erro ao serializar colunas: %w", err)
+	}
+	if pragas, err = json.Marshal(p.Pragas); err != nil {
+		return nil, nil, nil, fmt.Errorf("erro ao serializar pragas: %w", err)
+	}
+	if vocabulario, err = json.Marshal(p.Vocabulario); err != nil {
+		return nil, nil, nil, fmt.Errorf("erro ao serializar vocabulário: %w", err)
+	}
+	return colunas, pragas, vocabulario, nil
+}
+
+func scanImportProfile(row rowScanner) (*domain.ImportProfile, error) {
+	p := &domain.ImportProfile{}
+	var colunas, pragas, vocabulario []byte
+	err := row.Scan(
+		&p.ID,
+		&p.ClientID,
+		&p.Nome,
+		&p.Separador,
+		&p.Encoding,
+		&colunas,
+		&pragas,
+		&vocabulario,
+		&p.CreatedAt,
+		&p.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(colunas, &p.Colunas); err != nil {
+		return nil, fmt.Errorf("erro ao ler colunas: %w", err)
+	}
+	if err := json.Unmarshal(pragas, &p.Pragas); err != nil {
+		return nil, fmt.Errorf("erro ao ler pragas: %w", err)
+	}
+	if err := json.Unmarshal(vocabulario, &p.Vocabulario); err != nil {
+		return nil, fmt.Errorf("erro ao ler vocabulário: %w", err)
+	}
+	return p, nil
+}
@@ -0,0 +1,113 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"agro-monitoring/internal/modules/monitoring/domain"
+	"agro-monitoring/internal/modules/monitoring/dto"
+	"agro-monitoring/internal/services/csv"
+	sharedErrors "agro-monitoring/internal/shared/errors"
+)
+
+// maxProfileNomeLength limite da coluna import_profiles.nome
+const maxProfileNomeLength = 100
+
+// CreateImportProfile cria um perfil de importação para o client do contexto
+func (uc *monitoringUseCase) CreateImportProfile(ctx context.Context, req dto.ImportProfileRequest) (*domain.ImportProfile, error) {
+	profile := domain.NewImportProfile(uc.uuidGenerator(), req.Nome)
+	applyProfileRequest(profile, req)
+
+	if err := validateProfile(profile); err != nil {
+		return nil, err
+	}
+
+	if err := uc.profileRepo.Create(ctx, profile); err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
+func (uc *monitoringUseCase) GetImportProfile(ctx context.Context, id string) (*domain.ImportProfile, error) {
+	return uc.profileRepo.GetByID(ctx, id)
+}
+
+func (uc *monitoringUseCase) ListImportProfiles(ctx context.Context) ([]*domain.ImportProfile, error) {
+	return uc.profileRepo.List(ctx)
+}
+
+// UpdateImportProfile substitui o conteúdo do perfil; jobs já agendados usam a nova versão
+func (uc *monitoringUseCase) UpdateImportProfile(ctx context.Context, id string, req dto.ImportProfileRequest) (*domain.ImportProfile, error) {
+	existing, err := uc.profileRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	profile := *existing
+	profile.Nome = strings.TrimSpace(req.Nome)
+	applyProfileRequest(&profile, req)
+	profile.UpdatedAt = time.Now()
+
+	if err := validateProfile(&profile); err != nil {
+		return nil, err
+	}
+
+	if err := uc.profileRepo.Update(ctx, &profile); err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+func (uc *monitoringUseCase) DeleteImportProfile(ctx context.Context, id string) error {
+	return uc.profileRepo.Delete(ctx, id)
+}
+
+func applyProfileRequest(p *domain.ImportProfile, req dto.ImportProfileRequest) {
+	p.Separador = req.Separador
+	p.Encoding = strings.ToLower(strings.TrimSpace(req.Encoding))
+	p.Colunas = nonNilMap(req.Colunas)
+	p.Pragas = nonNilMap(req.Pragas)
+	p.Vocabulario = nonNilMap(req.Vocabulario)
+}
+
+func nonNilMap(m map[string]string) map[string]string {
+	if m == nil {
+		return make(map[string]string)
+	}
+	return m
+}
+
+// validateProfile valida o nome e o mapeamento; o erro detalha o campo inválido
+func validateProfile(p *domain.ImportProfile) error {
+	if p.Nome == "" {
+		return fmt.Errorf("%w: nome obrigatório", sharedErrors.ErrInvalidImportProfile)
+	}
+	if len(p.Nome) > maxProfileNomeLength {
+		return fmt.Errorf("%w: nome deve ter no máximo %d caracteres", sharedErrors.ErrInvalidImportProfile, maxProfileNomeLength)
+	}
+
+	_, err := profileMapping(p)
+	return err
+}
+
+// profileMapping converte o perfil no mapeamento usado pelo parser
+func profileMapping(p *domain.ImportProfile) (*csv.ColumnMapping, error) {
+	separator, err := csv.ParseSeparator(p.Separador)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", sharedErrors.ErrInvalidImportProfile, err)
+	}
+
+	mapping := &csv.ColumnMapping{
+		Separator:   separator,
+		Encoding:    p.Encoding,
+		Columns:     p.Colunas,
+		Pragas:      p.Pragas,
+		Vocabulario: p.Vocabulario,
+	}
+	if err := mapping.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", sharedErrors.ErrInvalidImportProfile, err)
+	}
+	return mapping, nil
+}
@@ -1,74 +1,818 @@
 package usecase
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
 	"io"
+	"log"
+	"os"
 
 	areaDomain "agro-monitoring/internal/modules/area/domain"
+	jobsDomain "agro-monitoring/internal/modules/jobs/domain"
 	"agro-monitoring/internal/modules/monitoring/domain"
+	"agro-monitoring/internal/modules/monitoring/dto"
 	"agro-monitoring/internal/services/csv"
+	"agro-monitoring/internal/services/storage"
+	"agro-monitoring/internal/services/xlsx"
+	sharedContext "agro-monitoring/internal/shared/context"
+	sharedErrors "agro-monitoring/internal/shared/errors"
+)
+
+// csvImportBatchSize quantidade de áreas por CreateBatch na importação
+const csvImportBatchSize = 500
+
+// exportPageSize quantidade de áreas lidas por consulta na exportação
+const exportPageSize = 500
+
+// zipMagic assinatura dos arquivos zip (XLSX)
+var zipMagic = []byte("PK\x03\x04")
+
+// Limites da prévia de upload
+const (
+	previewDefaultLimit = 20
+	previewMaxLimit     = 100
+	previewMaxErros     = 100
 )
 
 // MonitoringUseCase interface para operações de monitoramento
 type MonitoringUseCase interface {
-	UploadAndProcessCSV(ctx context.Context, file io.Reader, filename string) (*domain.Monitoramento, error)
+	UploadCSVAsync(ctx context.Context, file io.Reader, filename string, opts UploadOptions) (*domain.Monitoramento, *jobsDomain.Job, error)
+	PreviewUpload(ctx context.Context, file io.Reader, filename string, opts UploadOptions, limit int) (*domain.ImportPreview, error)
+	ProcessCSVImport(ctx context.Context, job *jobsDomain.Job, progress jobsDomain.ProgressFunc) (interface{}, []jobsDomain.JobError, error)
+	ReprocessMonitoramento(ctx context.Context, id string, opts UploadOptions) (*domain.Monitoramento, *jobsDomain.Job, error)
+	DeleteMonitoramento(ctx context.Context, id string) error
 	GetMonitoramento(ctx context.Context, id string) (*domain.Monitoramento, error)
+	ListErros(ctx context.Context, id string) ([]*domain.MonitoramentoErro, error)
+	WriteErrosCSV(ctx context.Context, id string, w io.Writer) error
+	OpenUploadFile(ctx context.Context, id string) (*domain.Monitoramento, io.ReadCloser, error)
+	ExportCSV(ctx context.Context, id string, w io.Writer) error
 	ListMonitoramentos(ctx context.Context, page, pageSize int) ([]*domain.Monitoramento, int, error)
+
+	CreateImportProfile(ctx context.Context, req dto.ImportProfileRequest) (*domain.ImportProfile, error)
+	GetImportProfile(ctx context.Context, id string) (*domain.ImportProfile, error)
+	ListImportProfiles(ctx context.Context) ([]*domain.ImportProfile, error)
+	UpdateImportProfile(ctx context.Context, id string, req dto.ImportProfileRequest) (*domain.ImportProfile, error)
+	DeleteImportProfile(ctx context.Context, id string) error
+}
+
+// UploadOptions opções escolhidas pelo usuário em cada upload
+type UploadOptions struct {
+	// Validation lenient (padrão) ou strict
+	Validation csv.ValidationMode
+	// Mode append (padrão) ou upsert
+	Mode domain.ImportMode
+	// Profile nome do perfil de importação do client (vazio = layout padrão)
+	Profile string
+	// Format csv ou xlsx; vazio detecta pela extensão do arquivo
+	Format domain.FileFormat
+	// Sheet aba da planilha XLSX (vazio = primeira aba)
+	Sheet string
+	// Encoding encoding declarado do CSV; vazio usa o do perfil ou detecta
+	Encoding string
+	// Force importa mesmo que o tenant já tenha importado um arquivo idêntico
+	Force bool
+
+	// mapping perfil resolvido pelo usecase
+	mapping *csv.ColumnMapping
+	// profileID referência do perfil no job: renomear o perfil não afeta importações agendadas
+	profileID string
+}
+
+// normalize valida as opções e aplica os padrões
+func (o UploadOptions) normalize() (UploadOptions, error) {
+	if o.Validation == "" {
+		o.Validation = csv.ValidationLenient
+	}
+	if !o.Validation.IsValid() {
+		return o, sharedErrors.ErrInvalidValidationMode
+	}
+	if o.Mode == "" {
+		o.Mode = domain.ImportModeAppend
+	}
+	if !o.Mode.IsValid() {
+		return o, sharedErrors.ErrInvalidImportMode
+	}
+	encoding, ok := csv.CanonicalEncoding(o.Encoding)
+	if !ok {
+		return o, sharedErrors.ErrInvalidEncoding
+	}
+	o.Encoding = encoding
+	return o, nil
+}
+
+// parseOptions opções do parser para as opções de upload (já normalizadas)
+func (o UploadOptions) parseOptions() csv.ParseOptions {
+	return csv.ParseOptions{
+		Validation: o.Validation,
+		UniqueKeys: o.Mode == domain.ImportModeUpsert,
+		Mapping:    o.mapping,
+		Encoding:   o.Encoding,
+	}
+}
+
+// JobScheduler cria jobs de importação (implementado pelo módulo jobs)
+type JobScheduler interface {
+	CreateCSVImportJob(ctx context.Context, payload jobsDomain.CSVImportPayload) (*jobsDomain.Job, error)
 }
 
 type monitoringUseCase struct {
 	monitoramentoRepo domain.MonitoramentoRepository
 	areaRepo          areaDomain.AreaMonitoramentoRepository
+	areaCatalog       areaDomain.AreaRepository // áreas físicas usadas no modo upsert
+	erroRepo          domain.MonitoramentoErroRepository
+	profileRepo       domain.ImportProfileRepository
 	csvParser         *csv.Parser
 	uuidGenerator     func() string
+	jobScheduler      JobScheduler
+	files             storage.Service // arquivos originais dos uploads
 }
 
 // NewMonitoringUseCase cria um novo usecase de monitoramento
 func NewMonitoringUseCase(
 	monitoramentoRepo domain.MonitoramentoRepository,
 	areaRepo areaDomain.AreaMonitoramentoRepository,
+	areaCatalog areaDomain.AreaRepository,
+	erroRepo domain.MonitoramentoErroRepository,
+	profileRepo domain.ImportProfileRepository,
 	csvParser *csv.Parser,
 	uuidGenerator func() string,
+	jobScheduler JobScheduler,
+	files storage.Service,
 ) MonitoringUseCase {
 	return &monitoringUseCase{
 		monitoramentoRepo: monitoramentoRepo,
 		areaRepo:          areaRepo,
+		areaCatalog:       areaCatalog,
+		erroRepo:          erroRepo,
+		profileRepo:       profileRepo,
 		csvParser:         csvParser,
 		uuidGenerator:     uuidGenerator,
+		jobScheduler:      jobScheduler,
+		files:             files,
+	}
+}
+
+// UploadCSVAsync salva o arquivo e cria um job csv_import; o monitoramento fica "processando"
+func (uc *monitoringUseCase) UploadCSVAsync(ctx context.Context, file io.Reader, filename string, opts UploadOptions) (*domain.Monitoramento, *jobsDomain.Job, error) {
+	clientID, err := sharedContext.RequireClientID(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	opts, err = uc.prepareOptions(ctx, filename, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	spooled, existing, err := uc.receiveUpload(ctx, file, opts)
+	if err != nil {
+		// No upload duplicado o monitoramento existente acompanha o erro para o handler informar o ID
+		return existing, nil, err
+	}
+	defer removeSpool(spooled)
+
+	// Planilha corrompida ou aba inexistente é rejeitada no upload, não só no worker
+	if opts.Format == domain.FileFormatXLSX {
+		if err := checkSpreadsheet(spooled.File, opts.Sheet); err != nil {
+			return nil, nil, err
+		}
 	}
+
+	monitoramento, err := uc.storeUpload(ctx, clientID, filename, opts, spooled)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	job, err := uc.scheduleImport(ctx, monitoramento, monitoramento.FileKey, opts)
+	if err != nil {
+		uc.monitoramentoRepo.UpdateStatus(ctx, monitoramento.ID, domain.StatusErro, 0)
+		return nil, nil, err
+	}
+
+	return monitoramento, job, nil
 }
 
-func (uc *monitoringUseCase) UploadAndProcessCSV(ctx context.Context, file io.Reader, filename string) (*domain.Monitoramento, error) {
+// receiveUpload copia o upload para um temporário e rejeita conteúdo já importado (exceto com Force).
+// No ErrDuplicateUpload retorna o monitoramento existente; o chamador remove o temporário com removeSpool
+func (uc *monitoringUseCase) receiveUpload(ctx context.Context, file io.Reader, opts UploadOptions) (*spooledUpload, *domain.Monitoramento, error) {
+	// O checksum precisa do arquivo inteiro antes do parsing
+	spooled, err := spoolUpload(file)
+	if err != nil {
+		return nil, nil, err
+	}
+	if opts.Force {
+		return spooled, nil, nil
+	}
+
+	existing, err := uc.findDuplicate(ctx, spooled.checksum)
+	if err == nil && existing != nil {
+		err = sharedErrors.ErrDuplicateUpload
+	}
+	if err != nil {
+		removeSpool(spooled)
+		return nil, existing, err
+	}
+	return spooled, nil, nil
+}
+
+// ReprocessMonitoramento agenda uma nova importação do arquivo original com as opções informadas
+// (ex.: após alterar o perfil); as áreas e os erros atuais são substituídos quando o job roda
+func (uc *monitoringUseCase) ReprocessMonitoramento(ctx context.Context, id string, opts UploadOptions) (*domain.Monitoramento, *jobsDomain.Job, error) {
+	if _, err := sharedContext.RequireClientID(ctx); err != nil {
+		return nil, nil, err
+	}
+
+	monitoramento, err := uc.monitoramentoRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if monitoramento.Status == domain.StatusProcessando {
+		return nil, nil, sharedErrors.ErrMonitoramentoProcessing
+	}
+
+	format, err := uc.storedFileFormat(ctx, monitoramento.FileKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	opts.Format = format
+
+	opts, err = uc.prepareOptions(ctx, monitoramento.NomeArquivo, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	if opts.Format == domain.FileFormatXLSX {
+		if err := uc.checkStoredSpreadsheet(ctx, monitoramento.FileKey, opts.Sheet); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// Só um reprocessamento vence: outra chamada pode ter passado pela checagem acima ao mesmo tempo
+	claimed, err := uc.monitoramentoRepo.UpdateStatusIf(ctx, monitoramento.ID, domain.StatusProcessando, 0, domain.StatusConcluido, domain.StatusErro)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !claimed {
+		return nil, nil, sharedErrors.ErrMonitoramentoProcessing
+	}
+
+	job, err := uc.scheduleImport(ctx, monitoramento, monitoramento.FileKey, opts)
+	if err != nil {
+		uc.monitoramentoRepo.UpdateStatus(ctx, monitoramento.ID, domain.StatusErro, 0)
+		return nil, nil, err
+	}
+
+	monitoramento.Status = domain.StatusProcessando
+	monitoramento.TotalLinhas = 0
+	return monitoramento, job, nil
+}
+
+// DeleteMonitoramento remove o monitoramento, suas áreas, os erros de parsing e o arquivo original
+func (uc *monitoringUseCase) DeleteMonitoramento(ctx context.Context, id string) error {
+	if _, err := sharedContext.RequireClientID(ctx); err != nil {
+		return err
+	}
+
+	monitoramento, err := uc.monitoramentoRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	// O worker ainda vai gravar áreas para este monitoramento
+	if monitoramento.Status == domain.StatusProcessando {
+		return sharedErrors.ErrMonitoramentoProcessing
+	}
+
+	if err := uc.monitoramentoRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	// No PostgreSQL as áreas e os erros já saíram em cascata; os repositórios em memória precisam da remoção explícita
+	if _, err := uc.areaRepo.DeleteByMonitoramentoID(ctx, id); err != nil {
+		return err
+	}
+	if err := uc.erroRepo.DeleteByMonitoramento(ctx, id); err != nil {
+		return err
+	}
+
+	if monitoramento.FileKey != "" {
+		if err := uc.files.Delete(ctx, monitoramento.FileKey); err != nil {
+			log.Printf("Erro ao remover arquivo do monitoramento %s: %v", id, err)
+		}
+	}
+	return nil
+}
+
+// scheduleImport cria o job csv_import do arquivo salvo em fileKey
+func (uc *monitoringUseCase) scheduleImport(ctx context.Context, monitoramento *domain.Monitoramento, fileKey string, opts UploadOptions) (*jobsDomain.Job, error) {
+	return uc.jobScheduler.CreateCSVImportJob(ctx, jobsDomain.CSVImportPayload{
+		MonitoramentoID: monitoramento.ID,
+		FileKey:         fileKey,
+		Filename:        monitoramento.NomeArquivo,
+		Validation:      string(opts.Validation),
+		Mode:            string(opts.Mode),
+		ProfileID:       opts.profileID,
+		Format:          string(opts.Format),
+		Sheet:           opts.Sheet,
+		Encoding:        opts.Encoding,
+	})
+}
+
+// storeUpload grava o arquivo original no storage e cria o monitoramento. O arquivo é mantido
+// após a importação para permitir o reprocessamento
+func (uc *monitoringUseCase) storeUpload(ctx context.Context, clientID, filename string, opts UploadOptions, spooled *spooledUpload) (*domain.Monitoramento, error) {
 	monitoramento := domain.NewMonitoramento(uc.uuidGenerator(), filename)
+	monitoramento.Checksum = spooled.checksum
+	monitoramento.FileKey = uploadFileKey(clientID, monitoramento.ID, opts.Format)
+
+	if _, err := spooled.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("erro ao salvar upload: %w", err)
+	}
+	if err := uc.files.Put(ctx, monitoramento.FileKey, spooled.File, spooled.size); err != nil {
+		return nil, fmt.Errorf("erro ao salvar upload: %w", err)
+	}
 
 	if err := uc.monitoramentoRepo.Create(ctx, monitoramento); err != nil {
+		uc.files.Delete(ctx, monitoramento.FileKey)
 		return nil, err
 	}
+	return monitoramento, nil
+}
+
+// uploadFileKey chave do arquivo original no storage, separada por client.
+// A extensão vem do formato, não do nome enviado pelo usuário
+func uploadFileKey(clientID, monitoramentoID string, format domain.FileFormat) string {
+	return storage.Key(clientID, monitoramentoID+"."+string(format))
+}
 
-	result, err := uc.csvParser.Parse(file, monitoramento.ID)
+// openStoredFile abre o arquivo original; uploads anteriores ao armazenamento não têm arquivo
+func (uc *monitoringUseCase) openStoredFile(ctx context.Context, fileKey string) (io.ReadCloser, error) {
+	if fileKey == "" {
+		return nil, sharedErrors.ErrUploadFileNotFound
+	}
+	file, err := uc.files.Get(ctx, fileKey)
+	if err == sharedErrors.ErrFileNotFound {
+		return nil, sharedErrors.ErrUploadFileNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao abrir arquivo do upload: %w", err)
+	}
+	return file, nil
+}
+
+// storedFileFormat identifica o formato do arquivo salvo pelo conteúdo: chaves de uploads
+// antigos usam a extensão do nome enviado, que pode faltar em um XLSX
+func (uc *monitoringUseCase) storedFileFormat(ctx context.Context, fileKey string) (domain.FileFormat, error) {
+	file, err := uc.openStoredFile(ctx, fileKey)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	magic := make([]byte, len(zipMagic))
+	if _, err := io.ReadFull(file, magic); err == nil && bytes.Equal(magic, zipMagic) {
+		return domain.FileFormatXLSX, nil
+	}
+	return domain.FileFormatCSV, nil
+}
+
+// PreviewUpload faz o parsing do arquivo com as mesmas opções do upload sem gravar nada:
+// retorna o layout reconhecido, as primeiras limit áreas e os erros por linha
+func (uc *monitoringUseCase) PreviewUpload(ctx context.Context, file io.Reader, filename string, opts UploadOptions, limit int) (*domain.ImportPreview, error) {
+	if _, err := sharedContext.RequireClientID(ctx); err != nil {
+		return nil, err
+	}
+
+	opts, err := uc.prepareOptions(ctx, filename, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if limit < 1 {
+		limit = previewDefaultLimit
+	}
+	if limit > previewMaxLimit {
+		limit = previewMaxLimit
+	}
+
+	areas := make([]*areaDomain.AreaMonitoramento, 0, limit)
+	result, err := uc.parseFile(file, "", opts, func(batch []*areaDomain.AreaMonitoramento) error {
+		if n := limit - len(areas); n > 0 {
+			areas = append(areas, batch[:min(n, len(batch))]...)
+		}
+		return nil
+	})
+	// Arquivo sem nenhuma linha válida ainda tem prévia: os erros explicam o motivo
+	if err != nil && (result == nil || err != sharedErrors.ErrInvalidCSV) {
+		return nil, err
+	}
+
+	preview := &domain.ImportPreview{
+		Format:      opts.Format,
+		Encoding:    result.Encoding,
+		Header:      result.Header,
+		Colunas:     result.Colunas,
+		Pragas:      result.Pragas,
+		TotalLinhas: result.TotalLinhas,
+		TotalErros:  len(result.Errors),
+		Areas:       areas,
+		Erros:       make([]*domain.MonitoramentoErro, 0, min(len(result.Errors), previewMaxErros)),
+	}
+	if result.Separator != 0 {
+		preview.Separador = string(result.Separator)
+	}
+	for _, e := range result.Errors[:min(len(result.Errors), previewMaxErros)] {
+		preview.Erros = append(preview.Erros, &domain.MonitoramentoErro{Linha: e.Linha, Erro: e.Erro, Registro: e.Registro})
+	}
+	return preview, nil
+}
+
+// prepareOptions normaliza as opções, carrega o perfil pelo nome e detecta o formato pela extensão
+func (uc *monitoringUseCase) prepareOptions(ctx context.Context, filename string, opts UploadOptions) (UploadOptions, error) {
+	opts, err := opts.normalize()
+	if err != nil {
+		return opts, err
+	}
+	if opts.Profile != "" {
+		profile, err := uc.profileRepo.GetByNome(ctx, opts.Profile)
+		if err != nil {
+			return opts, err
+		}
+		if opts.mapping, err = profileMapping(profile); err != nil {
+			return opts, err
+		}
+		opts.profileID = profile.ID
+	}
+	if opts.Format == "" {
+		opts.Format = domain.DetectFileFormat(filename, "")
+	}
+	return opts, nil
+}
+
+// ProcessCSVImport processa um job csv_import no worker
+func (uc *monitoringUseCase) ProcessCSVImport(ctx context.Context, job *jobsDomain.Job, progress jobsDomain.ProgressFunc) (interface{}, []jobsDomain.JobError, error) {
+	var payload jobsDomain.CSVImportPayload
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		return nil, nil, fmt.Errorf("payload inválido: %w", err)
+	}
+
+	// Nova tentativa após falha temporária: a anterior deixou o monitoramento com erro
+	if job.Attempts > 1 {
+		if err := uc.monitoramentoRepo.UpdateStatus(ctx, payload.MonitoramentoID, domain.StatusProcessando, 0); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	file, err := uc.openStoredFile(ctx, payload.FileKey)
+	if err != nil {
+		uc.monitoramentoRepo.UpdateStatus(ctx, payload.MonitoramentoID, domain.StatusErro, 0)
+		return nil, nil, err
+	}
+	defer file.Close()
+
+	// Jobs antigos não têm os campos e usam os padrões (lenient, append, csv)
+	opts, err := UploadOptions{
+		Validation: csv.ValidationMode(payload.Validation),
+		Mode:       domain.ImportMode(payload.Mode),
+		Format:     domain.FileFormat(payload.Format),
+		Sheet:      payload.Sheet,
+		Encoding:   payload.Encoding,
+	}.normalize()
+	if err != nil {
+		uc.monitoramentoRepo.UpdateStatus(ctx, payload.MonitoramentoID, domain.StatusErro, 0)
+		return nil, nil, err
+	}
+	if payload.ProfileID != "" {
+		// Aplica a versão atual do perfil (pode ter sido alterado desde o upload)
+		profile, err := uc.profileRepo.GetByID(ctx, payload.ProfileID)
+		if err == nil {
+			opts.mapping, err = profileMapping(profile)
+		}
+		if err != nil {
+			uc.monitoramentoRepo.UpdateStatus(ctx, payload.MonitoramentoID, domain.StatusErro, 0)
+			return nil, nil, err
+		}
+	}
+
+	// Reprocessamento (ou job reexecutado): as áreas da importação anterior são substituídas
+	if _, err := uc.areaRepo.DeleteByMonitoramentoID(ctx, payload.MonitoramentoID); err != nil {
+		uc.monitoramentoRepo.UpdateStatus(ctx, payload.MonitoramentoID, domain.StatusErro, 0)
+		return nil, nil, err
+	}
+
+	areas, err := uc.importCSV(ctx, payload.MonitoramentoID, file, opts, progress)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	mon, err := uc.monitoramentoRepo.GetByID(ctx, payload.MonitoramentoID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return jobsDomain.CSVImportResult{
+		MonitoramentoID: mon.ID,
+		TotalLinhas:     mon.TotalLinhas,
+		Areas:           areas,
+		TotalErros:      mon.TotalErros,
+	}, nil, nil
+}
+
+// importCSV faz o parse em streaming e insere as áreas lote a lote, atualizando o status do monitoramento.
+// Em caso de falha as áreas já inseridas são removidas. Retorna a quantidade de áreas inseridas.
+func (uc *monitoringUseCase) importCSV(ctx context.Context, monitoramentoID string, file io.Reader, opts UploadOptions, progress jobsDomain.ProgressFunc) (int, error) {
+	inserted := 0
+	result, err := uc.parseFile(file, monitoramentoID, opts, func(areas []*areaDomain.AreaMonitoramento) error {
+		if opts.Mode == domain.ImportModeUpsert {
+			if err := uc.linkAreas(ctx, areas); err != nil {
+				return err
+			}
+		}
+		if err := uc.areaRepo.CreateBatch(ctx, areas); err != nil {
+			return err
+		}
+		inserted += len(areas)
+
+		// O total só é conhecido ao final do arquivo
+		if progress != nil {
+			progress(0, inserted, 0)
+		}
+		return nil
+	})
+	if result != nil {
+		if saveErr := uc.saveParseErrors(ctx, monitoramentoID, result); saveErr != nil && err == nil {
+			err = saveErr
+		}
+	}
+	if err != nil {
+		if inserted > 0 {
+			if _, delErr := uc.areaRepo.DeleteByMonitoramentoID(ctx, monitoramentoID); delErr != nil {
+				log.Printf("Erro ao remover áreas do monitoramento %s após falha: %v", monitoramentoID, delErr)
+			}
+		}
+		uc.monitoramentoRepo.UpdateStatus(ctx, monitoramentoID, domain.StatusErro, 0)
+		return 0, err
+	}
+
+	if progress != nil {
+		progress(inserted, inserted, len(result.Errors))
+	}
+
+	if err := uc.monitoramentoRepo.UpdateStatus(ctx, monitoramentoID, domain.StatusConcluido, result.TotalLinhas); err != nil {
+		return 0, err
+	}
+
+	return inserted, nil
+}
+
+// parseFile faz o parse do CSV ou da aba da planilha com o mesmo mapeamento e validação
+func (uc *monitoringUseCase) parseFile(file io.Reader, monitoramentoID string, opts UploadOptions, onBatch csv.BatchFunc) (*csv.StreamResult, error) {
+	if opts.Format != domain.FileFormatXLSX {
+		return uc.csvParser.ParseStream(file, monitoramentoID, opts.parseOptions(), csvImportBatchSize, onBatch)
+	}
+
+	sheet, err := openSheet(file, opts.Sheet)
 	if err != nil {
-		uc.monitoramentoRepo.UpdateStatus(ctx, monitoramento.ID, domain.StatusErro, 0)
 		return nil, err
 	}
+	defer sheet.Close()
 
-	if len(result.Areas) > 0 {
-		if err := uc.areaRepo.CreateBatch(ctx, result.Areas); err != nil {
-			uc.monitoramentoRepo.UpdateStatus(ctx, monitoramento.ID, domain.StatusErro, 0)
-			return nil, err
+	return uc.csvParser.ParseRecords(sheet, monitoramentoID, opts.parseOptions(), csvImportBatchSize, onBatch)
+}
+
+// checkSpreadsheet verifica se o arquivo é um XLSX válido com a aba pedida
+func checkSpreadsheet(file io.Reader, sheetName string) error {
+	sheet, err := openSheet(file, sheetName)
+	if err != nil {
+		return err
+	}
+	return sheet.Close()
+}
+
+// checkStoredSpreadsheet verifica a planilha guardada no storage
+func (uc *monitoringUseCase) checkStoredSpreadsheet(ctx context.Context, fileKey, sheetName string) error {
+	file, err := uc.openStoredFile(ctx, fileKey)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return checkSpreadsheet(file, sheetName)
+}
+
+// openSheet abre a aba da planilha; o XLSX é um zip e precisa de acesso aleatório,
+// então uploads que não são arquivos em disco são lidos para a memória
+func openSheet(file io.Reader, sheetName string) (*xlsx.SheetReader, error) {
+	var (
+		readerAt io.ReaderAt
+		size     int64
+	)
+	if f, ok := file.(*os.File); ok {
+		info, err := f.Stat()
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler arquivo do upload: %w", err)
 		}
+		readerAt, size = f, info.Size()
+	} else {
+		data, err := io.ReadAll(file)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler arquivo do upload: %w", err)
+		}
+		readerAt, size = bytes.NewReader(data), int64(len(data))
 	}
 
-	if err := uc.monitoramentoRepo.UpdateStatus(ctx, monitoramento.ID, domain.StatusConcluido, result.TotalLinhas); err != nil {
+	workbook, err := xlsx.Open(readerAt, size)
+	if err != nil {
 		return nil, err
 	}
+	return workbook.OpenSheet(sheetName)
+}
+
+// linkAreas vincula cada observação com chave externa à área física correspondente,
+// criando as áreas que ainda não existem para o tenant
+func (uc *monitoringUseCase) linkAreas(ctx context.Context, observacoes []*areaDomain.AreaMonitoramento) error {
+	areas := make([]*areaDomain.Area, 0, len(observacoes))
+	linked := make([]*areaDomain.AreaMonitoramento, 0, len(observacoes))
+	for _, obs := range observacoes {
+		if obs.ExternalKey == "" {
+			continue
+		}
+		areas = append(areas, areaDomain.NewArea(uc.uuidGenerator(), obs))
+		linked = append(linked, obs)
+	}
 
-	return uc.monitoramentoRepo.GetByID(ctx, monitoramento.ID)
+	if err := uc.areaCatalog.UpsertBatch(ctx, areas); err != nil {
+		return err
+	}
+
+	for i, obs := range linked {
+		obs.AreaID = areas[i].ID
+	}
+	return nil
+}
+
+// saveParseErrors substitui os erros de parsing gravados para o monitoramento
+// (a importação pode ser reexecutada) e atualiza o resumo
+func (uc *monitoringUseCase) saveParseErrors(ctx context.Context, monitoramentoID string, result *csv.StreamResult) error {
+	if err := uc.erroRepo.DeleteByMonitoramento(ctx, monitoramentoID); err != nil {
+		return err
+	}
+
+	erros := make([]*domain.MonitoramentoErro, len(result.Errors))
+	for i, e := range result.Errors {
+		erros[i] = &domain.MonitoramentoErro{
+			MonitoramentoID: monitoramentoID,
+			Linha:           e.Linha,
+			Erro:            e.Erro,
+			Registro:        e.Registro,
+		}
+	}
+	if err := uc.erroRepo.CreateBatch(ctx, erros); err != nil {
+		return err
+	}
+
+	return uc.monitoramentoRepo.UpdateResumoErros(ctx, monitoramentoID, result.Header, len(erros))
+}
+
+// spooledUpload upload copiado para um arquivo temporário
+type spooledUpload struct {
+	*os.File
+	checksum string
+	size     int64
+}
+
+// spoolUpload copia o upload para um arquivo temporário (posicionado no início) e calcula o checksum
+func spoolUpload(file io.Reader) (*spooledUpload, error) {
+	tmp, err := os.CreateTemp("", "upload-*")
+	if err != nil {
+		return nil, fmt.Errorf("erro ao salvar upload: %w", err)
+	}
+
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, hash), file)
+	if err == nil {
+		_, err = tmp.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return nil, fmt.Errorf("erro ao salvar upload: %w", err)
+	}
+
+	return &spooledUpload{File: tmp, checksum: hex.EncodeToString(hash.Sum(nil)), size: size}, nil
+}
+
+// removeSpool fecha e remove o temporário do upload
+func removeSpool(spooled *spooledUpload) {
+	spooled.Close()
+	os.Remove(spooled.Name())
+}
+
+// findDuplicate retorna o monitoramento concluído do tenant com o mesmo conteúdo, se houver
+func (uc *monitoringUseCase) findDuplicate(ctx context.Context, checksum string) (*domain.Monitoramento, error) {
+	existing, err := uc.monitoramentoRepo.FindByChecksum(ctx, checksum, domain.StatusConcluido)
+	if err == sharedErrors.ErrMonitoramentoNotFound {
+		return nil, nil
+	}
+	return existing, err
 }
 
 func (uc *monitoringUseCase) GetMonitoramento(ctx context.Context, id string) (*domain.Monitoramento, error) {
 	return uc.monitoramentoRepo.GetByID(ctx, id)
 }
 
+// ListErros retorna as linhas rejeitadas na importação do monitoramento
+func (uc *monitoringUseCase) ListErros(ctx context.Context, id string) ([]*domain.MonitoramentoErro, error) {
+	if _, err := uc.monitoramentoRepo.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+	return uc.erroRepo.ListByMonitoramento(ctx, id)
+}
+
+// WriteErrosCSV escreve o CSV de erros (linha original + motivo) para correção e reenvio
+func (uc *monitoringUseCase) WriteErrosCSV(ctx context.Context, id string, w io.Writer) error {
+	mon, err := uc.monitoramentoRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	erros, err := uc.erroRepo.ListByMonitoramento(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	parseErrors := make([]csv.ParseError, len(erros))
+	for i, e := range erros {
+		parseErrors[i] = csv.ParseError{Linha: e.Linha, Erro: e.Erro, Registro: e.Registro}
+	}
+
+	return csv.WriteErrors(w, mon.Cabecalho, parseErrors)
+}
+
+// OpenUploadFile abre o arquivo original do monitoramento; o chamador fecha o arquivo
+func (uc *monitoringUseCase) OpenUploadFile(ctx context.Context, id string) (*domain.Monitoramento, io.ReadCloser, error) {
+	mon, err := uc.monitoramentoRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	file, err := uc.openStoredFile(ctx, mon.FileKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return mon, file, nil
+}
+
+// ExportCSV gera um CSV no layout padrão do parser com as áreas atuais do monitoramento,
+// incluindo as aplicações registradas pela API; o arquivo pode ser reimportado
+func (uc *monitoringUseCase) ExportCSV(ctx context.Context, id string, w io.Writer) error {
+	mon, err := uc.monitoramentoRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	// As áreas ainda estão sendo gravadas pelo worker
+	if mon.Status == domain.StatusProcessando {
+		return sharedErrors.ErrMonitoramentoProcessing
+	}
+
+	// As colunas de pragas e aplicações dependem de todas as áreas: a primeira
+	// passada monta o layout e a segunda escreve as linhas
+	layout := csv.NewExportLayout()
+	if err := uc.eachAreaPage(ctx, id, func(areas []*areaDomain.AreaMonitoramento) error {
+		layout.Add(areas)
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	writer, err := csv.NewAreaWriter(w, layout)
+	if err != nil {
+		return err
+	}
+	if err := uc.eachAreaPage(ctx, id, writer.Write); err != nil {
+		return err
+	}
+	return writer.Flush()
+}
+
+// eachAreaPage percorre as áreas do monitoramento em páginas de exportPageSize
+func (uc *monitoringUseCase) eachAreaPage(ctx context.Context, monitoramentoID string, fn func([]*areaDomain.AreaMonitoramento) error) error {
+	for offset := 0; ; offset += exportPageSize {
+		areas, _, err := uc.areaRepo.GetByMonitoramentoID(ctx, monitoramentoID, exportPageSize, offset)
+		if err != nil {
+			return err
+		}
+		if err := fn(areas); err != nil {
+			return err
+		}
+		if len(areas) < exportPageSize {
+			return nil
+		}
+	}
+}
+
 func (uc *monitoringUseCase) ListMonitoramentos(ctx context.Context, page, pageSize int) ([]*domain.Monitoramento, int, error) {
 	if page < 1 {
 		page = 1
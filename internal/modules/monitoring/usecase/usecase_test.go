@@ -1,19 +1,33 @@
 package usecase
 
 import (
+	"archive/zip"
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"strings"
 	"testing"
 
+	areaDomain "agro-monitoring/internal/modules/area/domain"
 	areaRepo "agro-monitoring/internal/modules/area/repository"
+	jobsDomain "agro-monitoring/internal/modules/jobs/domain"
 	"agro-monitoring/internal/services/csv"
+	"agro-monitoring/internal/modules/monitoring/domain"
+	"agro-monitoring/internal/modules/monitoring/dto"
 	"agro-monitoring/internal/modules/monitoring/repository"
+	"agro-monitoring/internal/services/storage"
+	sharedContext "agro-monitoring/internal/shared/context"
+	sharedErrors "agro-monitoring/internal/shared/errors"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
+var tenantCtx = sharedContext.WithTenant(context.Background(), "client-a", "user-a")
+
 func mockUUID() func() string {
 	counter := 0
 	return func() string {
@@ -22,19 +36,19 @@ func mockUUID() func() string {
 	}
 }
 
-func TestMonitoringUseCase_UploadAndProcessCSV(t *testing.T) {
+func TestMonitoringUseCase_ImportCSV(t *testing.T) {
 	monRepo := repository.NewInMemoryRepository()
 	areaRepository := areaRepo.NewInMemoryRepository()
 	uuidGen := mockUUID()
 	parser := csv.NewParser(uuidGen)
 
-	uc := NewMonitoringUseCase(monRepo, areaRepository, parser, uuidGen)
+	uc := NewMonitoringUseCase(monRepo, areaRepository, areaRepo.NewInMemoryAreaRepository(), repository.NewInMemoryErroRepository(), repository.NewInMemoryProfileRepository(), parser, uuidGen, &fakeScheduler{}, storage.NewLocalStorage(t.TempDir()))
 
 	csvContent := `Id;Setor;Setor2;Cod.Fazenda;Desc.Fazenda;Quadra;Corte;Área Total;Desc. Textura Solo;Corte Atual;Reforma;Mês Colheita;Restrição;Camalote;Vassoura
 1;Norte;Sub1;FAZ001;Fazenda A;Q1;3;150,5;Argiloso;2;2020;Agosto;Nenhuma;S;N
 2;Sul;Sub2;FAZ002;Fazenda B;Q2;4;200,75;Arenoso;3;2019;Setembro;APP;N;S`
 
-	result, err := uc.UploadAndProcessCSV(context.Background(), strings.NewReader(csvContent), "teste.csv")
+	result, err := importFile(tenantCtx, uc, strings.NewReader(csvContent), "teste.csv", UploadOptions{})
 
 	require.NoError(t, err)
 	assert.Equal(t, "concluido", string(result.Status))
@@ -42,7 +56,7 @@ func TestMonitoringUseCase_UploadAndProcessCSV(t *testing.T) {
 	assert.Equal(t, "teste.csv", result.NomeArquivo)
 
 	// Verifica se as áreas foram salvas
-	areas, total, _ := areaRepository.GetByMonitoramentoID(context.Background(), result.ID, 10, 0)
+	areas, total, _ := areaRepository.GetByMonitoramentoID(tenantCtx, result.ID, 10, 0)
 	assert.Equal(t, 2, total)
 
 	// Verifica fazendas
@@ -51,22 +65,22 @@ func TestMonitoringUseCase_UploadAndProcessCSV(t *testing.T) {
 	assert.Contains(t, fazendas, "FAZ002")
 }
 
-func TestMonitoringUseCase_UploadAndProcessCSV_InvalidCSV(t *testing.T) {
+func TestMonitoringUseCase_ImportCSV_InvalidCSV(t *testing.T) {
 	monRepo := repository.NewInMemoryRepository()
 	areaRepository := areaRepo.NewInMemoryRepository()
 	uuidGen := mockUUID()
 	parser := csv.NewParser(uuidGen)
 
-	uc := NewMonitoringUseCase(monRepo, areaRepository, parser, uuidGen)
+	uc := NewMonitoringUseCase(monRepo, areaRepository, areaRepo.NewInMemoryAreaRepository(), repository.NewInMemoryErroRepository(), repository.NewInMemoryProfileRepository(), parser, uuidGen, &fakeScheduler{}, storage.NewLocalStorage(t.TempDir()))
 
 	csvContent := `Campo1;Campo2
 1;2`
 
-	_, err := uc.UploadAndProcessCSV(context.Background(), strings.NewReader(csvContent), "invalido.csv")
+	_, err := importFile(tenantCtx, uc, strings.NewReader(csvContent), "invalido.csv", UploadOptions{})
 
 	assert.Error(t, err)
 
-	mons, _, _ := monRepo.List(context.Background(), 10, 0)
+	mons, _, _ := monRepo.List(tenantCtx, 10, 0)
 	if len(mons) > 0 {
 		assert.Equal(t, "erro", string(mons[0].Status))
 	}
@@ -78,15 +92,15 @@ func TestMonitoringUseCase_GetMonitoramento(t *testing.T) {
 	uuidGen := mockUUID()
 	parser := csv.NewParser(uuidGen)
 
-	uc := NewMonitoringUseCase(monRepo, areaRepository, parser, uuidGen)
+	uc := NewMonitoringUseCase(monRepo, areaRepository, areaRepo.NewInMemoryAreaRepository(), repository.NewInMemoryErroRepository(), repository.NewInMemoryProfileRepository(), parser, uuidGen, &fakeScheduler{}, storage.NewLocalStorage(t.TempDir()))
 
 	csvContent := `Id;Setor;Setor2;Cod.Fazenda;Desc.Fazenda;Quadra;Corte;Área Total;Desc. Textura Solo;Corte Atual;Reforma;Mês Colheita;Restrição
 1;N;S;F1;Fazenda;Q;1;100;Arg;1;2020;Jan;N`
 
-	created, err := uc.UploadAndProcessCSV(context.Background(), strings.NewReader(csvContent), "teste.csv")
+	created, err := importFile(tenantCtx, uc, strings.NewReader(csvContent), "teste.csv", UploadOptions{})
 	require.NoError(t, err)
 
-	found, err := uc.GetMonitoramento(context.Background(), created.ID)
+	found, err := uc.GetMonitoramento(tenantCtx, created.ID)
 	require.NoError(t, err)
 	assert.Equal(t, created.ID, found.ID)
 }
@@ -97,16 +111,17 @@ func TestMonitoringUseCase_ListMonitoramentos(t *testing.T) {
 	uuidGen := mockUUID()
 	parser := csv.NewParser(uuidGen)
 
-	uc := NewMonitoringUseCase(monRepo, areaRepository, parser, uuidGen)
+	uc := NewMonitoringUseCase(monRepo, areaRepository, areaRepo.NewInMemoryAreaRepository(), repository.NewInMemoryErroRepository(), repository.NewInMemoryProfileRepository(), parser, uuidGen, &fakeScheduler{}, storage.NewLocalStorage(t.TempDir()))
 
 	csvContent := `Id;Setor;Setor2;Cod.Fazenda;Desc.Fazenda;Quadra;Corte;Área Total;Desc. Textura Solo;Corte Atual;Reforma;Mês Colheita;Restrição
 1;N;S;F1;Fazenda;Q;1;100;Arg;1;2020;Jan;N`
 
-	uc.UploadAndProcessCSV(context.Background(), strings.NewReader(csvContent), "teste1.csv")
-	uc.UploadAndProcessCSV(context.Background(), strings.NewReader(csvContent), "teste2.csv")
-	uc.UploadAndProcessCSV(context.Background(), strings.NewReader(csvContent), "teste3.csv")
+	// Mesmo conteúdo três vezes: force evita a recusa por arquivo duplicado
+	importFile(tenantCtx, uc, strings.NewReader(csvContent), "teste1.csv", UploadOptions{Force: true})
+	importFile(tenantCtx, uc, strings.NewReader(csvContent), "teste2.csv", UploadOptions{Force: true})
+	importFile(tenantCtx, uc, strings.NewReader(csvContent), "teste3.csv", UploadOptions{Force: true})
 
-	list, total, err := uc.ListMonitoramentos(context.Background(), 1, 10)
+	list, total, err := uc.ListMonitoramentos(tenantCtx, 1, 10)
 	require.NoError(t, err)
 	assert.Equal(t, 3, total)
 	assert.Len(t, list, 3)
@@ -118,22 +133,982 @@ func TestMonitoringUseCase_Pagination(t *testing.T) {
 	uuidGen := mockUUID()
 	parser := csv.NewParser(uuidGen)
 
-	uc := NewMonitoringUseCase(monRepo, areaRepository, parser, uuidGen)
+	uc := NewMonitoringUseCase(monRepo, areaRepository, areaRepo.NewInMemoryAreaRepository(), repository.NewInMemoryErroRepository(), repository.NewInMemoryProfileRepository(), parser, uuidGen, &fakeScheduler{}, storage.NewLocalStorage(t.TempDir()))
 
 	csvContent := `Id;Setor;Setor2;Cod.Fazenda;Desc.Fazenda;Quadra;Corte;Área Total;Desc. Textura Solo;Corte Atual;Reforma;Mês Colheita;Restrição
 1;N;S;F1;Fazenda;Q;1;100;Arg;1;2020;Jan;N`
 
 	for i := 0; i < 5; i++ {
-		uc.UploadAndProcessCSV(context.Background(), strings.NewReader(csvContent), fmt.Sprintf("teste%d.csv", i))
+		importFile(tenantCtx, uc, strings.NewReader(csvContent), fmt.Sprintf("teste%d.csv", i), UploadOptions{Force: true})
 	}
 
-	list, total, _ := uc.ListMonitoramentos(context.Background(), 1, 2)
+	list, total, _ := uc.ListMonitoramentos(tenantCtx, 1, 2)
 	assert.Equal(t, 5, total)
 	assert.Len(t, list, 2)
 
-	list2, _, _ := uc.ListMonitoramentos(context.Background(), 2, 2)
+	list2, _, _ := uc.ListMonitoramentos(tenantCtx, 2, 2)
 	assert.Len(t, list2, 2)
 
-	list3, _, _ := uc.ListMonitoramentos(context.Background(), 3, 2)
+	list3, _, _ := uc.ListMonitoramentos(tenantCtx, 3, 2)
 	assert.Len(t, list3, 1)
 }
+
+func TestMonitoringUseCase_CrossTenantIsolation(t *testing.T) {
+	monRepo := repository.NewInMemoryRepository()
+	areaRepository := areaRepo.NewInMemoryRepository()
+	uuidGen := mockUUID()
+	parser := csv.NewParser(uuidGen)
+
+	uc := NewMonitoringUseCase(monRepo, areaRepository, areaRepo.NewInMemoryAreaRepository(), repository.NewInMemoryErroRepository(), repository.NewInMemoryProfileRepository(), parser, uuidGen, &fakeScheduler{}, storage.NewLocalStorage(t.TempDir()))
+
+	csvContent := `Id;Setor;Setor2;Cod.Fazenda;Desc.Fazenda;Quadra;Corte;Área Total;Desc. Textura Solo;Corte Atual;Reforma;Mês Colheita;Restrição
+1;N;S;F1;Fazenda;Q;1;100;Arg;1;2020;Jan;N`
+
+	created, err := importFile(tenantCtx, uc, strings.NewReader(csvContent), "teste.csv", UploadOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "client-a", created.ClientID)
+	assert.Equal(t, "user-a", created.UserID)
+
+	otherCtx := sharedContext.WithTenant(context.Background(), "client-b", "user-b")
+
+	_, err = uc.GetMonitoramento(otherCtx, created.ID)
+	assert.Equal(t, sharedErrors.ErrMonitoramentoNotFound, err)
+
+	list, total, err := uc.ListMonitoramentos(otherCtx, 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 0, total)
+	assert.Empty(t, list)
+}
+
+func TestMonitoringUseCase_UploadWithoutTenant(t *testing.T) {
+	monRepo := repository.NewInMemoryRepository()
+	areaRepository := areaRepo.NewInMemoryRepository()
+	uuidGen := mockUUID()
+	parser := csv.NewParser(uuidGen)
+
+	uc := NewMonitoringUseCase(monRepo, areaRepository, areaRepo.NewInMemoryAreaRepository(), repository.NewInMemoryErroRepository(), repository.NewInMemoryProfileRepository(), parser, uuidGen, &fakeScheduler{}, storage.NewLocalStorage(t.TempDir()))
+
+	csvContent := `Id;Setor;Setor2;Cod.Fazenda;Desc.Fazenda;Quadra;Corte;Área Total;Desc. Textura Solo;Corte Atual;Reforma;Mês Colheita;Restrição
+1;N;S;F1;Fazenda;Q;1;100;Arg;1;2020;Jan;N`
+
+	_, err := importFile(context.Background(), uc, strings.NewReader(csvContent), "teste.csv", UploadOptions{})
+	assert.Equal(t, sharedErrors.ErrTenantRequired, err)
+}
+
+// fakeScheduler guarda o job criado em vez de enfileirar
+type fakeScheduler struct {
+	job *jobsDomain.Job
+	err error
+}
+
+func (f *fakeScheduler) CreateCSVImportJob(ctx context.Context, payload jobsDomain.CSVImportPayload) (*jobsDomain.Job, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	job, err := jobsDomain.NewJob("job-1", jobsDomain.JobTypeCSVImport, payload)
+	if err != nil {
+		return nil, err
+	}
+	f.job = job
+	return job, nil
+}
+
+// importFile faz o upload e executa o job csv_import como o worker, retornando o monitoramento
+// importado (ou o existente, no upload duplicado)
+func importFile(ctx context.Context, uc MonitoringUseCase, file io.Reader, filename string, opts UploadOptions) (*domain.Monitoramento, error) {
+	mon, job, err := uc.UploadCSVAsync(ctx, file, filename, opts)
+	if err != nil {
+		return mon, err
+	}
+	if _, _, err := uc.ProcessCSVImport(ctx, job, func(int, int, int) {}); err != nil {
+		return nil, err
+	}
+	return uc.GetMonitoramento(ctx, mon.ID)
+}
+
+func TestMonitoringUseCase_UploadCSVAsync(t *testing.T) {
+	monRepo := repository.NewInMemoryRepository()
+	areaRepository := areaRepo.NewInMemoryRepository()
+	uuidGen := mockUUID()
+	parser := csv.NewParser(uuidGen)
+	scheduler := &fakeScheduler{}
+
+	uc := NewMonitoringUseCase(monRepo, areaRepository, areaRepo.NewInMemoryAreaRepository(), repository.NewInMemoryErroRepository(), repository.NewInMemoryProfileRepository(), parser, uuidGen, scheduler, storage.NewLocalStorage(t.TempDir()))
+
+	csvContent := `Id;Setor;Setor2;Cod.Fazenda;Desc.Fazenda;Quadra;Corte;Área Total;Desc. Textura Solo;Corte Atual;Reforma;Mês Colheita;Restrição;Camalote
+1;N;S;F1;Fazenda;Q1;1;100;Arg;1;2020;Jan;N;S
+2;N;S;F2;Fazenda;Q2;1;100;Arg;1;2020;Jan;N;N`
+
+	mon, job, err := uc.UploadCSVAsync(tenantCtx, strings.NewReader(csvContent), "teste.csv", UploadOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "processando", string(mon.Status))
+	assert.Equal(t, jobsDomain.JobTypeCSVImport, job.Type)
+
+	// Nada é importado antes do worker
+	_, total, _ := areaRepository.GetByMonitoramentoID(tenantCtx, mon.ID, 10, 0)
+	assert.Equal(t, 0, total)
+
+	var lastTotal, lastProcessed int
+	result, jobErrors, err := uc.ProcessCSVImport(tenantCtx, job, func(total, processed, errorCount int) {
+		lastTotal, lastProcessed = total, processed
+	})
+	require.NoError(t, err)
+	assert.Empty(t, jobErrors)
+	assert.Equal(t, 2, result.(jobsDomain.CSVImportResult).Areas)
+	assert.Equal(t, 2, lastTotal)
+	assert.Equal(t, 2, lastProcessed)
+
+	found, _ := uc.GetMonitoramento(tenantCtx, mon.ID)
+	assert.Equal(t, "concluido", string(found.Status))
+	assert.Equal(t, 2, found.TotalLinhas)
+
+	_, total, _ = areaRepository.GetByMonitoramentoID(tenantCtx, mon.ID, 10, 0)
+	assert.Equal(t, 2, total)
+}
+
+func TestMonitoringUseCase_ProcessCSVImport_InvalidCSV(t *testing.T) {
+	monRepo := repository.NewInMemoryRepository()
+	areaRepository := areaRepo.NewInMemoryRepository()
+	uuidGen := mockUUID()
+	scheduler := &fakeScheduler{}
+
+	uc := NewMonitoringUseCase(monRepo, areaRepository, areaRepo.NewInMemoryAreaRepository(), repository.NewInMemoryErroRepository(), repository.NewInMemoryProfileRepository(), csv.NewParser(uuidGen), uuidGen, scheduler, storage.NewLocalStorage(t.TempDir()))
+
+	mon, job, err := uc.UploadCSVAsync(tenantCtx, strings.NewReader("Campo1;Campo2\n1;2"), "invalido.csv", UploadOptions{})
+	require.NoError(t, err)
+
+	_, _, err = uc.ProcessCSVImport(tenantCtx, job, func(int, int, int) {})
+	assert.Error(t, err)
+
+	found, _ := uc.GetMonitoramento(tenantCtx, mon.ID)
+	assert.Equal(t, "erro", string(found.Status))
+}
+
+func TestMonitoringUseCase_UploadCSVAsync_SchedulerError(t *testing.T) {
+	monRepo := repository.NewInMemoryRepository()
+	areaRepository := areaRepo.NewInMemoryRepository()
+	uuidGen := mockUUID()
+	scheduler := &fakeScheduler{err: errors.New("redis indisponível")}
+
+	uc := NewMonitoringUseCase(monRepo, areaRepository, areaRepo.NewInMemoryAreaRepository(), repository.NewInMemoryErroRepository(), repository.NewInMemoryProfileRepository(), csv.NewParser(uuidGen), uuidGen, scheduler, storage.NewLocalStorage(t.TempDir()))
+
+	_, _, err := uc.UploadCSVAsync(tenantCtx, strings.NewReader("Id;Setor"), "teste.csv", UploadOptions{})
+	assert.Error(t, err)
+
+	mons, _, _ := monRepo.List(tenantCtx, 10, 0)
+	require.Len(t, mons, 1)
+	assert.Equal(t, "erro", string(mons[0].Status))
+}
+
+// failingAreaRepo falha a partir do lote failAt de CreateBatch
+type failingAreaRepo struct {
+	*areaRepo.InMemoryRepository
+	calls  int
+	failAt int
+}
+
+func (r *failingAreaRepo) CreateBatch(ctx context.Context, areas []*areaDomain.AreaMonitoramento) error {
+	r.calls++
+	if r.calls >= r.failAt {
+		return errors.New("falha no banco")
+	}
+	return r.InMemoryRepository.CreateBatch(ctx, areas)
+}
+
+func TestMonitoringUseCase_ImportCSV_BatchFailureRollsBack(t *testing.T) {
+	monRepo := repository.NewInMemoryRepository()
+	areaRepository := &failingAreaRepo{InMemoryRepository: areaRepo.NewInMemoryRepository(), failAt: 2}
+	uuidGen := mockUUID()
+
+	uc := NewMonitoringUseCase(monRepo, areaRepository, areaRepo.NewInMemoryAreaRepository(), repository.NewInMemoryErroRepository(), repository.NewInMemoryProfileRepository(), csv.NewParser(uuidGen), uuidGen, &fakeScheduler{}, storage.NewLocalStorage(t.TempDir()))
+
+	var sb strings.Builder
+	sb.WriteString("Id;Setor;Setor2;Cod.Fazenda;Desc.Fazenda;Quadra;Corte;Área Total;Desc. Textura Solo;Corte Atual;Reforma;Mês Colheita;Restrição\n")
+	for i := 1; i <= csvImportBatchSize+10; i++ {
+		fmt.Fprintf(&sb, "%d;N;S;F%d;Fazenda;Q;1;100;Arg;1;2020;Jan;N\n", i, i)
+	}
+
+	_, err := importFile(tenantCtx, uc, strings.NewReader(sb.String()), "grande.csv", UploadOptions{})
+	assert.Error(t, err)
+	assert.Equal(t, 2, areaRepository.calls)
+
+	mons, _, _ := monRepo.List(tenantCtx, 10, 0)
+	require.Len(t, mons, 1)
+	assert.Equal(t, "erro", string(mons[0].Status))
+
+	// O primeiro lote já inserido é removido
+	_, total, _ := areaRepository.GetByMonitoramentoID(tenantCtx, mons[0].ID, 10, 0)
+	assert.Equal(t, 0, total)
+}
+
+func TestMonitoringUseCase_ParseErrorsArePersisted(t *testing.T) {
+	monRepo := repository.NewInMemoryRepository()
+	areaRepository := areaRepo.NewInMemoryRepository()
+	uuidGen := mockUUID()
+
+	uc := NewMonitoringUseCase(monRepo, areaRepository, areaRepo.NewInMemoryAreaRepository(), repository.NewInMemoryErroRepository(), repository.NewInMemoryProfileRepository(), csv.NewParser(uuidGen), uuidGen, &fakeScheduler{}, storage.NewLocalStorage(t.TempDir()))
+
+	csvContent := `Id;Setor;Setor2;Cod.Fazenda;Desc.Fazenda;Quadra;Corte;Área Total;Desc. Textura Solo;Corte Atual;Reforma;Mês Colheita;Restrição
+1;N;S;F1;Fazenda;Q1;1;100;Arg;1;2020;Jan;N
+2;N;S;F2
+3;N;S;F3;Fazenda;Q3;1;100;Arg;1;2020;Jan;N`
+
+	mon, err := importFile(tenantCtx, uc, strings.NewReader(csvContent), "teste.csv", UploadOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 2, mon.TotalLinhas)
+	assert.Equal(t, 1, mon.TotalErros)
+
+	erros, err := uc.ListErros(tenantCtx, mon.ID)
+	require.NoError(t, err)
+	require.Len(t, erros, 1)
+	assert.Equal(t, 3, erros[0].Linha)
+
+	var sb strings.Builder
+	require.NoError(t, uc.WriteErrosCSV(tenantCtx, mon.ID, &sb))
+	lines := strings.Split(strings.TrimSpace(sb.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.Contains(t, lines[0], "Linha;Erro;Id;Setor")
+	assert.True(t, strings.HasPrefix(lines[1], "3;"))
+	assert.True(t, strings.HasSuffix(lines[1], ";2;N;S;F2"))
+
+	// Outro tenant não enxerga os erros
+	otherCtx := sharedContext.WithTenant(context.Background(), "client-b", "user-b")
+	_, err = uc.ListErros(otherCtx, mon.ID)
+	assert.Equal(t, sharedErrors.ErrMonitoramentoNotFound, err)
+}
+
+func TestMonitoringUseCase_InvalidCSV_PersistsErrors(t *testing.T) {
+	monRepo := repository.NewInMemoryRepository()
+	areaRepository := areaRepo.NewInMemoryRepository()
+	uuidGen := mockUUID()
+
+	uc := NewMonitoringUseCase(monRepo, areaRepository, areaRepo.NewInMemoryAreaRepository(), repository.NewInMemoryErroRepository(), repository.NewInMemoryProfileRepository(), csv.NewParser(uuidGen), uuidGen, &fakeScheduler{}, storage.NewLocalStorage(t.TempDir()))
+
+	csvContent := `Id;Setor;Setor2;Cod.Fazenda;Desc.Fazenda;Quadra;Corte;Área Total;Desc. Textura Solo;Corte Atual;Reforma;Mês Colheita;Restrição
+1;N;S
+2;N;S`
+
+	_, err := importFile(tenantCtx, uc, strings.NewReader(csvContent), "teste.csv", UploadOptions{})
+	assert.ErrorIs(t, err, sharedErrors.ErrInvalidCSV)
+
+	mons, _, _ := monRepo.List(tenantCtx, 10, 0)
+	require.Len(t, mons, 1)
+	assert.Equal(t, "erro", string(mons[0].Status))
+	assert.Equal(t, 2, mons[0].TotalErros)
+
+	erros, err := uc.ListErros(tenantCtx, mons[0].ID)
+	require.NoError(t, err)
+	assert.Len(t, erros, 2)
+}
+
+func TestMonitoringUseCase_UploadCSVAsync_StrictValidation(t *testing.T) {
+	monRepo := repository.NewInMemoryRepository()
+	areaRepository := areaRepo.NewInMemoryRepository()
+	uuidGen := mockUUID()
+	scheduler := &fakeScheduler{}
+
+	uc := NewMonitoringUseCase(monRepo, areaRepository, areaRepo.NewInMemoryAreaRepository(), repository.NewInMemoryErroRepository(), repository.NewInMemoryProfileRepository(), csv.NewParser(uuidGen), uuidGen, scheduler, storage.NewLocalStorage(t.TempDir()))
+
+	csvContent := `Id;Setor;Setor2;Cod.Fazenda;Desc.Fazenda;Quadra;Corte;Área Total;Desc. Textura Solo;Corte Atual;Reforma;Mês Colheita;Restrição;Camalote
+1;N;S;F1;Fazenda;Q1;1;1.500,25;Arg;1;2020;Jan;N;S
+2;N;S;F2;Fazenda;Q2;1;dez;Arg;1;2020;Jan;N;S`
+
+	mon, job, err := uc.UploadCSVAsync(tenantCtx, strings.NewReader(csvContent), "teste.csv", UploadOptions{Validation: csv.ValidationStrict})
+	require.NoError(t, err)
+
+	var payload jobsDomain.CSVImportPayload
+	require.NoError(t, json.Unmarshal(job.Payload, &payload))
+	assert.Equal(t, "strict", payload.Validation)
+
+	_, _, err = uc.ProcessCSVImport(tenantCtx, job, func(int, int, int) {})
+	require.NoError(t, err)
+
+	found, _ := uc.GetMonitoramento(tenantCtx, mon.ID)
+	assert.Equal(t, 1, found.TotalLinhas)
+	assert.Equal(t, 1, found.TotalErros)
+
+	areas, _, _ := areaRepository.GetByMonitoramentoID(tenantCtx, mon.ID, 10, 0)
+	require.Len(t, areas, 1)
+	assert.Equal(t, 1500.25, areas[0].AreaTotal)
+}
+
+func TestMonitoringUseCase_UploadCSVAsync_InvalidValidationMode(t *testing.T) {
+	monRepo := repository.NewInMemoryRepository()
+	uuidGen := mockUUID()
+
+	uc := NewMonitoringUseCase(monRepo, areaRepo.NewInMemoryRepository(), areaRepo.NewInMemoryAreaRepository(), repository.NewInMemoryErroRepository(), repository.NewInMemoryProfileRepository(), csv.NewParser(uuidGen), uuidGen, &fakeScheduler{}, storage.NewLocalStorage(t.TempDir()))
+
+	_, _, err := uc.UploadCSVAsync(tenantCtx, strings.NewReader("Id;Setor"), "teste.csv", UploadOptions{Validation: "rigido"})
+	assert.Equal(t, sharedErrors.ErrInvalidValidationMode, err)
+
+	mons, _, _ := monRepo.List(tenantCtx, 10, 0)
+	assert.Empty(t, mons)
+}
+
+func TestMonitoringUseCase_UpsertModeLinksAreasAcrossUploads(t *testing.T) {
+	monRepo := repository.NewInMemoryRepository()
+	areaRepository := areaRepo.NewInMemoryRepository()
+	catalog := areaRepo.NewInMemoryAreaRepository()
+	uuidGen := mockUUID()
+
+	uc := NewMonitoringUseCase(monRepo, areaRepository, catalog, repository.NewInMemoryErroRepository(), repository.NewInMemoryProfileRepository(), csv.NewParser(uuidGen), uuidGen, &fakeScheduler{}, storage.NewLocalStorage(t.TempDir()))
+
+	semana1 := `Id;Setor;Setor2;Cod.Fazenda;Desc.Fazenda;Quadra;Corte;Área Total;Desc. Textura Solo;Corte Atual;Reforma;Mês Colheita;Restrição;Camalote
+;N;S;F1;Fazenda;Q1;1;100;Arg;1;2020;Jan;N;S
+;N;S;F1;Fazenda;Q2;1;100;Arg;1;2020;Jan;N;N`
+	semana2 := `Id;Setor;Setor2;Cod.Fazenda;Desc.Fazenda;Quadra;Corte;Área Total;Desc. Textura Solo;Corte Atual;Reforma;Mês Colheita;Restrição;Camalote
+;N;S;F1;Fazenda;Q1;1;100;Arg;1;2020;Jan;N;N
+;N;S;F2;Fazenda;Q1;1;100;Arg;1;2020;Jan;N;S`
+
+	upsert := UploadOptions{Mode: "upsert"}
+	mon1, err := importFile(tenantCtx, uc, strings.NewReader(semana1), "semana1.csv", upsert)
+	require.NoError(t, err)
+	mon2, err := importFile(tenantCtx, uc, strings.NewReader(semana2), "semana2.csv", upsert)
+	require.NoError(t, err)
+
+	// F1/Q1 é a mesma área física nas duas semanas; F1/Q2 e F2/Q1 são novas
+	assert.Equal(t, 3, catalog.Count())
+
+	obs1, _, _ := areaRepository.GetByMonitoramentoID(tenantCtx, mon1.ID, 10, 0)
+	obs2, _, _ := areaRepository.GetByMonitoramentoID(tenantCtx, mon2.ID, 10, 0)
+	byKey := func(obs []*areaDomain.AreaMonitoramento, key string) *areaDomain.AreaMonitoramento {
+		for _, o := range obs {
+			if o.ExternalKey == key {
+				return o
+			}
+		}
+		return nil
+	}
+
+	q1Semana1 := byKey(obs1, "F1/Q1")
+	q1Semana2 := byKey(obs2, "F1/Q1")
+	require.NotNil(t, q1Semana1)
+	require.NotNil(t, q1Semana2)
+	assert.NotEmpty(t, q1Semana1.AreaID)
+	assert.Equal(t, q1Semana1.AreaID, q1Semana2.AreaID)
+	assert.NotEqual(t, q1Semana1.ID, q1Semana2.ID)
+
+	historico, total, err := areaRepository.GetByAreaID(tenantCtx, q1Semana1.AreaID, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 2, total)
+	assert.True(t, historico[0].PragasData.HasPraga("Camalote"))
+	assert.False(t, historico[1].PragasData.HasPraga("Camalote"))
+}
+
+func TestMonitoringUseCase_AppendModeDoesNotLinkAreas(t *testing.T) {
+	monRepo := repository.NewInMemoryRepository()
+	areaRepository := areaRepo.NewInMemoryRepository()
+	catalog := areaRepo.NewInMemoryAreaRepository()
+	uuidGen := mockUUID()
+
+	uc := NewMonitoringUseCase(monRepo, areaRepository, catalog, repository.NewInMemoryErroRepository(), repository.NewInMemoryProfileRepository(), csv.NewParser(uuidGen), uuidGen, &fakeScheduler{}, storage.NewLocalStorage(t.TempDir()))
+
+	csvContent := `Id;Setor;Setor2;Cod.Fazenda;Desc.Fazenda;Quadra;Corte;Área Total;Desc. Textura Solo;Corte Atual;Reforma;Mês Colheita;Restrição
+7;N;S;F1;Fazenda;Q1;1;100;Arg;1;2020;Jan;N`
+
+	mon, err := importFile(tenantCtx, uc, strings.NewReader(csvContent), "teste.csv", UploadOptions{})
+	require.NoError(t, err)
+
+	obs, _, _ := areaRepository.GetByMonitoramentoID(tenantCtx, mon.ID, 10, 0)
+	require.Len(t, obs, 1)
+	assert.Equal(t, "7", obs[0].ExternalKey)
+	assert.Empty(t, obs[0].AreaID)
+	assert.Equal(t, 0, catalog.Count())
+
+	_, err = importFile(tenantCtx, uc, strings.NewReader(csvContent), "teste.csv", UploadOptions{Mode: "replace"})
+	assert.Equal(t, sharedErrors.ErrInvalidImportMode, err)
+}
+
+func TestMonitoringUseCase_ImportProfileCRUD(t *testing.T) {
+	uuidGen := mockUUID()
+	uc := NewMonitoringUseCase(repository.NewInMemoryRepository(), areaRepo.NewInMemoryRepository(), areaRepo.NewInMemoryAreaRepository(), repository.NewInMemoryErroRepository(), repository.NewInMemoryProfileRepository(), csv.NewParser(uuidGen), uuidGen, &fakeScheduler{}, storage.NewLocalStorage(t.TempDir()))
+
+	req := dto.ImportProfileRequest{
+		Nome:      " Usina Norte ",
+		Separador: "tab",
+		Encoding:  "Windows-1252",
+		Colunas:   map[string]string{"Talhao": "Quadra"},
+	}
+	profile, err := uc.CreateImportProfile(tenantCtx, req)
+	require.NoError(t, err)
+	assert.Equal(t, "Usina Norte", profile.Nome)
+	assert.Equal(t, "windows-1252", profile.Encoding)
+	assert.NotNil(t, profile.Pragas)
+
+	_, err = uc.CreateImportProfile(tenantCtx, dto.ImportProfileRequest{Nome: "Usina Norte"})
+	assert.Equal(t, sharedErrors.ErrDuplicateImportProfile, err)
+
+	_, err = uc.CreateImportProfile(tenantCtx, dto.ImportProfileRequest{Nome: "Outro", Colunas: map[string]string{"X": "Talhão"}})
+	assert.ErrorIs(t, err, sharedErrors.ErrInvalidImportProfile)
+
+	_, err = uc.CreateImportProfile(tenantCtx, dto.ImportProfileRequest{Nome: "Outro", Separador: "#"})
+	assert.ErrorIs(t, err, sharedErrors.ErrInvalidImportProfile)
+
+	req.Nome = "Usina Sul"
+	updated, err := uc.UpdateImportProfile(tenantCtx, profile.ID, req)
+	require.NoError(t, err)
+	assert.Equal(t, "Usina Sul", updated.Nome)
+
+	// Outro tenant não enxerga o perfil
+	otherCtx := sharedContext.WithTenant(context.Background(), "client-b", "user-b")
+	_, err = uc.GetImportProfile(otherCtx, profile.ID)
+	assert.Equal(t, sharedErrors.ErrImportProfileNotFound, err)
+
+	profiles, err := uc.ListImportProfiles(tenantCtx)
+	require.NoError(t, err)
+	require.Len(t, profiles, 1)
+
+	require.NoError(t, uc.DeleteImportProfile(tenantCtx, profile.ID))
+	assert.Equal(t, sharedErrors.ErrImportProfileNotFound, uc.DeleteImportProfile(tenantCtx, profile.ID))
+}
+
+func TestMonitoringUseCase_UploadCSVAsync_Profile(t *testing.T) {
+	areaRepository := areaRepo.NewInMemoryRepository()
+	uuidGen := mockUUID()
+	scheduler := &fakeScheduler{}
+	uc := NewMonitoringUseCase(repository.NewInMemoryRepository(), areaRepository, areaRepo.NewInMemoryAreaRepository(), repository.NewInMemoryErroRepository(), repository.NewInMemoryProfileRepository(), csv.NewParser(uuidGen), uuidGen, scheduler, storage.NewLocalStorage(t.TempDir()))
+
+	profile, err := uc.CreateImportProfile(tenantCtx, dto.ImportProfileRequest{
+		Nome:        "usina",
+		Separador:   ",",
+		Colunas:     map[string]string{"Codigo": "Id", "Fazenda": "Cod.Fazenda", "Talhao": "Quadra", "Sub": "Setor2"},
+		Pragas:      map[string]string{"Capim": "Capim Colchão"},
+		Vocabulario: map[string]string{"Alto": "A"},
+	})
+	require.NoError(t, err)
+
+	_, _, err = uc.UploadCSVAsync(tenantCtx, strings.NewReader(""), "teste.csv", UploadOptions{Profile: "inexistente"})
+	assert.Equal(t, sharedErrors.ErrImportProfileNotFound, err)
+
+	csvContent := "Codigo,Fazenda,Talhao,Setor,Sub,Capim\n1,F1,Q1,Norte,N1,Alto\n"
+	mon, job, err := uc.UploadCSVAsync(tenantCtx, strings.NewReader(csvContent), "usina.csv", UploadOptions{Profile: "usina"})
+	require.NoError(t, err)
+	assert.Contains(t, string(job.Payload), profile.ID)
+
+	// O worker usa a versão atual do perfil, mesmo após renomeado
+	_, err = uc.UpdateImportProfile(tenantCtx, profile.ID, dto.ImportProfileRequest{
+		Nome:        "usina renomeada",
+		Separador:   ",",
+		Colunas:     map[string]string{"Codigo": "Id", "Fazenda": "Cod.Fazenda", "Talhao": "Quadra", "Sub": "Setor2"},
+		Pragas:      map[string]string{"Capim": "Capim Colchão"},
+		Vocabulario: map[string]string{"Alto": "A"},
+	})
+	require.NoError(t, err)
+
+	_, _, err = uc.ProcessCSVImport(tenantCtx, job, func(int, int, int) {})
+	require.NoError(t, err)
+
+	areas, total, _ := areaRepository.GetByMonitoramentoID(tenantCtx, mon.ID, 10, 0)
+	require.Equal(t, 1, total)
+	assert.Equal(t, "F1", areas[0].CodFazenda)
+	assert.Equal(t, "A", areas[0].PragasData.Pragas["Capim Colchão"].Nivel)
+}
+
+// xlsxFile monta um .xlsx mínimo com uma aba "Dados" (strings inline; "n:" marca célula numérica)
+func xlsxFile(t *testing.T, rows map[int][]string) *bytes.Reader {
+	t.Helper()
+
+	var sheetData strings.Builder
+	for n := 1; n <= 100; n++ {
+		row, ok := rows[n]
+		if !ok {
+			continue
+		}
+		fmt.Fprintf(&sheetData, `<row r="%d">`, n)
+		for i, v := range row {
+			if num, ok := strings.CutPrefix(v, "n:"); ok {
+				fmt.Fprintf(&sheetData, `<c r="%c%d"><v>%s</v></c>`, 'A'+i, n, num)
+				continue
+			}
+			fmt.Fprintf(&sheetData, `<c r="%c%d" t="inlineStr"><is><t>%s</t></is></c>`, 'A'+i, n, v)
+		}
+		sheetData.WriteString(`</row>`)
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range map[string]string{
+		"xl/workbook.xml":            `<workbook xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets><sheet name="Dados" sheetId="1" r:id="rId1"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships><Relationship Id="rId1" Target="worksheets/sheet1.xml"/></Relationships>`,
+		"xl/worksheets/sheet1.xml":   `<worksheet><sheetData>` + sheetData.String() + `</sheetData></worksheet>`,
+	} {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	return bytes.NewReader(buf.Bytes())
+}
+
+func TestMonitoringUseCase_UploadCSVAsync_XLSX(t *testing.T) {
+	areaRepository := areaRepo.NewInMemoryRepository()
+	erroRepo := repository.NewInMemoryErroRepository()
+	uuidGen := mockUUID()
+	scheduler := &fakeScheduler{}
+	uc := NewMonitoringUseCase(repository.NewInMemoryRepository(), areaRepository, areaRepo.NewInMemoryAreaRepository(), erroRepo, repository.NewInMemoryProfileRepository(), csv.NewParser(uuidGen), uuidGen, scheduler, storage.NewLocalStorage(t.TempDir()))
+
+	header := []string{"Id", "Setor", "Setor2", "Cod.Fazenda", "Quadra", "Área Total", "Restrição", "Camalote"}
+	rows := map[int][]string{
+		1: header,
+		2: {"1", "Norte", "N1", "F1", "Q1", "150,5", "N", "A"},
+		// linha 3 vazia: omitida no arquivo
+		4: {"2", "Norte", "N1", "F1", "Q2", "abc", "N", "Z"},
+	}
+
+	_, _, err := uc.UploadCSVAsync(tenantCtx, xlsxFile(t, rows), "campo.xlsx", UploadOptions{Sheet: "Resumo"})
+	assert.ErrorIs(t, err, sharedErrors.ErrInvalidSpreadsheet)
+
+	mon, job, err := uc.UploadCSVAsync(tenantCtx, xlsxFile(t, rows), "campo.xlsx", UploadOptions{Validation: csv.ValidationStrict, Sheet: "dados"})
+	require.NoError(t, err)
+
+	_, _, err = uc.ProcessCSVImport(tenantCtx, job, func(int, int, int) {})
+	require.NoError(t, err)
+
+	areas, total, _ := areaRepository.GetByMonitoramentoID(tenantCtx, mon.ID, 10, 0)
+	require.Equal(t, 1, total)
+	assert.Equal(t, 150.5, areas[0].AreaTotal)
+	assert.Equal(t, "A", areas[0].PragasData.Pragas["Camalote"].Nivel)
+
+	// O erro aponta a linha do Excel
+	erros, err := uc.ListErros(tenantCtx, mon.ID)
+	require.NoError(t, err)
+	require.Len(t, erros, 1)
+	assert.Equal(t, 4, erros[0].Linha)
+	assert.Contains(t, erros[0].Erro, `coluna 'Área Total': número inválido "abc"`)
+
+	found, _ := uc.GetMonitoramento(tenantCtx, mon.ID)
+	assert.Equal(t, header, found.Cabecalho)
+}
+
+func TestMonitoringUseCase_UploadCSVAsync_XLSXNumericCells(t *testing.T) {
+	areaRepository := areaRepo.NewInMemoryRepository()
+	uuidGen := mockUUID()
+	uc := NewMonitoringUseCase(repository.NewInMemoryRepository(), areaRepository, areaRepo.NewInMemoryAreaRepository(), repository.NewInMemoryErroRepository(), repository.NewInMemoryProfileRepository(), csv.NewParser(uuidGen), uuidGen, &fakeScheduler{}, storage.NewLocalStorage(t.TempDir()))
+
+	// Células numéricas usam ponto decimal; a célula de texto segue o formato brasileiro
+	rows := map[int][]string{
+		1: {"Id", "Setor", "Setor2", "Cod.Fazenda", "Quadra", "Corte", "Área Total", "Restrição", "Camalote", "Herb 1 Camalote", "Dose 1 Camalote", "Herb 2 Camalote", "Dose 2 Camalote"},
+		2: {"1", "Norte", "N1", "F1", "Q1", "n:3", "n:12.5", "N", "A", "Glifosato", "n:0.125", "Diuron", "1.234,5"},
+	}
+
+	mon, job, err := uc.UploadCSVAsync(tenantCtx, xlsxFile(t, rows), "campo.xlsx", UploadOptions{Validation: csv.ValidationStrict})
+	require.NoError(t, err)
+	_, _, err = uc.ProcessCSVImport(tenantCtx, job, func(int, int, int) {})
+	require.NoError(t, err)
+
+	areas, total, _ := areaRepository.GetByMonitoramentoID(tenantCtx, mon.ID, 10, 0)
+	require.Equal(t, 1, total)
+	assert.Equal(t, 3, areas[0].Corte)
+	assert.Equal(t, 12.5, areas[0].AreaTotal)
+	aplicacoes := areas[0].PragasData.Pragas["Camalote"].Aplicacoes
+	require.Len(t, aplicacoes, 2)
+	assert.Equal(t, 0.125, aplicacoes[0].Dose)
+	assert.Equal(t, 1234.5, aplicacoes[1].Dose)
+}
+
+func TestMonitoringUseCase_UploadCSVAsync_Encoding(t *testing.T) {
+	areaRepository := areaRepo.NewInMemoryRepository()
+	uuidGen := mockUUID()
+	scheduler := &fakeScheduler{}
+	uc := NewMonitoringUseCase(repository.NewInMemoryRepository(), areaRepository, areaRepo.NewInMemoryAreaRepository(), repository.NewInMemoryErroRepository(), repository.NewInMemoryProfileRepository(), csv.NewParser(uuidGen), uuidGen, scheduler, storage.NewLocalStorage(t.TempDir()))
+
+	_, _, err := uc.UploadCSVAsync(tenantCtx, strings.NewReader(""), "teste.csv", UploadOptions{Encoding: "utf-16"})
+	assert.Equal(t, sharedErrors.ErrInvalidEncoding, err)
+
+	// Exportação do Excel antigo em Windows-1252: "Área Total", "Restrição", "Colonião"
+	csvContent := "Id;Setor;Setor2;Cod.Fazenda;Desc.Fazenda;Quadra;\xc1rea Total;Restri\xe7\xe3o;Coloni\xe3o\n" +
+		"1;Norte;N1;F1;Fazenda S\xe3o Jo\xe3o;Q1;150,5;N;A\n"
+
+	mon, job, err := uc.UploadCSVAsync(tenantCtx, strings.NewReader(csvContent), "teste.csv", UploadOptions{Validation: csv.ValidationStrict, Encoding: "cp1252"})
+	require.NoError(t, err)
+	assert.Contains(t, string(job.Payload), `"encoding":"windows-1252"`)
+
+	_, _, err = uc.ProcessCSVImport(tenantCtx, job, func(int, int, int) {})
+	require.NoError(t, err)
+
+	areas, total, _ := areaRepository.GetByMonitoramentoID(tenantCtx, mon.ID, 10, 0)
+	require.Equal(t, 1, total)
+	assert.Equal(t, "Fazenda São João", areas[0].DescFazenda)
+	assert.Equal(t, 150.5, areas[0].AreaTotal)
+	assert.Equal(t, "A", areas[0].PragasData.Pragas["Colonião"].Nivel)
+}
+
+func TestMonitoringUseCase_DuplicateUpload(t *testing.T) {
+	monRepo := repository.NewInMemoryRepository()
+	uuidGen := mockUUID()
+
+	uc := NewMonitoringUseCase(monRepo, areaRepo.NewInMemoryRepository(), areaRepo.NewInMemoryAreaRepository(), repository.NewInMemoryErroRepository(), repository.NewInMemoryProfileRepository(), csv.NewParser(uuidGen), uuidGen, &fakeScheduler{}, storage.NewLocalStorage(t.TempDir()))
+
+	csvContent := `Id;Setor;Setor2;Cod.Fazenda;Desc.Fazenda;Quadra;Corte;Área Total;Desc. Textura Solo;Corte Atual;Reforma;Mês Colheita;Restrição
+1;N;S;F1;Fazenda;Q1;1;100;Arg;1;2020;Jan;N`
+
+	first, err := importFile(tenantCtx, uc, strings.NewReader(csvContent), "semana1.csv", UploadOptions{})
+	require.NoError(t, err)
+	assert.Len(t, first.Checksum, 64)
+
+	// Mesmo conteúdo com outro nome: recusado, com o monitoramento existente
+	existing, err := importFile(tenantCtx, uc, strings.NewReader(csvContent), "copia.csv", UploadOptions{})
+	assert.Equal(t, sharedErrors.ErrDuplicateUpload, err)
+	require.NotNil(t, existing)
+	assert.Equal(t, first.ID, existing.ID)
+
+	forced, err := importFile(tenantCtx, uc, strings.NewReader(csvContent), "copia.csv", UploadOptions{Force: true})
+	require.NoError(t, err)
+	assert.NotEqual(t, first.ID, forced.ID)
+	assert.Equal(t, first.Checksum, forced.Checksum)
+
+	// O checksum é por tenant
+	otherCtx := sharedContext.WithTenant(context.Background(), "client-b", "user-b")
+	_, err = importFile(otherCtx, uc, strings.NewReader(csvContent), "semana1.csv", UploadOptions{})
+	assert.NoError(t, err)
+
+	_, total, _ := monRepo.List(tenantCtx, 10, 0)
+	assert.Equal(t, 2, total)
+}
+
+func TestMonitoringUseCase_DuplicateUpload_OnlyCompleted(t *testing.T) {
+	monRepo := repository.NewInMemoryRepository()
+	uuidGen := mockUUID()
+
+	uc := NewMonitoringUseCase(monRepo, areaRepo.NewInMemoryRepository(), areaRepo.NewInMemoryAreaRepository(), repository.NewInMemoryErroRepository(), repository.NewInMemoryProfileRepository(), csv.NewParser(uuidGen), uuidGen, &fakeScheduler{}, storage.NewLocalStorage(t.TempDir()))
+
+	// Um arquivo que falhou na importação pode ser reenviado sem force
+	_, err := importFile(tenantCtx, uc, strings.NewReader("Campo1;Campo2\n1;2"), "invalido.csv", UploadOptions{})
+	require.Error(t, err)
+	_, err = importFile(tenantCtx, uc, strings.NewReader("Campo1;Campo2\n1;2"), "invalido.csv", UploadOptions{})
+	assert.NotEqual(t, sharedErrors.ErrDuplicateUpload, err)
+
+	_, total, _ := monRepo.List(tenantCtx, 10, 0)
+	assert.Equal(t, 2, total)
+}
+
+func TestMonitoringUseCase_UploadCSVAsync_Duplicate(t *testing.T) {
+	monRepo := repository.NewInMemoryRepository()
+	uuidGen := mockUUID()
+	scheduler := &fakeScheduler{}
+	files := storage.NewLocalStorage(t.TempDir())
+
+	uc := NewMonitoringUseCase(monRepo, areaRepo.NewInMemoryRepository(), areaRepo.NewInMemoryAreaRepository(), repository.NewInMemoryErroRepository(), repository.NewInMemoryProfileRepository(), csv.NewParser(uuidGen), uuidGen, scheduler, files)
+
+	csvContent := `Id;Setor;Setor2;Cod.Fazenda;Desc.Fazenda;Quadra;Corte;Área Total;Desc. Textura Solo;Corte Atual;Reforma;Mês Colheita;Restrição
+1;N;S;F1;Fazenda;Q1;1;100;Arg;1;2020;Jan;N`
+
+	mon, job, err := uc.UploadCSVAsync(tenantCtx, strings.NewReader(csvContent), "teste.csv", UploadOptions{})
+	require.NoError(t, err)
+
+	// Enquanto o primeiro não conclui, o reenvio não é considerado duplicado
+	pending, pendingJob, err := uc.UploadCSVAsync(tenantCtx, strings.NewReader(csvContent), "teste.csv", UploadOptions{})
+	require.NoError(t, err)
+	_, _, err = uc.ProcessCSVImport(tenantCtx, pendingJob, func(int, int, int) {})
+	require.NoError(t, err)
+
+	_, _, err = uc.ProcessCSVImport(tenantCtx, job, func(int, int, int) {})
+	require.NoError(t, err)
+
+	existing, dupJob, err := uc.UploadCSVAsync(tenantCtx, strings.NewReader(csvContent), "teste.csv", UploadOptions{})
+	assert.Equal(t, sharedErrors.ErrDuplicateUpload, err)
+	assert.Nil(t, dupJob)
+	require.NotNil(t, existing)
+	assert.Contains(t, []string{mon.ID, pending.ID}, existing.ID)
+
+	// O arquivo recusado não fica no storage
+	keys, err := files.List(tenantCtx, "client-a/")
+	require.NoError(t, err)
+	assert.Len(t, keys, 2)
+
+	_, total, _ := monRepo.List(tenantCtx, 10, 0)
+	assert.Equal(t, 2, total)
+}
+
+func TestMonitoringUseCase_PreviewUpload(t *testing.T) {
+	monRepo := repository.NewInMemoryRepository()
+	areaRepository := areaRepo.NewInMemoryRepository()
+	uuidGen := mockUUID()
+
+	uc := NewMonitoringUseCase(monRepo, areaRepository, areaRepo.NewInMemoryAreaRepository(), repository.NewInMemoryErroRepository(), repository.NewInMemoryProfileRepository(), csv.NewParser(uuidGen), uuidGen, &fakeScheduler{}, storage.NewLocalStorage(t.TempDir()))
+
+	csvContent := `Id;Setor;Setor2;Cod.Fazenda;Desc.Fazenda;Quadra;Corte;Área Total;Desc. Textura Solo;Corte Atual;Reforma;Mês Colheita;Restrição;Camalote;Vassoura
+1;N;S;F1;Fazenda;Q1;1;100;Arg;1;2020;Jan;N;S;N
+2;N;S;F1;Fazenda;Q2;x;100;Arg;1;2020;Jan;N;N;S
+3;N;S;F1;Fazenda;Q3;1;100;Arg;1;2020;Jan;N;N;N
+4;N;S;F1;Fazenda;Q4;1;100;Arg;1;2020;Jan;N;S;S`
+
+	preview, err := uc.PreviewUpload(tenantCtx, strings.NewReader(csvContent), "teste.csv", UploadOptions{Validation: "strict"}, 2)
+	require.NoError(t, err)
+
+	assert.Equal(t, "csv", string(preview.Format))
+	assert.Equal(t, ";", preview.Separador)
+	assert.Equal(t, "Quadra", preview.Colunas["Quadra"])
+	assert.Equal(t, []string{"Camalote", "Vassoura"}, preview.Pragas)
+	assert.Equal(t, 3, preview.TotalLinhas)
+	assert.Equal(t, 1, preview.TotalErros)
+	require.Len(t, preview.Areas, 2)
+	assert.Equal(t, "Q1", preview.Areas[0].Quadra)
+	assert.Equal(t, "Q3", preview.Areas[1].Quadra)
+	require.Len(t, preview.Erros, 1)
+	assert.Equal(t, 3, preview.Erros[0].Linha)
+
+	// Nada é gravado
+	_, total, _ := monRepo.List(tenantCtx, 10, 0)
+	assert.Equal(t, 0, total)
+	_, total, _ = areaRepository.SearchByFazenda(tenantCtx, "F1", 10, 0)
+	assert.Equal(t, 0, total)
+}
+
+func TestMonitoringUseCase_PreviewUpload_NoValidRows(t *testing.T) {
+	uuidGen := mockUUID()
+	uc := NewMonitoringUseCase(repository.NewInMemoryRepository(), areaRepo.NewInMemoryRepository(), areaRepo.NewInMemoryAreaRepository(), repository.NewInMemoryErroRepository(), repository.NewInMemoryProfileRepository(), csv.NewParser(uuidGen), uuidGen, &fakeScheduler{}, storage.NewLocalStorage(t.TempDir()))
+
+	csvContent := `Id;Setor;Setor2;Cod.Fazenda;Desc.Fazenda;Quadra;Corte;Área Total;Desc. Textura Solo;Corte Atual;Reforma;Mês Colheita;Restrição
+1;N;S;F1;Fazenda;Q1;x;100;Arg;1;2020;Jan;N`
+
+	// Sem linhas válidas a prévia ainda mostra os erros
+	preview, err := uc.PreviewUpload(tenantCtx, strings.NewReader(csvContent), "teste.csv", UploadOptions{Validation: "strict"}, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 0, preview.TotalLinhas)
+	assert.Empty(t, preview.Areas)
+	require.Len(t, preview.Erros, 1)
+
+	// Header sem os campos obrigatórios não tem prévia
+	_, err = uc.PreviewUpload(tenantCtx, strings.NewReader("Campo1;Campo2\n1;2"), "teste.csv", UploadOptions{}, 0)
+	assert.ErrorIs(t, err, sharedErrors.ErrInvalidCSV)
+
+	_, err = uc.PreviewUpload(tenantCtx, strings.NewReader(csvContent), "teste.csv", UploadOptions{Profile: "inexistente"}, 0)
+	assert.Equal(t, sharedErrors.ErrImportProfileNotFound, err)
+}
+
+func TestMonitoringUseCase_ReprocessMonitoramento(t *testing.T) {
+	areaRepository := areaRepo.NewInMemoryRepository()
+	uuidGen := mockUUID()
+	scheduler := &fakeScheduler{}
+	uc := NewMonitoringUseCase(repository.NewInMemoryRepository(), areaRepository, areaRepo.NewInMemoryAreaRepository(), repository.NewInMemoryErroRepository(), repository.NewInMemoryProfileRepository(), csv.NewParser(uuidGen), uuidGen, scheduler, storage.NewLocalStorage(t.TempDir()))
+
+	// Sem perfil o layout do cliente não é reconhecido
+	csvContent := "Codigo,Fazenda,Talhao,Setor,Sub,Capim\n1,F1,Q1,Norte,N1,Alto\n2,F1,Q2,Norte,N1,\n"
+	mon, job, err := uc.UploadCSVAsync(tenantCtx, strings.NewReader(csvContent), "usina.csv", UploadOptions{})
+	require.NoError(t, err)
+
+	_, _, err = uc.ReprocessMonitoramento(tenantCtx, mon.ID, UploadOptions{})
+	assert.Equal(t, sharedErrors.ErrMonitoramentoProcessing, err)
+
+	_, _, err = uc.ProcessCSVImport(tenantCtx, job, func(int, int, int) {})
+	require.Error(t, err)
+	found, _ := uc.GetMonitoramento(tenantCtx, mon.ID)
+	assert.Equal(t, "erro", string(found.Status))
+
+	_, err = uc.CreateImportProfile(tenantCtx, dto.ImportProfileRequest{
+		Nome:        "usina",
+		Colunas:     map[string]string{"Codigo": "Id", "Fazenda": "Cod.Fazenda", "Talhao": "Quadra", "Sub": "Setor2"},
+		Pragas:      map[string]string{"Capim": "Capim Colchão"},
+		Vocabulario: map[string]string{"Alto": "A"},
+	})
+	require.NoError(t, err)
+
+	reprocessed, job, err := uc.ReprocessMonitoramento(tenantCtx, mon.ID, UploadOptions{Profile: "usina"})
+	require.NoError(t, err)
+	assert.Equal(t, mon.ID, reprocessed.ID)
+	assert.Equal(t, "processando", string(reprocessed.Status))
+
+	_, _, err = uc.ProcessCSVImport(tenantCtx, job, func(int, int, int) {})
+	require.NoError(t, err)
+
+	// Reexecutar o job substitui as áreas em vez de duplicá-las
+	_, _, err = uc.ProcessCSVImport(tenantCtx, job, func(int, int, int) {})
+	require.NoError(t, err)
+
+	found, _ = uc.GetMonitoramento(tenantCtx, mon.ID)
+	assert.Equal(t, "concluido", string(found.Status))
+	assert.Equal(t, 0, found.TotalErros)
+	areas, total, _ := areaRepository.GetByMonitoramentoID(tenantCtx, mon.ID, 10, 0)
+	require.Equal(t, 2, total)
+	assert.Equal(t, "F1", areas[0].CodFazenda)
+
+	_, _, err = uc.ReprocessMonitoramento(tenantCtx, "nao-existe", UploadOptions{})
+	assert.Equal(t, sharedErrors.ErrMonitoramentoNotFound, err)
+}
+
+func TestMonitoringUseCase_ReprocessMonitoramento_WithoutFile(t *testing.T) {
+	uuidGen := mockUUID()
+	monRepo := repository.NewInMemoryRepository()
+	files := storage.NewLocalStorage(t.TempDir())
+	uc := NewMonitoringUseCase(monRepo, areaRepo.NewInMemoryRepository(), areaRepo.NewInMemoryAreaRepository(), repository.NewInMemoryErroRepository(), repository.NewInMemoryProfileRepository(), csv.NewParser(uuidGen), uuidGen, &fakeScheduler{}, files)
+
+	// O upload guarda o arquivo original
+	mon, err := importFile(tenantCtx, uc, strings.NewReader("Id;Setor;Setor2;Cod.Fazenda;Desc.Fazenda;Quadra;Corte\n1;N;S;F1;Fazenda;Q1;1"), "teste.csv", UploadOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "client-a/"+mon.ID+".csv", mon.FileKey)
+
+	_, job, err := uc.ReprocessMonitoramento(tenantCtx, mon.ID, UploadOptions{})
+	require.NoError(t, err)
+	_, _, err = uc.ProcessCSVImport(tenantCtx, job, func(int, int, int) {})
+	require.NoError(t, err)
+
+	// Arquivo removido do storage
+	require.NoError(t, files.Delete(tenantCtx, mon.FileKey))
+	_, _, err = uc.ReprocessMonitoramento(tenantCtx, mon.ID, UploadOptions{})
+	assert.Equal(t, sharedErrors.ErrUploadFileNotFound, err)
+
+	// Monitoramento anterior ao armazenamento dos arquivos
+	old := domain.NewMonitoramento("antigo", "antigo.csv")
+	require.NoError(t, monRepo.Create(tenantCtx, old))
+	require.NoError(t, monRepo.UpdateStatus(tenantCtx, old.ID, domain.StatusConcluido, 0))
+	_, _, err = uc.ReprocessMonitoramento(tenantCtx, old.ID, UploadOptions{})
+	assert.Equal(t, sharedErrors.ErrUploadFileNotFound, err)
+}
+
+// staleMonitoramentoRepo devolve uma leitura antiga, como uma chamada concorrente que leu antes da outra gravar
+type staleMonitoramentoRepo struct {
+	domain.MonitoramentoRepository
+	status domain.MonitoramentoStatus
+}
+
+func (r *staleMonitoramentoRepo) GetByID(ctx context.Context, id string) (*domain.Monitoramento, error) {
+	m, err := r.MonitoramentoRepository.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	stale := *m
+	stale.Status = r.status
+	return &stale, nil
+}
+
+func TestMonitoringUseCase_ReprocessMonitoramento_ConcurrentClaim(t *testing.T) {
+	uuidGen := mockUUID()
+	monRepo := repository.NewInMemoryRepository()
+	scheduler := &fakeScheduler{}
+	stale := &staleMonitoramentoRepo{MonitoramentoRepository: monRepo, status: domain.StatusConcluido}
+	uc := NewMonitoringUseCase(stale, areaRepo.NewInMemoryRepository(), areaRepo.NewInMemoryAreaRepository(), repository.NewInMemoryErroRepository(), repository.NewInMemoryProfileRepository(), csv.NewParser(uuidGen), uuidGen, scheduler, storage.NewLocalStorage(t.TempDir()))
+
+	mon, err := importFile(tenantCtx, uc, strings.NewReader("Id;Setor;Setor2;Cod.Fazenda;Desc.Fazenda;Quadra;Corte\n1;N;S;F1;Fazenda;Q1;1"), "teste.csv", UploadOptions{})
+	require.NoError(t, err)
+
+	// Outra chamada já marcou como processando depois da leitura
+	require.NoError(t, monRepo.UpdateStatus(tenantCtx, mon.ID, domain.StatusProcessando, 0))
+	scheduler.job = nil
+
+	_, _, err = uc.ReprocessMonitoramento(tenantCtx, mon.ID, UploadOptions{})
+	assert.Equal(t, sharedErrors.ErrMonitoramentoProcessing, err)
+	assert.Nil(t, scheduler.job)
+
+	found, _ := monRepo.GetByID(tenantCtx, mon.ID)
+	assert.Equal(t, domain.StatusProcessando, found.Status)
+}
+
+func TestMonitoringUseCase_DeleteMonitoramento(t *testing.T) {
+	areaRepository := areaRepo.NewInMemoryRepository()
+	erroRepository := repository.NewInMemoryErroRepository()
+	uuidGen := mockUUID()
+	files := storage.NewLocalStorage(t.TempDir())
+	uc := NewMonitoringUseCase(repository.NewInMemoryRepository(), areaRepository, areaRepo.NewInMemoryAreaRepository(), erroRepository, repository.NewInMemoryProfileRepository(), csv.NewParser(uuidGen), uuidGen, &fakeScheduler{}, files)
+
+	csvContent := `Id;Setor;Setor2;Cod.Fazenda;Desc.Fazenda;Quadra;Corte;Área Total;Desc. Textura Solo;Corte Atual;Reforma;Mês Colheita;Restrição
+1;N;S;F1;Fazenda;Q1;1;100;Arg;1;2020;Jan;N
+2;N;S;F1;Fazenda;Q2;x;100;Arg;1;2020;Jan;N`
+
+	mon, job, err := uc.UploadCSVAsync(tenantCtx, strings.NewReader(csvContent), "teste.csv", UploadOptions{Validation: "strict"})
+	require.NoError(t, err)
+
+	// Enquanto o worker não termina a remoção é recusada
+	assert.Equal(t, sharedErrors.ErrMonitoramentoProcessing, uc.DeleteMonitoramento(tenantCtx, mon.ID))
+
+	_, _, err = uc.ProcessCSVImport(tenantCtx, job, func(int, int, int) {})
+	require.NoError(t, err)
+
+	otherCtx := sharedContext.WithTenant(context.Background(), "client-b", "user-b")
+	assert.Equal(t, sharedErrors.ErrMonitoramentoNotFound, uc.DeleteMonitoramento(otherCtx, mon.ID))
+
+	require.NoError(t, uc.DeleteMonitoramento(tenantCtx, mon.ID))
+
+	_, err = uc.GetMonitoramento(tenantCtx, mon.ID)
+	assert.Equal(t, sharedErrors.ErrMonitoramentoNotFound, err)
+	_, total, _ := areaRepository.GetByMonitoramentoID(tenantCtx, mon.ID, 10, 0)
+	assert.Equal(t, 0, total)
+	erros, _ := erroRepository.ListByMonitoramento(tenantCtx, mon.ID)
+	assert.Empty(t, erros)
+	keys, _ := files.List(tenantCtx, "client-a/")
+	assert.Empty(t, keys)
+
+	assert.Equal(t, sharedErrors.ErrMonitoramentoNotFound, uc.DeleteMonitoramento(tenantCtx, mon.ID))
+}
+
+func TestMonitoringUseCase_OpenUploadFile(t *testing.T) {
+	uuidGen := mockUUID()
+	files := storage.NewLocalStorage(t.TempDir())
+	uc := NewMonitoringUseCase(repository.NewInMemoryRepository(), areaRepo.NewInMemoryRepository(), areaRepo.NewInMemoryAreaRepository(), repository.NewInMemoryErroRepository(), repository.NewInMemoryProfileRepository(), csv.NewParser(uuidGen), uuidGen, &fakeScheduler{}, files)
+
+	csvContent := "Id;Setor;Setor2;Cod.Fazenda;Desc.Fazenda;Quadra;Corte\n1;N;S;F1;Fazenda;Q1;1"
+	mon, err := importFile(tenantCtx, uc, strings.NewReader(csvContent), "teste.csv", UploadOptions{})
+	require.NoError(t, err)
+
+	found, file, err := uc.OpenUploadFile(tenantCtx, mon.ID)
+	require.NoError(t, err)
+	data, _ := io.ReadAll(file)
+	file.Close()
+	assert.Equal(t, csvContent, string(data))
+	assert.Equal(t, "teste.csv", found.NomeArquivo)
+
+	otherCtx := sharedContext.WithTenant(context.Background(), "client-b", "user-b")
+	_, _, err = uc.OpenUploadFile(otherCtx, mon.ID)
+	assert.Equal(t, sharedErrors.ErrMonitoramentoNotFound, err)
+
+	require.NoError(t, files.Delete(tenantCtx, mon.FileKey))
+	_, _, err = uc.OpenUploadFile(tenantCtx, mon.ID)
+	assert.Equal(t, sharedErrors.ErrUploadFileNotFound, err)
+}
+
+func TestMonitoringUseCase_ExportCSV(t *testing.T) {
+	areaRepository := areaRepo.NewInMemoryRepository()
+	uuidGen := mockUUID()
+	scheduler := &fakeScheduler{}
+	uc := NewMonitoringUseCase(repository.NewInMemoryRepository(), areaRepository, areaRepo.NewInMemoryAreaRepository(), repository.NewInMemoryErroRepository(), repository.NewInMemoryProfileRepository(), csv.NewParser(uuidGen), uuidGen, scheduler, storage.NewLocalStorage(t.TempDir()))
+
+	// Arquivo no layout de um perfil: a exportação usa o layout padrão
+	_, err := uc.CreateImportProfile(tenantCtx, dto.ImportProfileRequest{
+		Nome:        "usina",
+		Colunas:     map[string]string{"Codigo": "Id", "Fazenda": "Cod.Fazenda", "Talhao": "Quadra", "Sub": "Setor2", "Area": "Área Total"},
+		Pragas:      map[string]string{"Capim": "Capim Colchão", "Mato": "Vassoura"},
+		Vocabulario: map[string]string{"Alto": "A", "Sim": "X"},
+	})
+	require.NoError(t, err)
+
+	csvContent := "Codigo,Fazenda,Talhao,Setor,Sub,Area,Capim,Mato\n1,F1,Q1,Norte,N1,\"12,5\",Alto,\n2,F1,Q2,Norte,N1,8,,Sim\n"
+	mon, job, err := uc.UploadCSVAsync(tenantCtx, strings.NewReader(csvContent), "usina.csv", UploadOptions{Profile: "usina"})
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	assert.Equal(t, sharedErrors.ErrMonitoramentoProcessing, uc.ExportCSV(tenantCtx, mon.ID, &buf))
+
+	_, _, err = uc.ProcessCSVImport(tenantCtx, job, func(int, int, int) {})
+	require.NoError(t, err)
+
+	// Aplicação registrada pela API depois da importação
+	areas, _, err := areaRepository.GetByMonitoramentoID(tenantCtx, mon.ID, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, areas, 2)
+	require.NoError(t, areas[0].PragasData.AddAplicacao("Capim Colchão", 1, "Glifosato", 2.5))
+	require.NoError(t, areaRepository.UpdatePragasData(tenantCtx, areas[0].ID, areas[0].PragasData))
+
+	require.NoError(t, uc.ExportCSV(tenantCtx, mon.ID, &buf))
+	assert.Contains(t, buf.String(), "Restrição;Capim Colchão;Vassoura;Herb 1 Capim Colchão;Dose 1 Capim Colchão\n")
+
+	// O CSV exportado é aceito pelo upload sem perfil e reproduz as áreas
+	exported, err := importFile(tenantCtx, uc, &buf, "export.csv", UploadOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 2, exported.TotalLinhas)
+	assert.Equal(t, 0, exported.TotalErros)
+
+	reimported, _, err := areaRepository.GetByMonitoramentoID(tenantCtx, exported.ID, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, reimported, 2)
+	for i, area := range areas {
+		assert.Equal(t, area.ExternalKey, reimported[i].ExternalKey)
+		assert.Equal(t, area.CodFazenda, reimported[i].CodFazenda)
+		assert.Equal(t, area.Quadra, reimported[i].Quadra)
+		assert.Equal(t, area.Setor2, reimported[i].Setor2)
+		assert.Equal(t, area.AreaTotal, reimported[i].AreaTotal)
+		assert.Equal(t, area.PragasData, reimported[i].PragasData)
+	}
+
+	assert.Equal(t, sharedErrors.ErrMonitoramentoNotFound, uc.ExportCSV(tenantCtx, "nao-existe", &buf))
+}
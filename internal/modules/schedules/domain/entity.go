@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// Tarefas agendáveis registradas no bootstrap
+const (
+	TaskSnapshotClientStats  = "snapshot_client_stats"
+	TaskPurgeDeletedClients  = "purge_deleted_clients"
+	TaskCleanupJobs          = "cleanup_jobs"
+	TaskGenerateStatsReports = "generate_stats_reports"
+)
+
+// Schedule agendamento recorrente de uma tarefa da plataforma, executado pelo worker
+type Schedule struct {
+	ID   string
+	Name string
+	// Task nome da tarefa registrada no usecase
+	Task string
+	// Cron expressão de 5 campos, avaliada em UTC
+	Cron string
+	// Payload parâmetros da tarefa
+	Payload   json.RawMessage
+	Enabled   bool
+	NextRunAt time.Time
+	LastRunAt *time.Time
+	// LastError erro da última execução (vazio = sucesso)
+	LastError string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// NewSchedule cria um novo agendamento habilitado
+func NewSchedule(id, name string) *Schedule {
+	now := time.Now()
+	return &Schedule{
+		ID:        id,
+		Name:      strings.TrimSpace(name),
+		Payload:   json.RawMessage(`{}`),
+		Enabled:   true,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
@@ -0,0 +1,22 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// ScheduleRepository interface de persistência dos agendamentos (sem tenant)
+type ScheduleRepository interface {
+	Create(ctx context.Context, s *Schedule) error
+	GetByID(ctx context.Context, id string) (*Schedule, error)
+	List(ctx context.Context) ([]*Schedule, error)
+	Update(ctx context.Context, s *Schedule) error
+	Delete(ctx context.Context, id string) error
+	// ListDue lista os agendamentos habilitados com next_run_at <= now
+	ListDue(ctx context.Context, now time.Time) ([]*Schedule, error)
+	// Claim avança next_run_at de previous para next só se ele ainda for previous;
+	// com vários workers, apenas um consegue o claim de cada execução
+	Claim(ctx context.Context, id string, previous, next time.Time) (bool, error)
+	// RecordRun grava o horário e o erro (vazio = sucesso) da última execução
+	RecordRun(ctx context.Context, id string, ranAt time.Time, runErr string) error
+}
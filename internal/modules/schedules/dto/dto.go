@@ -0,0 +1,60 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"agro-monitoring/internal/modules/schedules/domain"
+)
+
+// ScheduleRequest request para criar/substituir um agendamento
+type ScheduleRequest struct {
+	Name string `json:"name"`
+	Task string `json:"task"`
+	// Cron expressão de 5 campos em UTC (ex.: "0 3 * * *") ou macro (@daily, @hourly...)
+	Cron    string          `json:"cron"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+	// Enabled padrão true
+	Enabled *bool `json:"enabled,omitempty"`
+}
+
+// ScheduleResponse resposta de agendamento
+type ScheduleResponse struct {
+	ID        string          `json:"id"`
+	Name      string          `json:"name"`
+	Task      string          `json:"task"`
+	Cron      string          `json:"cron"`
+	Payload   json.RawMessage `json:"payload"`
+	Enabled   bool            `json:"enabled"`
+	NextRunAt time.Time       `json:"next_run_at"`
+	LastRunAt *time.Time      `json:"last_run_at,omitempty"`
+	LastError string          `json:"last_error,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// ToScheduleResponse converte domain para DTO
+func ToScheduleResponse(s *domain.Schedule) ScheduleResponse {
+	return ScheduleResponse{
+		ID:        s.ID,
+		Name:      s.Name,
+		Task:      s.Task,
+		Cron:      s.Cron,
+		Payload:   s.Payload,
+		Enabled:   s.Enabled,
+		NextRunAt: s.NextRunAt,
+		LastRunAt: s.LastRunAt,
+		LastError: s.LastError,
+		CreatedAt: s.CreatedAt,
+		UpdatedAt: s.UpdatedAt,
+	}
+}
+
+// ToScheduleListResponse converte lista para DTO
+func ToScheduleListResponse(items []*domain.Schedule) []ScheduleResponse {
+	data := make([]ScheduleResponse, len(items))
+	for i, s := range items {
+		data[i] = ToScheduleResponse(s)
+	}
+	return data
+}
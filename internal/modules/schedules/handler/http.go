@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"agro-monitoring/internal/modules/schedules/dto"
+	"agro-monitoring/internal/modules/schedules/usecase"
+	sharedErrors "agro-monitoring/internal/shared/errors"
+	"agro-monitoring/internal/shared/response"
+)
+
+// Handler handler para agendamentos
+type Handler struct {
+	uc usecase.ScheduleUseCase
+}
+
+// NewHandler cria novo handler
+func NewHandler(uc usecase.ScheduleUseCase) *Handler {
+	return &Handler{uc: uc}
+}
+
+// RegisterAdminRoutes registra as rotas admin de agendamentos (/v1/admin/schedules)
+func (h *Handler) RegisterAdminRoutes(r chi.Router) {
+	r.Route("/schedules", func(r chi.Router) {
+		r.Get("/", h.List)
+		r.Post("/", h.Create)
+		r.Get("/{id}", h.Get)
+		r.Put("/{id}", h.Update)
+		r.Delete("/{id}", h.Delete)
+		r.Post("/{id}/run", h.Run)
+	})
+}
+
+// List lista os agendamentos
+func (h *Handler) List(w http.ResponseWriter, r *http.Request) {
+	items, err := h.uc.ListSchedules(r.Context())
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Erro ao listar agendamentos")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, dto.ToScheduleListResponse(items))
+}
+
+// Get retorna um agendamento
+func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
+	s, err := h.uc.GetSchedule(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		respondScheduleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, dto.ToScheduleResponse(s))
+}
+
+// Create cria um agendamento
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	var req dto.ScheduleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "JSON inválido")
+		return
+	}
+
+	s, err := h.uc.CreateSchedule(r.Context(), req)
+	if err != nil {
+		respondScheduleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, dto.ToScheduleResponse(s))
+}
+
+// Update substitui um agendamento
+func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
+	var req dto.ScheduleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "JSON inválido")
+		return
+	}
+
+	s, err := h.uc.UpdateSchedule(r.Context(), chi.URLParam(r, "id"), req)
+	if err != nil {
+		respondScheduleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, dto.ToScheduleResponse(s))
+}
+
+// Delete remove um agendamento
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	if err := h.uc.DeleteSchedule(r.Context(), chi.URLParam(r, "id")); err != nil {
+		respondScheduleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Run antecipa a execução do agendamento para o próximo ciclo do worker
+func (h *Handler) Run(w http.ResponseWriter, r *http.Request) {
+	s, err := h.uc.TriggerSchedule(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		respondScheduleError(w, err)
+		return
+	}
+
+	respondJSON(w, http.StatusAccepted, dto.ToScheduleResponse(s))
+}
+
+func respondScheduleError(w http.ResponseWriter, err error) {
+	switch {
+	case err == sharedErrors.ErrScheduleNotFound:
+		respondError(w, http.StatusNotFound, "Agendamento não encontrado")
+	case err == sharedErrors.ErrDuplicateSchedule:
+		respondError(w, http.StatusConflict, err.Error())
+	case errors.Is(err, sharedErrors.ErrInvalidSchedule):
+		// A mensagem indica o campo inválido
+		respondError(w, http.StatusBadRequest, err.Error())
+	default:
+		respondError(w, http.StatusInternalServerError, "Erro interno")
+	}
+}
+
+func respondJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
+
+func respondError(w http.ResponseWriter, status int, message string) {
+	respondJSON(w, status, response.ErrorResponse{Message: message})
+}
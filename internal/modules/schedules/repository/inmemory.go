@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"agro-monitoring/internal/modules/schedules/domain"
+	sharedErrors "agro-monitoring/internal/shared/errors"
+)
+
+// InMemoryRepository implementação em memória dos agendamentos para testes
+type InMemoryRepository struct {
+	mu    sync.RWMutex
+	items map[string]*domain.Schedule
+}
+
+// NewInMemoryRepository cria um novo repository de agendamentos em memória
+func NewInMemoryRepository() *InMemoryRepository {
+	return &InMemoryRepository{
+		items: make(map[string]*domain.Schedule),
+	}
+}
+
+func (r *InMemoryRepository) Create(ctx context.Context, s *domain.Schedule) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.nameTaken(s.Name, "") {
+		return sharedErrors.ErrDuplicateSchedule
+	}
+
+	stored := *s
+	r.items[s.ID] = &stored
+	return nil
+}
+
+func (r *InMemoryRepository) GetByID(ctx context.Context, id string) (*domain.Schedule, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	s, ok := r.items[id]
+	if !ok {
+		return nil, sharedErrors.ErrScheduleNotFound
+	}
+	result := *s
+	return &result, nil
+}
+
+func (r *InMemoryRepository) List(ctx context.Context) ([]*domain.Schedule, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]*domain.Schedule, 0, len(r.items))
+	for _, s := range r.items {
+		item := *s
+		result = append(result, &item)
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, nil
+}
+
+func (r *InMemoryRepository) Update(ctx context.Context, s *domain.Schedule) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	existing, ok := r.items[s.ID]
+	if !ok {
+		return sharedErrors.ErrScheduleNotFound
+	}
+	if r.nameTaken(s.Name, s.ID) {
+		return sharedErrors.ErrDuplicateSchedule
+	}
+
+	stored := *s
+	// Resultado da execução não é alterado pela edição
+	stored.LastRunAt = existing.LastRunAt
+	stored.LastError = existing.LastError
+	r.items[s.ID] = &stored
+	return nil
+}
+
+func (r *InMemoryRepository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.items[id]; !ok {
+		return sharedErrors.ErrScheduleNotFound
+	}
+	delete(r.items, id)
+	return nil
+}
+
+func (r *InMemoryRepository) ListDue(ctx context.Context, now time.Time) ([]*domain.Schedule, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]*domain.Schedule, 0)
+	for _, s := range r.items {
+		if s.Enabled && !s.NextRunAt.After(now) {
+			item := *s
+			result = append(result, &item)
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].NextRunAt.Before(result[j].NextRunAt) })
+	return result, nil
+}
+
+func (r *InMemoryRepository) Claim(ctx context.Context, id string, previous, next time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s, ok := r.items[id]
+	if !ok || !s.Enabled || !s.NextRunAt.Equal(previous) {
+		return false, nil
+	}
+	s.NextRunAt = next
+	return true, nil
+}
+
+func (r *InMemoryRepository) RecordRun(ctx context.Context, id string, ranAt time.Time, runErr string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if s, ok := r.items[id]; ok {
+		s.LastRunAt = &ranAt
+		s.LastError = runErr
+	}
+	return nil
+}
+
+func (r *InMemoryRepository) nameTaken(name, exceptID string) bool {
+	for _, s := range r.items {
+		if s.Name == name && s.ID != exceptID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"agro-monitoring/internal/modules/schedules/domain"
+	"agro-monitoring/internal/shared/database"
+	sharedErrors "agro-monitoring/internal/shared/errors"
+)
+
+const selectScheduleColumns = `
+		SELECT id, name, task, cron, payload, enabled, next_run_at, last_run_at, COALESCE(last_error, ''), created_at, updated_at
+		FROM schedules`
+
+// PostgresRepository implementação PostgreSQL dos agendamentos.
+// A tabela não tem RLS: os agendamentos são da plataforma, não de um tenant
+type PostgresRepository struct {
+	db *sql.DB
+}
+
+// NewPostgresRepository cria um novo repository de agendamentos PostgreSQL
+func NewPostgresRepository(tenantDB *database.TenantDB) domain.ScheduleRepository {
+	return &PostgresRepository{db: tenantDB.DB()}
+}
+
+func (r *PostgresRepository) Create(ctx context.Context, s *domain.Schedule) error {
+	query := `
+		INSERT INTO schedules (id, name, task, cron, payload, enabled, next_run_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (name) DO NOTHING
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		s.ID, s.Name, s.Task, s.Cron, []byte(s.Payload), s.Enabled, s.NextRunAt, s.CreatedAt, s.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sharedErrors.ErrDuplicateSchedule
+	}
+	return nil
+}
+
+func (r *PostgresRepository) GetByID(ctx context.Context, id string) (*domain.Schedule, error) {
+	query := selectScheduleColumns + `
+		WHERE id = $1
+	`
+
+	s, err := scanSchedule(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, sharedErrors.ErrScheduleNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (r *PostgresRepository) List(ctx context.Context) ([]*domain.Schedule, error) {
+	return r.query(ctx, selectScheduleColumns+`
+		ORDER BY name
+	`)
+}
+
+func (r *PostgresRepository) Update(ctx context.Context, s *domain.Schedule) error {
+	query := `
+		UPDATE schedules
+		SET name = $1, task = $2, cron = $3, payload = $4, enabled = $5, next_run_at = $6, updated_at = $7
+		WHERE id = $8
+		AND NOT EXISTS (SELECT 1 FROM schedules o WHERE o.name = $1 AND o.id <> $8)
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		s.Name, s.Task, s.Cron, []byte(s.Payload), s.Enabled, s.NextRunAt, s.UpdatedAt, s.ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		var exists bool
+		if err := r.db.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM schedules WHERE id = $1)`, s.ID).Scan(&exists); err != nil {
+			return err
+		}
+		if exists {
+			return sharedErrors.ErrDuplicateSchedule
+		}
+		return sharedErrors.ErrScheduleNotFound
+	}
+	return nil
+}
+
+func (r *PostgresRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM schedules WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sharedErrors.ErrScheduleNotFound
+	}
+	return nil
+}
+
+func (r *PostgresRepository) ListDue(ctx context.Context, now time.Time) ([]*domain.Schedule, error) {
+	return r.query(ctx, selectScheduleColumns+`
+		WHERE enabled AND next_run_at <= $1
+		ORDER BY next_run_at
+	`, now)
+}
+
+func (r *PostgresRepository) Claim(ctx context.Context, id string, previous, next time.Time) (bool, error) {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE schedules SET next_run_at = $3 WHERE id = $1 AND next_run_at = $2 AND enabled`,
+		id, previous, next,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows == 1, nil
+}
+
+func (r *PostgresRepository) RecordRun(ctx context.Context, id string, ranAt time.Time, runErr string) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE schedules SET last_run_at = $2, last_error = NULLIF($3, '') WHERE id = $1`,
+		id, ranAt, runErr,
+	)
+	return err
+}
+
+func (r *PostgresRepository) query(ctx context.Context, query string, args ...interface{}) ([]*domain.Schedule, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make([]*domain.Schedule, 0)
+	for rows.Next() {
+		s, err := scanSchedule(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, s)
+	}
+	return result, rows.Err()
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanSchedule(row rowScanner) (*domain.Schedule, error) {
+	s := &domain.Schedule{}
+	var payload []byte
+	err := row.Scan(
+		&s.ID,
+		&s.Name,
+		&s.Task,
+		&s.Cron,
+		&payload,
+		&s.Enabled,
+		&s.NextRunAt,
+		&s.LastRunAt,
+		&s.LastError,
+		&s.CreatedAt,
+		&s.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	s.Payload = json.RawMessage(payload)
+	return s, nil
+}
@@ -0,0 +1,26 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+
+	"agro-monitoring/internal/modules/schedules/domain"
+	"agro-monitoring/internal/modules/schedules/dto"
+)
+
+// ScheduleUseCase define a interface para os casos de uso de agendamentos
+type ScheduleUseCase interface {
+	CreateSchedule(ctx context.Context, req dto.ScheduleRequest) (*domain.Schedule, error)
+	GetSchedule(ctx context.Context, id string) (*domain.Schedule, error)
+	ListSchedules(ctx context.Context) ([]*domain.Schedule, error)
+	UpdateSchedule(ctx context.Context, id string, req dto.ScheduleRequest) (*domain.Schedule, error)
+	DeleteSchedule(ctx context.Context, id string) error
+	// TriggerSchedule antecipa a próxima execução para o próximo ciclo do scheduler
+	TriggerSchedule(ctx context.Context, id string) (*domain.Schedule, error)
+	RegisterTask(name string, task Task)
+	// RunScheduler executa os agendamentos vencidos até o context ser cancelado (chamado pelo cmd/worker)
+	RunScheduler(ctx context.Context)
+}
+
+// Task tarefa executada por um agendamento; recebe o payload do agendamento
+type Task func(ctx context.Context, payload json.RawMessage) error
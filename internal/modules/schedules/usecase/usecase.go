@@ -0,0 +1,230 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"agro-monitoring/internal/modules/schedules/domain"
+	"agro-monitoring/internal/modules/schedules/dto"
+	"agro-monitoring/internal/services/cron"
+	sharedErrors "agro-monitoring/internal/shared/errors"
+)
+
+const (
+	// schedulerInterval intervalo entre as verificações de agendamentos vencidos
+	schedulerInterval     = 30 * time.Second
+	maxScheduleNameLength = 100
+)
+
+type scheduleUseCase struct {
+	repo    domain.ScheduleRepository
+	uuidGen func() string
+	tasks   map[string]Task
+	// now relógio em UTC (substituído nos testes)
+	now func() time.Time
+}
+
+// NewScheduleUseCase cria um novo usecase de agendamentos
+func NewScheduleUseCase(repo domain.ScheduleRepository, uuidGen func() string) ScheduleUseCase {
+	return &scheduleUseCase{
+		repo:    repo,
+		uuidGen: uuidGen,
+		tasks:   make(map[string]Task),
+		now:     func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// RegisterTask associa o nome usado nos agendamentos à tarefa
+func (uc *scheduleUseCase) RegisterTask(name string, task Task) {
+	uc.tasks[name] = task
+}
+
+func (uc *scheduleUseCase) CreateSchedule(ctx context.Context, req dto.ScheduleRequest) (*domain.Schedule, error) {
+	s := domain.NewSchedule(uc.uuidGen(), req.Name)
+	if err := uc.applyRequest(s, req); err != nil {
+		return nil, err
+	}
+
+	if err := uc.repo.Create(ctx, s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (uc *scheduleUseCase) GetSchedule(ctx context.Context, id string) (*domain.Schedule, error) {
+	return uc.repo.GetByID(ctx, id)
+}
+
+func (uc *scheduleUseCase) ListSchedules(ctx context.Context) ([]*domain.Schedule, error) {
+	return uc.repo.List(ctx)
+}
+
+// UpdateSchedule substitui o agendamento; a próxima execução é recalculada a partir de agora
+func (uc *scheduleUseCase) UpdateSchedule(ctx context.Context, id string, req dto.ScheduleRequest) (*domain.Schedule, error) {
+	existing, err := uc.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	s := *existing
+	s.Name = strings.TrimSpace(req.Name)
+	if err := uc.applyRequest(&s, req); err != nil {
+		return nil, err
+	}
+	s.UpdatedAt = time.Now()
+
+	if err := uc.repo.Update(ctx, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (uc *scheduleUseCase) DeleteSchedule(ctx context.Context, id string) error {
+	return uc.repo.Delete(ctx, id)
+}
+
+func (uc *scheduleUseCase) TriggerSchedule(ctx context.Context, id string) (*domain.Schedule, error) {
+	s, err := uc.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !s.Enabled {
+		return nil, fmt.Errorf("%w: agendamento desabilitado", sharedErrors.ErrInvalidSchedule)
+	}
+
+	s.NextRunAt = uc.now()
+	s.UpdatedAt = time.Now()
+	if err := uc.repo.Update(ctx, s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// applyRequest valida o request e calcula a próxima execução; o erro detalha o campo inválido
+func (uc *scheduleUseCase) applyRequest(s *domain.Schedule, req dto.ScheduleRequest) error {
+	s.Task = strings.TrimSpace(req.Task)
+	s.Cron = strings.TrimSpace(req.Cron)
+	s.Enabled = req.Enabled == nil || *req.Enabled
+
+	s.Payload = json.RawMessage(`{}`)
+	if p := bytes.TrimSpace(req.Payload); len(p) > 0 && string(p) != "null" {
+		s.Payload = p
+	}
+
+	if s.Name == "" {
+		return fmt.Errorf("%w: name obrigatório", sharedErrors.ErrInvalidSchedule)
+	}
+	if len(s.Name) > maxScheduleNameLength {
+		return fmt.Errorf("%w: name deve ter no máximo %d caracteres", sharedErrors.ErrInvalidSchedule, maxScheduleNameLength)
+	}
+	if _, ok := uc.tasks[s.Task]; !ok {
+		return fmt.Errorf("%w: task deve ser uma de: %s", sharedErrors.ErrInvalidSchedule, strings.Join(uc.taskNames(), ", "))
+	}
+	var payload map[string]interface{}
+	if err := json.Unmarshal(s.Payload, &payload); err != nil {
+		return fmt.Errorf("%w: payload deve ser um objeto JSON", sharedErrors.ErrInvalidSchedule)
+	}
+
+	sched, err := cron.Parse(s.Cron)
+	if err != nil {
+		return fmt.Errorf("%w: %v", sharedErrors.ErrInvalidSchedule, err)
+	}
+	s.NextRunAt = sched.Next(uc.now())
+	return nil
+}
+
+func (uc *scheduleUseCase) taskNames() []string {
+	names := make([]string, 0, len(uc.tasks))
+	for name := range uc.tasks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RunScheduler verifica os agendamentos vencidos a cada schedulerInterval
+func (uc *scheduleUseCase) RunScheduler(ctx context.Context) {
+	log.Println("Scheduler iniciado")
+
+	ticker := time.NewTicker(schedulerInterval)
+	defer ticker.Stop()
+
+	for {
+		uc.runDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			log.Println("Scheduler encerrado")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runDue executa os agendamentos vencidos que este worker conseguir reservar.
+// Execuções perdidas (worker parado) não se acumulam: a próxima é calculada a partir de agora
+func (uc *scheduleUseCase) runDue(ctx context.Context) {
+	now := uc.now()
+	due, err := uc.repo.ListDue(ctx, now)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("Erro ao buscar agendamentos vencidos: %v", err)
+		}
+		return
+	}
+
+	for _, s := range due {
+		if ctx.Err() != nil {
+			return
+		}
+
+		sched, err := cron.Parse(s.Cron)
+		if err != nil {
+			log.Printf("Agendamento %s com cron inválido %q: %v", s.Name, s.Cron, err)
+			continue
+		}
+
+		claimed, err := uc.repo.Claim(ctx, s.ID, s.NextRunAt, sched.Next(now))
+		if err != nil {
+			log.Printf("Erro ao reservar agendamento %s: %v", s.Name, err)
+			continue
+		}
+		if !claimed {
+			continue // outro worker já executou
+		}
+
+		uc.execute(ctx, s)
+	}
+}
+
+// execute roda a tarefa do agendamento e grava o resultado
+func (uc *scheduleUseCase) execute(ctx context.Context, s *domain.Schedule) {
+	startedAt := uc.now()
+	log.Printf("Executando agendamento %s (tarefa %s)", s.Name, s.Task)
+
+	var runErr error
+	task, ok := uc.tasks[s.Task]
+	if !ok {
+		runErr = fmt.Errorf("tarefa desconhecida: %s", s.Task)
+	} else {
+		runErr = task(ctx, s.Payload)
+	}
+
+	var errMsg string
+	if runErr != nil {
+		errMsg = runErr.Error()
+		log.Printf("Agendamento %s falhou: %v", s.Name, runErr)
+	} else {
+		log.Printf("Agendamento %s concluído em %s", s.Name, uc.now().Sub(startedAt).Round(time.Millisecond))
+	}
+
+	if err := uc.repo.RecordRun(ctx, s.ID, startedAt, errMsg); err != nil {
+		log.Printf("Erro ao gravar execução do agendamento %s: %v", s.Name, err)
+	}
+}
@@ -0,0 +1,159 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"agro-monitoring/internal/modules/schedules/dto"
+	"agro-monitoring/internal/modules/schedules/repository"
+	sharedErrors "agro-monitoring/internal/shared/errors"
+)
+
+func mockUUID() func() string {
+	counter := 0
+	return func() string {
+		counter++
+		return fmt.Sprintf("uuid-%d", counter)
+	}
+}
+
+// setupScheduleTest cria o usecase com relógio controlado e uma tarefa "noop" que registra os payloads
+func setupScheduleTest() (*scheduleUseCase, *repository.InMemoryRepository, *time.Time, *[]json.RawMessage) {
+	repo := repository.NewInMemoryRepository()
+	uc := NewScheduleUseCase(repo, mockUUID()).(*scheduleUseCase)
+
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	uc.now = func() time.Time { return now }
+
+	var runs []json.RawMessage
+	uc.RegisterTask("noop", func(ctx context.Context, payload json.RawMessage) error {
+		runs = append(runs, payload)
+		return nil
+	})
+	return uc, repo, &now, &runs
+}
+
+func TestScheduleUseCase_CreateValidates(t *testing.T) {
+	uc, _, _, _ := setupScheduleTest()
+	ctx := context.Background()
+
+	s, err := uc.CreateSchedule(ctx, dto.ScheduleRequest{Name: " nightly ", Task: "noop", Cron: "0 3 * * *"})
+	require.NoError(t, err)
+	assert.Equal(t, "nightly", s.Name)
+	assert.True(t, s.Enabled)
+	assert.JSONEq(t, `{}`, string(s.Payload))
+	assert.Equal(t, time.Date(2026, 3, 11, 3, 0, 0, 0, time.UTC), s.NextRunAt)
+
+	_, err = uc.CreateSchedule(ctx, dto.ScheduleRequest{Name: "nightly", Task: "noop", Cron: "@hourly"})
+	assert.Equal(t, sharedErrors.ErrDuplicateSchedule, err)
+
+	invalid := []dto.ScheduleRequest{
+		{Name: "", Task: "noop", Cron: "@daily"},
+		{Name: "x", Task: "desconhecida", Cron: "@daily"},
+		{Name: "x", Task: "noop", Cron: "0 25 * * *"},
+		{Name: "x", Task: "noop", Cron: "@daily", Payload: json.RawMessage(`[1, 2]`)},
+	}
+	for _, req := range invalid {
+		_, err := uc.CreateSchedule(ctx, req)
+		assert.True(t, errors.Is(err, sharedErrors.ErrInvalidSchedule), "%+v: %v", req, err)
+	}
+}
+
+func TestScheduleUseCase_RunDue(t *testing.T) {
+	uc, repo, now, runs := setupScheduleTest()
+	ctx := context.Background()
+
+	s, err := uc.CreateSchedule(ctx, dto.ScheduleRequest{Name: "a cada hora", Task: "noop", Cron: "@hourly", Payload: json.RawMessage(`{"dias": 30}`)})
+	require.NoError(t, err)
+	disabled := false
+	_, err = uc.CreateSchedule(ctx, dto.ScheduleRequest{Name: "desabilitado", Task: "noop", Cron: "* * * * *", Enabled: &disabled})
+	require.NoError(t, err)
+
+	// Ainda não venceu
+	uc.runDue(ctx)
+	assert.Empty(t, *runs)
+
+	// Worker parado por 3 horas: executa uma vez e agenda a partir de agora
+	*now = now.Add(3*time.Hour + 30*time.Minute)
+	uc.runDue(ctx)
+	require.Len(t, *runs, 1)
+	assert.JSONEq(t, `{"dias": 30}`, string((*runs)[0]))
+
+	stored, err := repo.GetByID(ctx, s.ID)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 3, 10, 16, 0, 0, 0, time.UTC), stored.NextRunAt)
+	require.NotNil(t, stored.LastRunAt)
+	assert.Equal(t, *now, *stored.LastRunAt)
+	assert.Empty(t, stored.LastError)
+
+	uc.runDue(ctx)
+	assert.Len(t, *runs, 1)
+}
+
+func TestScheduleUseCase_RunDue_ClaimedOnce(t *testing.T) {
+	uc, repo, now, runs := setupScheduleTest()
+	ctx := context.Background()
+
+	s, err := uc.CreateSchedule(ctx, dto.ScheduleRequest{Name: "diario", Task: "noop", Cron: "@daily"})
+	require.NoError(t, err)
+
+	// Outro worker reservou a execução entre a listagem e o claim
+	*now = s.NextRunAt
+	claimed, err := repo.Claim(ctx, s.ID, s.NextRunAt, s.NextRunAt.Add(24*time.Hour))
+	require.NoError(t, err)
+	require.True(t, claimed)
+
+	uc.runDue(ctx)
+	assert.Empty(t, *runs)
+}
+
+func TestScheduleUseCase_RecordsTaskError(t *testing.T) {
+	uc, repo, now, _ := setupScheduleTest()
+	ctx := context.Background()
+
+	uc.RegisterTask("falha", func(ctx context.Context, payload json.RawMessage) error {
+		return errors.New("banco indisponível")
+	})
+	s, err := uc.CreateSchedule(ctx, dto.ScheduleRequest{Name: "falha", Task: "falha", Cron: "@daily"})
+	require.NoError(t, err)
+
+	_, err = uc.TriggerSchedule(ctx, s.ID)
+	require.NoError(t, err)
+	uc.runDue(ctx)
+
+	stored, err := repo.GetByID(ctx, s.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "banco indisponível", stored.LastError)
+	assert.Equal(t, time.Date(2026, 3, 11, 0, 0, 0, 0, time.UTC), stored.NextRunAt)
+
+	// Editar não apaga o resultado da última execução
+	*now = now.Add(time.Minute)
+	updated, err := uc.UpdateSchedule(ctx, s.ID, dto.ScheduleRequest{Name: "falha", Task: "falha", Cron: "@hourly"})
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 3, 10, 13, 0, 0, 0, time.UTC), updated.NextRunAt)
+
+	stored, _ = repo.GetByID(ctx, s.ID)
+	assert.Equal(t, "banco indisponível", stored.LastError)
+}
+
+func TestScheduleUseCase_TriggerDisabled(t *testing.T) {
+	uc, _, _, _ := setupScheduleTest()
+	ctx := context.Background()
+
+	disabled := false
+	s, err := uc.CreateSchedule(ctx, dto.ScheduleRequest{Name: "x", Task: "noop", Cron: "@daily", Enabled: &disabled})
+	require.NoError(t, err)
+
+	_, err = uc.TriggerSchedule(ctx, s.ID)
+	assert.True(t, errors.Is(err, sharedErrors.ErrInvalidSchedule))
+
+	_, err = uc.TriggerSchedule(ctx, "inexistente")
+	assert.Equal(t, sharedErrors.ErrScheduleNotFound, err)
+}
@@ -0,0 +1,64 @@
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func date(s string) time.Time {
+	t, err := time.Parse("2006-01-02 15:04", s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestSchedule_Next(t *testing.T) {
+	tests := []struct {
+		expr  string
+		after string
+		want  string
+	}{
+		{"* * * * *", "2026-03-10 12:00", "2026-03-10 12:01"},
+		{"0 3 * * *", "2026-03-10 12:00", "2026-03-11 03:00"},
+		{"0 3 * * *", "2026-03-10 02:59", "2026-03-10 03:00"},
+		{"@daily", "2026-12-31 23:59", "2027-01-01 00:00"},
+		{"*/15 * * * *", "2026-03-10 12:16", "2026-03-10 12:30"},
+		{"0 9-17/4 * * *", "2026-03-10 14:00", "2026-03-10 17:00"},
+		{"30 6 1,15 * *", "2026-03-02 00:00", "2026-03-15 06:30"},
+		{"0 0 * * 1", "2026-03-10 12:00", "2026-03-16 00:00"}, // segunda-feira
+		{"0 0 * * 7", "2026-03-10 12:00", "2026-03-15 00:00"}, // domingo como 7
+		{"0 0 29 2 *", "2026-03-01 00:00", "2028-02-29 00:00"},
+		// Dia e dia da semana restritos: basta um deles casar
+		{"0 0 20 * 1", "2026-03-10 12:00", "2026-03-16 00:00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr+" após "+tt.after, func(t *testing.T) {
+			s, err := Parse(tt.expr)
+			require.NoError(t, err)
+			assert.Equal(t, date(tt.want), s.Next(date(tt.after)))
+		})
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+		"0 0 30 2 *",
+	} {
+		_, err := Parse(expr)
+		assert.Error(t, err, expr)
+	}
+}
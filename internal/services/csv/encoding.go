@@ -0,0 +1,166 @@
+package csv
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// Encodings aceitos para arquivos CSV
+const (
+	EncodingUTF8        = "utf-8"
+	EncodingWindows1252 = "windows-1252"
+	EncodingLatin1      = "iso-8859-1"
+)
+
+// encodingAliases nomes alternativos usados por planilhas e ferramentas de exportação
+var encodingAliases = map[string]string{
+	"utf8":       EncodingUTF8,
+	"cp1252":     EncodingWindows1252,
+	"latin1":     EncodingLatin1,
+	"latin-1":    EncodingLatin1,
+	"iso8859-1":  EncodingLatin1,
+	"iso-8859-1": EncodingLatin1,
+}
+
+// CanonicalEncoding normaliza o nome do encoding ("" = detectar); ok=false se não suportado
+func CanonicalEncoding(encoding string) (string, bool) {
+	encoding = strings.ToLower(strings.TrimSpace(encoding))
+	if alias, ok := encodingAliases[encoding]; ok {
+		encoding = alias
+	}
+	switch encoding {
+	case "", EncodingUTF8, EncodingWindows1252, EncodingLatin1:
+		return encoding, true
+	}
+	return "", false
+}
+
+// IsValidEncoding verifica se o encoding é suportado (vazio = detectar)
+func IsValidEncoding(encoding string) bool {
+	_, ok := CanonicalEncoding(encoding)
+	return ok
+}
+
+// windows1252High caracteres de 0x80 a 0x9F que diferem do ISO-8859-1.
+// Posições não definidas no Windows-1252 mantêm o controle C1 correspondente.
+var windows1252High = [32]rune{
+	'€', '\u0081', '‚', 'ƒ', '„', '…', '†', '‡',
+	'ˆ', '‰', 'Š', '‹', 'Œ', '\u008D', 'Ž', '\u008F',
+	'\u0090', '‘', '’', '“', '”', '•', '–', '—',
+	'˜', '™', 'š', '›', 'œ', '\u009D', 'ž', 'Ÿ',
+}
+
+// decodeReader converte o conteúdo para UTF-8 conforme o encoding informado
+func decodeReader(r io.Reader, encoding string) io.Reader {
+	encoding, _ = CanonicalEncoding(encoding)
+	switch encoding {
+	case EncodingWindows1252:
+		return &singleByteReader{r: r, buf: make([]byte, 4096), windows1252: true}
+	case EncodingLatin1:
+		return &singleByteReader{r: r, buf: make([]byte, 4096)}
+	}
+	return r
+}
+
+// singleByteReader decodifica encodings de um byte por caractere (ISO-8859-1 e Windows-1252)
+type singleByteReader struct {
+	r           io.Reader
+	buf         []byte
+	pending     []byte
+	err         error
+	windows1252 bool
+}
+
+func (s *singleByteReader) Read(p []byte) (int, error) {
+	for len(s.pending) == 0 {
+		if s.err != nil {
+			return 0, s.err
+		}
+		n, err := s.r.Read(s.buf)
+		for _, b := range s.buf[:n] {
+			s.pending = utf8.AppendRune(s.pending, s.decode(b))
+		}
+		s.err = err
+	}
+
+	n := copy(p, s.pending)
+	s.pending = s.pending[n:]
+	return n, nil
+}
+
+func (s *singleByteReader) decode(b byte) rune {
+	if s.windows1252 && b >= 0x80 && b <= 0x9F {
+		return windows1252High[b-0x80]
+	}
+	return rune(b)
+}
+
+// detectReader repassa o conteúdo enquanto ele for ASCII (igual nos dois encodings).
+// No primeiro byte não ASCII decide: se os próximos peekSize bytes forem UTF-8 válido
+// o arquivo é UTF-8, senão é tratado como Windows-1252 (superconjunto imprimível do Latin-1).
+type detectReader struct {
+	br       *bufio.Reader
+	decoded  io.Reader
+	encoding string
+}
+
+func newDetectReader(br *bufio.Reader) *detectReader {
+	return &detectReader{br: br}
+}
+
+// Encoding encoding detectado; vazio enquanto só houve ASCII (compatível com UTF-8)
+func (d *detectReader) Encoding() string {
+	return d.encoding
+}
+
+func (d *detectReader) Read(p []byte) (int, error) {
+	if d.decoded != nil {
+		return d.decoded.Read(p)
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	if _, err := d.br.Peek(1); err != nil {
+		return 0, err
+	}
+	chunk, _ := d.br.Peek(d.br.Buffered())
+
+	n := 0
+	for n < len(chunk) && n < len(p) && chunk[n] < utf8.RuneSelf {
+		n++
+	}
+	if n > 0 {
+		copy(p, chunk[:n])
+		d.br.Discard(n)
+		return n, nil
+	}
+
+	window, err := d.br.Peek(peekSize)
+	if isUTF8(window, err == io.EOF) {
+		d.encoding = EncodingUTF8
+		d.decoded = d.br
+	} else {
+		d.encoding = EncodingWindows1252
+		d.decoded = decodeReader(d.br, EncodingWindows1252)
+	}
+	return d.decoded.Read(p)
+}
+
+// isUTF8 valida a janela; se ela não chega ao fim do arquivo (complete=false),
+// um caractere cortado no final é ignorado
+func isUTF8(window []byte, complete bool) bool {
+	if !complete {
+		for i := len(window) - 1; i >= 0 && i >= len(window)-utf8.UTFMax; i-- {
+			if utf8.RuneStart(window[i]) {
+				if !utf8.FullRune(window[i:]) {
+					window = window[:i]
+				}
+				break
+			}
+		}
+	}
+	return utf8.Valid(window)
+}
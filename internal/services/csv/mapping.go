@@ -0,0 +1,179 @@
+package csv
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// ColumnMapping ajusta o parser ao layout de um cliente (perfil de importação)
+type ColumnMapping struct {
+	// Separator separador de colunas; 0 detecta a partir da primeira linha
+	Separator rune
+	// Encoding encoding do arquivo (vazio = detectar)
+	Encoding string
+	// Columns header do arquivo -> campo canônico (ex.: "Fazenda" -> "Cod.Fazenda", "Herbicida" -> "Herb 1")
+	Columns map[string]string
+	// Pragas header do arquivo -> nome da praga; se informado, substitui a detecção
+	// das colunas de praga entre "Restrição" e o primeiro herbicida
+	Pragas map[string]string
+	// Vocabulario valor da célula de praga -> nível (A, B, M, X; vazio = ausente).
+	// Se informado, substitui o vocabulário padrão; célula vazia é sempre ausência.
+	Vocabulario map[string]string
+}
+
+// defaultVocabulario S, SIM, 1, X (presença simples) ou A, B, M (nível: Alta, Baixa, Média)
+var defaultVocabulario = map[string]string{
+	"A": "A", "B": "B", "M": "M",
+	"S": "X", "SIM": "X", "1": "X", "X": "X",
+	"": "", "N": "", "NAO": "", "NÃO": "", "0": "", "-": "",
+}
+
+// CanonicalFields campos fixos aceitos como destino de um mapeamento de colunas
+func CanonicalFields() []string {
+	return append([]string(nil), camposFixos...)
+}
+
+// ParseSeparator converte o separador de um perfil ("", ",", ";", "|", "\t" ou "tab")
+func ParseSeparator(s string) (rune, error) {
+	switch s {
+	case "":
+		return 0, nil
+	case "tab", `\t`:
+		return '\t', nil
+	}
+	r, size := utf8.DecodeRuneInString(s)
+	if size != len(s) || !strings.ContainsRune(",;|\t", r) {
+		return 0, fmt.Errorf("separador inválido %q", s)
+	}
+	return r, nil
+}
+
+// Validate verifica se os destinos do mapeamento são campos, pragas e níveis conhecidos
+func (m *ColumnMapping) Validate() error {
+	if !IsValidEncoding(m.Encoding) {
+		return fmt.Errorf("encoding não suportado %q", m.Encoding)
+	}
+
+	sources := make(map[string]bool)
+	targets := make(map[string]string)
+	for source, target := range m.Columns {
+		key := normalizeHeader(source)
+		if key == "" {
+			return fmt.Errorf("coluna de origem vazia")
+		}
+		if sources[key] {
+			return fmt.Errorf("coluna de origem repetida %q", source)
+		}
+		sources[key] = true
+
+		if !isCanonicalField(target) {
+			return fmt.Errorf("coluna %q: campo de destino desconhecido %q", source, target)
+		}
+		if other, dup := targets[strings.ToLower(target)]; dup {
+			return fmt.Errorf("colunas %q e %q mapeadas para o mesmo campo %q", other, source, target)
+		}
+		targets[strings.ToLower(target)] = source
+	}
+
+	pragas := make(map[string]bool)
+	for source, praga := range m.Pragas {
+		key := normalizeHeader(source)
+		if key == "" {
+			return fmt.Errorf("coluna de origem vazia")
+		}
+		if sources[key] {
+			return fmt.Errorf("coluna de origem repetida %q", source)
+		}
+		sources[key] = true
+
+		praga = strings.TrimSpace(praga)
+		if praga == "" {
+			return fmt.Errorf("coluna %q: nome da praga vazio", source)
+		}
+		if pragas[strings.ToLower(praga)] {
+			return fmt.Errorf("praga %q mapeada mais de uma vez", praga)
+		}
+		pragas[strings.ToLower(praga)] = true
+	}
+
+	for valor, nivel := range m.Vocabulario {
+		switch strings.ToUpper(strings.TrimSpace(nivel)) {
+		case "", "A", "B", "M", "X":
+		default:
+			return fmt.Errorf("valor %q: nível inválido %q (use A, B, M, X ou vazio)", valor, nivel)
+		}
+	}
+
+	return nil
+}
+
+// isCanonicalField aceita os campos fixos e as colunas de aplicação ("Herb N [Praga]", "Dose N [Praga]")
+func isCanonicalField(name string) bool {
+	for _, campo := range camposFixos {
+		if strings.EqualFold(name, campo) {
+			return true
+		}
+	}
+	return aplicacaoHeaderPattern.MatchString(strings.TrimSpace(name))
+}
+
+// canonicalName retorna a grafia oficial do campo fixo, ou o nome informado (colunas de aplicação)
+func canonicalName(name string) string {
+	name = strings.TrimSpace(name)
+	for _, campo := range camposFixos {
+		if strings.EqualFold(name, campo) {
+			return campo
+		}
+	}
+	return name
+}
+
+func normalizeHeader(s string) string {
+	return strings.ToLower(strings.TrimSpace(s))
+}
+
+// apply renomeia o header para os campos canônicos e retorna as colunas de praga do perfil
+// (nil quando o perfil não define pragas e a detecção padrão deve ser usada)
+func (m *ColumnMapping) apply(header []string) []string {
+	columns := make(map[string]string, len(m.Columns))
+	for source, target := range m.Columns {
+		columns[normalizeHeader(source)] = canonicalName(target)
+	}
+	pragas := make(map[string]string, len(m.Pragas))
+	for source, praga := range m.Pragas {
+		pragas[normalizeHeader(source)] = strings.TrimSpace(praga)
+	}
+
+	var pragaColumns []string
+	if len(pragas) > 0 {
+		pragaColumns = make([]string, 0, len(pragas))
+	}
+
+	for i, col := range header {
+		key := normalizeHeader(col)
+		if target, ok := columns[key]; ok {
+			header[i] = target
+			continue
+		}
+		if praga, ok := pragas[key]; ok {
+			header[i] = praga
+			pragaColumns = append(pragaColumns, praga)
+		}
+	}
+
+	return pragaColumns
+}
+
+// vocabulario retorna o vocabulário de presença do perfil com as chaves normalizadas
+func (m *ColumnMapping) vocabulario() map[string]string {
+	if m == nil || len(m.Vocabulario) == 0 {
+		return defaultVocabulario
+	}
+
+	vocab := map[string]string{"": ""}
+	for valor, nivel := range m.Vocabulario {
+		vocab[strings.ToUpper(strings.TrimSpace(valor))] = strings.ToUpper(strings.TrimSpace(nivel))
+	}
+	return vocab
+}
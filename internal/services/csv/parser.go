@@ -1,10 +1,14 @@
 package csv
 
 import (
+	"bufio"
+	"bytes"
 	"encoding/csv"
+	"errors"
 	"fmt"
 	"io"
-	"log"
+	"math"
+	"regexp"
 	"strconv"
 	"strings"
 
@@ -41,94 +45,332 @@ func NewParser(uuidGenerator func() string) *Parser {
 	}
 }
 
+// ValidationMode define como valores inválidos são tratados
+type ValidationMode string
+
+const (
+	// ValidationLenient converte valores numéricos inválidos em 0 e ignora códigos de praga desconhecidos
+	ValidationLenient ValidationMode = "lenient"
+	// ValidationStrict rejeita a linha e registra um ParseError com a coluna e o valor original
+	ValidationStrict ValidationMode = "strict"
+)
+
+// IsValid verifica se o modo de validação é válido
+func (m ValidationMode) IsValid() bool {
+	switch m {
+	case ValidationLenient, ValidationStrict:
+		return true
+	}
+	return false
+}
+
+// ParseOptions opções de parsing definidas por upload
+type ParseOptions struct {
+	Validation ValidationMode
+	// UniqueKeys rejeita linhas que repetem a chave externa de uma linha anterior
+	// (e, em modo strict, linhas sem chave); usado quando as linhas são vinculadas a áreas existentes
+	UniqueKeys bool
+	// Mapping perfil de importação do cliente; nil usa o layout padrão
+	Mapping *ColumnMapping
+	// Encoding declarado no upload; tem prioridade sobre o do perfil. Vazio detecta
+	// (BOM UTF-8, UTF-8 válido ou Windows-1252)
+	Encoding string
+}
+
 // ParseResult contém o resultado do parsing
 type ParseResult struct {
 	Areas       []*domain.AreaMonitoramento
+	Header      []string
+	TotalLinhas int
+	Errors      []ParseError
+}
+
+// StreamResult contém o resumo de um parsing em streaming (as áreas vão para o callback)
+type StreamResult struct {
+	Header      []string
 	TotalLinhas int
 	Errors      []ParseError
+	// Encoding encoding usado na leitura (declarado ou detectado; vazio em arquivos só ASCII)
+	Encoding string
+	// Separator separador usado na leitura (detectado ou do perfil; zero em planilhas)
+	Separator rune
+	// Colunas campos fixos encontrados no header, com o nome da coluna no arquivo
+	Colunas map[string]string
+	// Pragas colunas lidas como pragas
+	Pragas []string
 }
 
 // ParseError representa um erro em uma linha específica
 type ParseError struct {
 	Linha int
 	Erro  string
+	// Registro valores originais da linha (pode estar incompleto em erros de leitura)
+	Registro []string
 }
 
-// Parse processa o CSV e retorna as áreas de monitoramento
+// BatchFunc recebe cada lote de áreas parseadas; retornar erro interrompe o parsing
+type BatchFunc func(areas []*domain.AreaMonitoramento) error
+
+// peekSize tamanho do buffer usado para detectar BOM e separador na primeira linha
+const peekSize = 64 * 1024
+
+// Parse processa o CSV e retorna todas as áreas de monitoramento em memória.
+// Para arquivos grandes use ParseStream.
 func (p *Parser) Parse(reader io.Reader, monitoramentoID string) (*ParseResult, error) {
-	// Lê todo o conteúdo para detectar o separador
-	content, err := io.ReadAll(reader)
+	result := &ParseResult{
+		Areas: make([]*domain.AreaMonitoramento, 0),
+	}
+
+	stream, err := p.ParseStream(reader, monitoramentoID, ParseOptions{}, 500, func(areas []*domain.AreaMonitoramento) error {
+		result.Areas = append(result.Areas, areas...)
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("%w: erro ao ler arquivo: %v", sharedErrors.ErrInvalidCSV, err)
+		return nil, err
+	}
+
+	result.Header = stream.Header
+	result.TotalLinhas = stream.TotalLinhas
+	result.Errors = stream.Errors
+	return result, nil
+}
+
+// ParseStream lê o CSV linha a linha e entrega as áreas em lotes de até batchSize,
+// mantendo em memória apenas o lote corrente. Se nenhuma linha for válida retorna
+// ErrInvalidCSV junto com o resultado, para que os erros possam ser registrados.
+func (p *Parser) ParseStream(reader io.Reader, monitoramentoID string, opts ParseOptions, batchSize int, onBatch BatchFunc) (*StreamResult, error) {
+	if batchSize < 1 {
+		batchSize = 1
+	}
+
+	encoding := opts.Encoding
+	if encoding == "" && opts.Mapping != nil {
+		encoding = opts.Mapping.Encoding
+	}
+	encoding, ok := CanonicalEncoding(encoding)
+	if !ok {
+		return nil, fmt.Errorf("%w: encoding não suportado %q", sharedErrors.ErrInvalidCSV, opts.Encoding)
+	}
+
+	raw := bufio.NewReaderSize(reader, peekSize)
+
+	// Remove BOM (Byte Order Mark) se existir; o BOM identifica o arquivo como UTF-8
+	if bom, _ := raw.Peek(3); bytes.Equal(bom, []byte("\xef\xbb\xbf")) {
+		raw.Discard(3)
+		if encoding == "" {
+			encoding = EncodingUTF8
+		}
 	}
 
-	// Remove BOM (Byte Order Mark) se existir
-	contentStr := strings.TrimPrefix(string(content), "\ufeff")
+	// Converte para UTF-8 antes do parsing, para que os nomes dos campos ("Área Total",
+	// "Restrição") e das pragas cheguem corretos
+	var decoded io.Reader = decodeReader(raw, encoding)
+	var detector *detectReader
+	if encoding == "" {
+		detector = newDetectReader(raw)
+		decoded = detector
+	}
+	buffered := bufio.NewReaderSize(decoded, peekSize)
 
-	// Detecta o separador (TAB, ; ou ,)
-	separator := p.detectSeparator(contentStr)
+	// Detecta o separador (TAB, ; ou ,) a partir da primeira linha, sem consumir o reader
+	firstLine, err := buffered.Peek(peekSize)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, fmt.Errorf("%w: erro ao ler arquivo: %v", sharedErrors.ErrInvalidCSV, err)
+	}
+	separator := p.detectSeparator(string(firstLine))
+	if opts.Mapping != nil && opts.Mapping.Separator != 0 {
+		separator = opts.Mapping.Separator
+	}
 
-	csvReader := csv.NewReader(strings.NewReader(contentStr))
+	csvReader := csv.NewReader(buffered)
 	csvReader.Comma = separator
 	csvReader.LazyQuotes = true
 	csvReader.TrimLeadingSpace = true
+	csvReader.ReuseRecord = true
+
+	result, err := p.ParseRecords(csvReader, monitoramentoID, opts, batchSize, onBatch)
+	if result != nil {
+		result.Separator = separator
+		result.Encoding = encoding
+		if detector != nil {
+			result.Encoding = detector.Encoding()
+		}
+	}
+	return result, err
+}
+
+// RecordReader fonte de linhas do arquivo (CSV ou planilha); a primeira linha é o header.
+// Erros *csv.ParseError rejeitam só a linha; os demais interrompem o parsing.
+type RecordReader interface {
+	Read() ([]string, error)
+}
 
-	header, err := csvReader.Read()
+// lineReader fontes que informam o número real da linha (ex.: planilhas com linhas vazias omitidas)
+type lineReader interface {
+	Line() int
+}
+
+// numericReader fontes que informam as células numéricas da última linha lida (ex.: planilhas).
+// Essas células já usam ponto decimal e não passam pelas regras do formato brasileiro
+type numericReader interface {
+	IsNumeric(col int) bool
+}
+
+// ParseRecords aplica o mesmo mapeamento e validação do CSV a qualquer fonte de linhas.
+// Separador e encoding do perfil não se aplicam aqui.
+func (p *Parser) ParseRecords(records RecordReader, monitoramentoID string, opts ParseOptions, batchSize int, onBatch BatchFunc) (*StreamResult, error) {
+	if batchSize < 1 {
+		batchSize = 1
+	}
+
+	header, err := records.Read()
 	if err != nil {
 		return nil, fmt.Errorf("%w: erro ao ler header: %v", sharedErrors.ErrInvalidCSV, err)
 	}
+	// ReuseRecord: o header precisa ser copiado antes da próxima leitura
+	header = append([]string(nil), header...)
 
-	colIndex, pragaColumns, err := p.mapColumns(header)
+	// O resultado mantém o header original, para o CSV de erros ser reenviado com o mesmo perfil
+	layout, err := p.mapColumns(append([]string(nil), header...), opts.Mapping)
 	if err != nil {
 		return nil, err
 	}
 
-	result := &ParseResult{
-		Areas:  make([]*domain.AreaMonitoramento, 0),
-		Errors: make([]ParseError, 0),
+	result := &StreamResult{
+		Header:  header,
+		Colunas: layout.fixedColumns(header),
+		Pragas:  layout.pragaColumns,
+		Errors:  make([]ParseError, 0),
+	}
+	batch := make([]*domain.AreaMonitoramento, 0, batchSize)
+
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := onBatch(batch); err != nil {
+			return err
+		}
+		batch = make([]*domain.AreaMonitoramento, 0, batchSize)
+		return nil
 	}
 
+	seenKeys := make(map[string]int)
+
+	lines, hasLines := records.(lineReader)
+	numeric, _ := records.(numericReader)
 	linha := 1
 	for {
-		record, err := csvReader.Read()
+		record, err := records.Read()
 		if err == io.EOF {
 			break
 		}
 		linha++
+		if hasLines {
+			linha = lines.Line()
+		}
 
+		var lineErr *csv.ParseError
+		if err != nil && !errors.As(err, &lineErr) {
+			return nil, err
+		}
 		if err != nil {
 			result.Errors = append(result.Errors, ParseError{
-				Linha: linha,
-				Erro:  fmt.Sprintf("erro ao ler linha: %v", err),
+				Linha:    linha,
+				Erro:     fmt.Sprintf("erro ao ler linha: %v", err),
+				Registro: append([]string(nil), record...),
 			})
 			continue
 		}
 
-		area, err := p.parseRecord(record, colIndex, pragaColumns, monitoramentoID)
+		area, err := p.parseRecord(record, numeric, layout, monitoramentoID, opts.Validation == ValidationStrict)
 		if err != nil {
 			result.Errors = append(result.Errors, ParseError{
-				Linha: linha,
-				Erro:  err.Error(),
+				Linha:    linha,
+				Erro:     err.Error(),
+				Registro: append([]string(nil), record...),
 			})
 			continue
 		}
 
-		result.Areas = append(result.Areas, area)
+		if opts.UniqueKeys {
+			if keyErr := checkExternalKey(area.ExternalKey, linha, seenKeys, opts.Validation == ValidationStrict); keyErr != "" {
+				result.Errors = append(result.Errors, ParseError{
+					Linha:    linha,
+					Erro:     keyErr,
+					Registro: append([]string(nil), record...),
+				})
+				continue
+			}
+		}
+
+		result.TotalLinhas++
+		batch = append(batch, area)
+		if len(batch) == batchSize {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		}
 	}
 
-	result.TotalLinhas = len(result.Areas)
+	if err := flush(); err != nil {
+		return nil, err
+	}
 
 	if result.TotalLinhas == 0 && len(result.Errors) > 0 {
-		return nil, sharedErrors.ErrInvalidCSV
+		return result, sharedErrors.ErrInvalidCSV
 	}
 
 	return result, nil
 }
 
-func (p *Parser) mapColumns(header []string) (map[string]int, []string, error) {
+// aplicacaoColumn par de colunas "Herb N [Praga]" / "Dose N [Praga]" de uma aplicação planejada
+type aplicacaoColumn struct {
+	Posicao   int
+	Praga     string // vazio: aplicação na área, sem praga específica
+	HerbName  string
+	HerbIndex int
+	DoseName  string
+	DoseIndex int // -1 quando não há coluna de dose
+}
+
+// aplicacaoHeaderPattern reconhece "Herb 1", "Herb 1 Camalote", "Dose 2 Capim Colchão"
+var aplicacaoHeaderPattern = regexp.MustCompile(`(?i)^(herb|dose)\s+(\d+)(?:\s+(.+))?$`)
+
+// checkExternalKey registra a chave da linha e retorna a mensagem de erro se ela já apareceu no arquivo
+func checkExternalKey(key string, linha int, seen map[string]int, strict bool) string {
+	if key == "" {
+		if strict {
+			return "linha sem chave externa (Id ou Cod.Fazenda e Quadra)"
+		}
+		return ""
+	}
+	if first, ok := seen[key]; ok {
+		return fmt.Sprintf("chave externa %q repetida (linha %d)", key, first)
+	}
+	seen[key] = linha
+	return ""
+}
+
+// recordLayout posição das colunas do arquivo e vocabulário de presença das pragas
+type recordLayout struct {
+	colIndex         map[string]int
+	pragaColumns     []string
+	aplicacaoColumns []aplicacaoColumn
+	vocabulario      map[string]string
+}
+
+// mapColumns localiza os campos fixos, pragas e aplicações no header. Com um perfil,
+// o header é antes renomeado para os campos canônicos e as pragas vêm do perfil.
+func (p *Parser) mapColumns(header []string, mapping *ColumnMapping) (*recordLayout, error) {
 	colIndex := make(map[string]int)
 	pragaColumns := make([]string, 0)
 
+	var profilePragas []string
+	if mapping != nil {
+		profilePragas = mapping.apply(header)
+	}
+
 	restricaoIndex := -1
 	herbIndex := -1
 
@@ -142,8 +384,9 @@ func (p *Parser) mapColumns(header []string) (map[string]int, []string, error) {
 		if strings.Contains(colLower, "restri") {
 			restricaoIndex = i
 		}
-		// Encontra onde começam os herbicidas (fim das pragas)
-		if herbIndex == -1 && strings.HasPrefix(col, "Herb ") {
+		// Encontra onde começam os herbicidas (fim das pragas). Arquivos antigos
+		// têm colunas "Herb <praga>" sem posição, que só encerram as pragas
+		if herbIndex == -1 && (strings.HasPrefix(col, "Herb ") || aplicacaoHeaderPattern.MatchString(col)) {
 			herbIndex = i
 		}
 	}
@@ -158,21 +401,19 @@ func (p *Parser) mapColumns(header []string) (map[string]int, []string, error) {
 				}
 			}
 			if !found {
-				return nil, nil, fmt.Errorf("%w: campo obrigatório não encontrado: %s", sharedErrors.ErrInvalidCSV, campo)
+				return nil, fmt.Errorf("%w: campo obrigatório não encontrado: %s", sharedErrors.ErrInvalidCSV, campo)
 			}
 		}
 	}
 
-	log.Printf("[DEBUG] restricaoIndex=%d, herbIndex=%d, totalColunas=%d", restricaoIndex, herbIndex, len(header))
-
-	if restricaoIndex >= 0 {
+	if profilePragas != nil {
+		pragaColumns = profilePragas
+	} else if restricaoIndex >= 0 {
 		endIndex := len(header)
 		if herbIndex > restricaoIndex {
 			endIndex = herbIndex
 		}
 
-		log.Printf("[DEBUG] Buscando pragas de %d até %d", restricaoIndex+1, endIndex)
-
 		for i := restricaoIndex + 1; i < endIndex; i++ {
 			colName := header[i]
 			// Ignora colunas vazias e colunas "ColunaX"
@@ -183,23 +424,126 @@ func (p *Parser) mapColumns(header []string) (map[string]int, []string, error) {
 		}
 	}
 
-	log.Printf("[DEBUG] Pragas encontradas: %v", pragaColumns)
+	aplicacaoColumns, err := mapAplicacaoColumns(header, herbIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	// Usa o nome exato da coluna da praga ("Herb 1 camalote" -> "Camalote")
+	for i, col := range aplicacaoColumns {
+		for _, praga := range pragaColumns {
+			if strings.EqualFold(col.Praga, praga) {
+				aplicacaoColumns[i].Praga = praga
+				break
+			}
+		}
+	}
+
+	return &recordLayout{
+		colIndex:         colIndex,
+		pragaColumns:     pragaColumns,
+		aplicacaoColumns: aplicacaoColumns,
+		vocabulario:      mapping.vocabulario(),
+	}, nil
+}
+
+// fixedColumns campos fixos presentes no header (já renomeado pelo perfil),
+// com o nome original da coluna no arquivo
+func (l *recordLayout) fixedColumns(original []string) map[string]string {
+	colunas := make(map[string]string)
+	for _, campo := range camposFixos {
+		if idx, ok := l.colIndex[campo]; ok {
+			colunas[campo] = strings.TrimSpace(original[idx])
+		}
+	}
+	return colunas
+}
+
+// mapAplicacaoColumns agrupa as colunas de herbicida e dose a partir de herbIndex.
+// Convenção: "Herb N <Praga>" com o nome do herbicida e "Dose N <Praga>" com a dose,
+// onde N é a posição da aplicação; sem <Praga> a aplicação vale para a área.
+func mapAplicacaoColumns(header []string, herbIndex int) ([]aplicacaoColumn, error) {
+	columns := make([]aplicacaoColumn, 0)
+	if herbIndex < 0 {
+		return columns, nil
+	}
+
+	byKey := make(map[string]int)
+	doses := make(map[string]int)
+	doseNames := make(map[string]string)
+
+	for i := herbIndex; i < len(header); i++ {
+		match := aplicacaoHeaderPattern.FindStringSubmatch(header[i])
+		if match == nil {
+			continue
+		}
+
+		posicao, _ := strconv.Atoi(match[2])
+		praga := strings.TrimSpace(match[3])
+		key := strings.ToLower(match[2] + "|" + praga)
+
+		if strings.EqualFold(match[1], "dose") {
+			doses[key] = i
+			doseNames[key] = header[i]
+			continue
+		}
+
+		if _, dup := byKey[key]; dup {
+			return nil, fmt.Errorf("%w: coluna de herbicida duplicada: %s", sharedErrors.ErrInvalidCSV, header[i])
+		}
+		byKey[key] = len(columns)
+		columns = append(columns, aplicacaoColumn{
+			Posicao:   posicao,
+			Praga:     praga,
+			HerbName:  header[i],
+			HerbIndex: i,
+			DoseIndex: -1,
+		})
+	}
+
+	for key, idx := range doses {
+		pos, ok := byKey[key]
+		if !ok {
+			return nil, fmt.Errorf("%w: coluna de dose sem herbicida correspondente: %s", sharedErrors.ErrInvalidCSV, doseNames[key])
+		}
+		columns[pos].DoseIndex = idx
+		columns[pos].DoseName = doseNames[key]
+	}
 
-	return colIndex, pragaColumns, nil
+	return columns, nil
 }
 
-func (p *Parser) parseRecord(record []string, colIndex map[string]int, pragaColumns []string, monitoramentoID string) (*domain.AreaMonitoramento, error) {
+// parseRecord monta a área da linha. Em modo strict, valores numéricos inválidos e
+// códigos de praga desconhecidos rejeitam a linha com todas as inconsistências encontradas.
+func (p *Parser) parseRecord(record []string, numeric numericReader, layout *recordLayout, monitoramentoID string, strict bool) (*domain.AreaMonitoramento, error) {
+	colIndex := layout.colIndex
 	area := domain.NewAreaMonitoramento(p.uuidGenerator(), monitoramentoID)
+	invalid := make([]string, 0)
+
+	intField := func(colName string) int {
+		val, err := p.getInt(record, numeric, colIndex, colName)
+		if err != nil {
+			invalid = append(invalid, err.Error())
+		}
+		return val
+	}
+	floatField := func(colName string) float64 {
+		val, err := p.getFloat(record, numeric, colIndex, colName)
+		if err != nil {
+			invalid = append(invalid, err.Error())
+		}
+		return val
+	}
 
 	setor := p.getString(record, colIndex, "Setor")
 	setor2 := p.getString(record, colIndex, "Setor2")
 	codFazenda := p.getString(record, colIndex, "Cod.Fazenda")
 	descFazenda := p.getString(record, colIndex, "Desc.Fazenda")
 	quadra := p.getString(record, colIndex, "Quadra")
-	corte := p.getInt(record, colIndex, "Corte")
-	areaTotal := p.getFloat(record, colIndex, "Área Total")
+	corte := intField("Corte")
+	areaTotal := floatField("Área Total")
 	descTexturaSolo := p.getString(record, colIndex, "Desc. Textura Solo")
-	corteAtual := p.getInt(record, colIndex, "Corte Atual")
+	corteAtual := intField("Corte Atual")
 	reforma := p.getString(record, colIndex, "Reforma")
 	mesColheita := p.getString(record, colIndex, "Mês Colheita")
 	restricao := p.getString(record, colIndex, "Restrição")
@@ -209,23 +553,63 @@ func (p *Parser) parseRecord(record []string, colIndex map[string]int, pragaColu
 		corte, areaTotal, descTexturaSolo,
 		corteAtual, reforma, mesColheita, restricao,
 	)
+	area.ExternalKey = domain.ExternalKeyFor(p.getString(record, colIndex, "Id"), codFazenda, quadra)
 
-	for _, pragaName := range pragaColumns {
+	for _, pragaName := range layout.pragaColumns {
 		idx, ok := colIndex[pragaName]
 		if !ok || idx >= len(record) {
 			continue
 		}
 
 		valor := strings.TrimSpace(strings.ToUpper(record[idx]))
-		// Aceita: S, SIM, 1, X (presença simples) ou A, B, M (nível: Alta, Baixa, Média)
-		switch valor {
-		case "A", "B", "M":
-			area.PragasData.AddPragaComNivel(pragaName, valor)
-		case "S", "SIM", "1", "X":
-			area.PragasData.AddPragaComNivel(pragaName, "X")
+		nivel, ok := layout.vocabulario[valor]
+		switch {
+		case !ok:
+			invalid = append(invalid, fmt.Sprintf("coluna '%s': código de praga desconhecido %q", pragaName, strings.TrimSpace(record[idx])))
+		case nivel != "":
+			area.PragasData.AddPragaComNivel(pragaName, nivel)
 		}
 	}
 
+	// Aplicações planejadas: depois das pragas, para associar à praga presente na linha
+	for _, col := range layout.aplicacaoColumns {
+		herbicida := fieldAt(record, col.HerbIndex)
+		doseStr := fieldAt(record, col.DoseIndex)
+		if herbicida == "" {
+			if doseStr != "" {
+				invalid = append(invalid, fmt.Sprintf("coluna '%s': dose %q sem herbicida em '%s'", col.DoseName, doseStr, col.HerbName))
+			}
+			continue
+		}
+
+		var dose float64
+		if doseStr != "" {
+			val, err := parseNumber(doseStr, col.DoseIndex, numeric)
+			if err != nil {
+				invalid = append(invalid, fmt.Sprintf("coluna '%s': número inválido %q", col.DoseName, doseStr))
+			}
+			dose = val
+		}
+
+		area.Aplicacoes = append(area.Aplicacoes, domain.AplicacaoHerbicidaJson{
+			Posicao:   col.Posicao,
+			Praga:     col.Praga,
+			Herbicida: herbicida,
+			Dose:      dose,
+		})
+
+		if col.Praga == "" {
+			continue
+		}
+		if err := area.PragasData.AddAplicacao(col.Praga, col.Posicao, herbicida, dose); err != nil {
+			invalid = append(invalid, fmt.Sprintf("coluna '%s': aplicação para praga ausente na linha", col.HerbName))
+		}
+	}
+
+	if strict && len(invalid) > 0 {
+		return nil, errors.New(strings.Join(invalid, "; "))
+	}
+
 	return area, nil
 }
 
@@ -237,28 +621,124 @@ func (p *Parser) getString(record []string, colIndex map[string]int, colName str
 	return strings.TrimSpace(record[idx])
 }
 
-func (p *Parser) getInt(record []string, colIndex map[string]int, colName string) int {
+// fieldAt retorna o valor da coluna idx, ou vazio se a coluna não existe na linha
+func fieldAt(record []string, idx int) string {
+	if idx < 0 || idx >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[idx])
+}
+
+// getInt retorna 0 para coluna vazia; valores inválidos retornam 0 com erro
+func (p *Parser) getInt(record []string, numeric numericReader, colIndex map[string]int, colName string) (int, error) {
 	str := p.getString(record, colIndex, colName)
 	if str == "" {
-		return 0
+		return 0, nil
+	}
+
+	var val int
+	var err error
+	if numeric != nil && numeric.IsNumeric(colIndex[colName]) {
+		var f float64
+		f, err = strconv.ParseFloat(str, 64)
+		if err == nil && f != math.Trunc(f) {
+			err = strconv.ErrSyntax
+		}
+		val = int(f)
+	} else {
+		val, err = parseInteger(str)
 	}
-	val, _ := strconv.Atoi(str)
-	return val
+	if err != nil {
+		return 0, fmt.Errorf("coluna '%s': número inteiro inválido %q", colName, str)
+	}
+	return val, nil
 }
 
-func (p *Parser) getFloat(record []string, colIndex map[string]int, colName string) float64 {
+// getFloat retorna 0 para coluna vazia; valores inválidos retornam 0 com erro
+func (p *Parser) getFloat(record []string, numeric numericReader, colIndex map[string]int, colName string) (float64, error) {
 	str := p.getString(record, colIndex, colName)
 	if str == "" {
-		return 0
+		return 0, nil
+	}
+	val, err := parseNumber(str, colIndex[colName], numeric)
+	if err != nil {
+		return 0, fmt.Errorf("coluna '%s': número inválido %q", colName, str)
+	}
+	return val, nil
+}
+
+// parseNumber lê a célula da coluna idx: células numéricas da planilha já usam ponto decimal;
+// texto segue as regras de parseDecimal
+func parseNumber(s string, idx int, numeric numericReader) (float64, error) {
+	if numeric != nil && numeric.IsNumeric(idx) {
+		val, err := strconv.ParseFloat(s, 64)
+		if err != nil || math.IsNaN(val) || math.IsInf(val, 0) {
+			return 0, strconv.ErrSyntax
+		}
+		return val, nil
+	}
+	return parseDecimal(s)
+}
+
+var (
+	integerPattern        = regexp.MustCompile(`^[+-]?\d+$`)
+	decimalPattern        = regexp.MustCompile(`^[+-]?\d+(\.\d+)?$`)
+	dotThousandsPattern   = regexp.MustCompile(`^[+-]?\d{1,3}(\.\d{3})+$`)
+	dotGroupsPattern      = regexp.MustCompile(`^[+-]?\d{1,3}(\.\d{3}){2,}$`)
+	commaThousandsPattern = regexp.MustCompile(`^[+-]?\d{1,3}(,\d{3})+$`)
+)
+
+// parseInteger aceita separador de milhar com ponto em mais de um grupo ("1.234.567"), com a
+// mesma regra de parseDecimal: "1.234" é decimal (1,234) e não é inteiro válido
+func parseInteger(s string) (int, error) {
+	if dotGroupsPattern.MatchString(s) {
+		s = strings.ReplaceAll(s, ".", "")
+	}
+	if !integerPattern.MatchString(s) {
+		return 0, strconv.ErrSyntax
+	}
+	return strconv.Atoi(s)
+}
+
+// parseDecimal aceita o formato brasileiro ("1.234,56", "150,5") e o americano ("1,234.56").
+// Sem vírgula, um único ponto é separador decimal ("12.500" = 12,5, "0.125"); só vários
+// grupos de 3 dígitos são lidos como milhar ("1.234.567").
+func parseDecimal(s string) (float64, error) {
+	lastComma := strings.LastIndex(s, ",")
+	lastDot := strings.LastIndex(s, ".")
+
+	switch {
+	case lastComma >= 0 && lastDot >= 0:
+		sep, thousands, pattern := lastComma, ".", dotThousandsPattern
+		if lastDot > lastComma {
+			sep, thousands, pattern = lastDot, ",", commaThousandsPattern
+		}
+		intPart := s[:sep]
+		if !pattern.MatchString(intPart) {
+			return 0, strconv.ErrSyntax
+		}
+		s = strings.ReplaceAll(intPart, thousands, "") + "." + s[sep+1:]
+	case lastComma >= 0:
+		if strings.Count(s, ",") > 1 {
+			return 0, strconv.ErrSyntax
+		}
+		s = strings.Replace(s, ",", ".", 1)
+	case dotGroupsPattern.MatchString(s):
+		s = strings.ReplaceAll(s, ".", "")
+	case strings.Count(s, ".") > 1:
+		return 0, strconv.ErrSyntax
+	}
+
+	// Rejeita formas aceitas pelo ParseFloat que não são números de planilha (NaN, Inf, 1e5)
+	if !decimalPattern.MatchString(s) {
+		return 0, strconv.ErrSyntax
 	}
-	str = strings.Replace(str, ",", ".", 1)
-	val, _ := strconv.ParseFloat(str, 64)
-	return val
+	return strconv.ParseFloat(s, 64)
 }
 
 // detectSeparator detecta o separador usado no CSV (TAB, ; ou ,)
 func (p *Parser) detectSeparator(content string) rune {
-	firstLine := strings.Split(content, "\n")[0]
+	firstLine, _, _ := strings.Cut(content, "\n")
 
 	tabCount := strings.Count(firstLine, "\t")
 	semicolonCount := strings.Count(firstLine, ";")
@@ -1,10 +1,17 @@
 package csv
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
+	"agro-monitoring/internal/modules/area/domain"
+	sharedErrors "agro-monitoring/internal/shared/errors"
+
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -146,3 +153,599 @@ func TestParser_Parse_UUIDGeneration(t *testing.T) {
 	assert.Equal(t, "uuid-1", result.Areas[0].ID)
 	assert.Equal(t, "uuid-2", result.Areas[1].ID)
 }
+
+func TestParser_ParseStream_Batches(t *testing.T) {
+	var sb strings.Builder
+	sb.WriteString("Id;Setor;Setor2;Cod.Fazenda;Desc.Fazenda;Quadra;Corte;Área Total;Desc. Textura Solo;Corte Atual;Reforma;Mês Colheita;Restrição;Camalote\n")
+	for i := 1; i <= 1203; i++ {
+		fmt.Fprintf(&sb, "%d;N;S;F%d;Fazenda;Q;1;100;Arg;1;2020;Jan;N;S\n", i, i)
+	}
+
+	parser := NewParser(mockUUID())
+	batchSizes := make([]int, 0)
+	result, err := parser.ParseStream(strings.NewReader(sb.String()), "mon-123", ParseOptions{}, 500, func(areas []*domain.AreaMonitoramento) error {
+		batchSizes = append(batchSizes, len(areas))
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 1203, result.TotalLinhas)
+	assert.Equal(t, []int{500, 500, 203}, batchSizes)
+}
+
+func TestParser_ParseStream_CallbackError(t *testing.T) {
+	csvContent := `Id;Setor;Setor2;Cod.Fazenda;Desc.Fazenda;Quadra;Corte;Área Total;Desc. Textura Solo;Corte Atual;Reforma;Mês Colheita;Restrição
+1;N;S;F1;Fazenda;Q;1;100;Arg;1;2020;Jan;N
+2;N;S;F2;Fazenda;Q;1;100;Arg;1;2020;Jan;N
+3;N;S;F3;Fazenda;Q;1;100;Arg;1;2020;Jan;N`
+
+	parser := NewParser(mockUUID())
+	calls := 0
+	_, err := parser.ParseStream(strings.NewReader(csvContent), "mon-123", ParseOptions{}, 1, func(areas []*domain.AreaMonitoramento) error {
+		calls++
+		return errors.New("falha ao salvar")
+	})
+
+	assert.EqualError(t, err, "falha ao salvar")
+	assert.Equal(t, 1, calls)
+}
+
+func TestParser_ParseStream_Layout(t *testing.T) {
+	csvContent := "\xef\xbb\xbfId\tSetor\tSetor2\tCod.Fazenda\tDesc.Fazenda\tQuadra\tCorte\tÁrea Total\tDesc. Textura Solo\tCorte Atual\tReforma\tMês Colheita\tRestrição\tCamalote\tColuna1\tVassoura\tHerb 1\n" +
+		"1\tN\tS\tF1\tFazenda\tQ\t1\t100\tArg\t1\t2020\tJan\tN\tS\t-\tN\tBoral\n"
+
+	result, err := NewParser(mockUUID()).ParseStream(strings.NewReader(csvContent), "mon-123", ParseOptions{}, 10, func([]*domain.AreaMonitoramento) error {
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, '\t', result.Separator)
+	assert.Equal(t, EncodingUTF8, result.Encoding)
+	assert.Len(t, result.Colunas, len(camposFixos))
+	assert.Equal(t, "Área Total", result.Colunas["Área Total"])
+	assert.Equal(t, []string{"Camalote", "Vassoura"}, result.Pragas)
+}
+
+func TestParser_Parse_BOMAndTabSeparator(t *testing.T) {
+	csvContent := "\xef\xbb\xbfId\tSetor\tSetor2\tCod.Fazenda\tDesc.Fazenda\tQuadra\tCorte\tÁrea Total\tDesc. Textura Solo\tCorte Atual\tReforma\tMês Colheita\tRestrição\tCamalote\n" +
+		"1\tN\tS\tF1\tFazenda\tQ\t1\t100\tArg\t1\t2020\tJan\tN\tS\n"
+
+	parser := NewParser(mockUUID())
+	result, err := parser.Parse(strings.NewReader(csvContent), "mon-123")
+
+	require.NoError(t, err)
+	require.Len(t, result.Areas, 1)
+	assert.Equal(t, "F1", result.Areas[0].CodFazenda)
+	assert.True(t, result.Areas[0].PragasData.HasPraga("Camalote"))
+}
+
+func TestParser_Parse_ErrorsKeepOriginalRecord(t *testing.T) {
+	csvContent := `Id;Setor;Setor2;Cod.Fazenda;Desc.Fazenda;Quadra;Corte;Área Total;Desc. Textura Solo;Corte Atual;Reforma;Mês Colheita;Restrição
+1;N;S;F1;Fazenda;Q;1;100;Arg;1;2020;Jan;N
+2;N;S;F2;Fazenda
+3;N;S;F3;Fazenda;Q;1;100;Arg;1;2020;Jan;N`
+
+	parser := NewParser(mockUUID())
+	result, err := parser.Parse(strings.NewReader(csvContent), "mon-123")
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.TotalLinhas)
+	assert.Equal(t, "Id", result.Header[0])
+	require.Len(t, result.Errors, 1)
+	assert.Equal(t, 3, result.Errors[0].Linha)
+	assert.Equal(t, []string{"2", "N", "S", "F2", "Fazenda"}, result.Errors[0].Registro)
+}
+
+func TestWriteErrors_CanBeReuploaded(t *testing.T) {
+	header := []string{"Id", "Setor", "Setor2", "Cod.Fazenda", "Desc.Fazenda", "Quadra", "Corte", "Área Total", "Desc. Textura Solo", "Corte Atual", "Reforma", "Mês Colheita", "Restrição", "Camalote"}
+	errs := []ParseError{
+		{Linha: 7, Erro: "erro ao ler linha: wrong number of fields", Registro: []string{"6", "N", "S", "F6", "Fazenda; Norte", "Q", "1", "100", "Arg", "1", "2020", "Jan", "N", "S"}},
+	}
+
+	var sb strings.Builder
+	require.NoError(t, WriteErrors(&sb, header, errs))
+	assert.True(t, strings.HasPrefix(sb.String(), "\xef\xbb\xbfLinha;Erro;Id;Setor"))
+
+	// O arquivo de erros corrigido é aceito pelo parser; Linha e Erro não viram pragas
+	result, err := NewParser(mockUUID()).Parse(strings.NewReader(sb.String()), "mon-123")
+	require.NoError(t, err)
+	require.Len(t, result.Areas, 1)
+	assert.Equal(t, "F6", result.Areas[0].CodFazenda)
+	assert.Equal(t, "Fazenda; Norte", result.Areas[0].DescFazenda)
+	assert.True(t, result.Areas[0].PragasData.HasPraga("Camalote"))
+	assert.False(t, result.Areas[0].PragasData.HasPraga("Erro"))
+}
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected float64
+		valid    bool
+	}{
+		{"150", 150, true},
+		{"150,5", 150.5, true},
+		{"150.5", 150.5, true},
+		{"1.5", 1.5, true},
+		{"1.2345", 1.2345, true},
+		// Sem vírgula, um único ponto é sempre decimal
+		{"0.125", 0.125, true},
+		{"2.375", 2.375, true},
+		{"12.500", 12.5, true},
+		{"-12.500", -12.5, true},
+		{"1.234", 1.234, true},
+		{"1.234,56", 1234.56, true},
+		{"1.234.567,8", 1234567.8, true},
+		{"1.234.567", 1234567, true},
+		{"1,234.56", 1234.56, true},
+		{"-2,5", -2.5, true},
+		{"abc", 0, false},
+		{"12,34,56", 0, false},
+		{"1.23,4", 0, false},
+		{"NaN", 0, false},
+		{"1e5", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			val, err := parseDecimal(tt.input)
+			if tt.valid {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expected, val)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestParseInteger(t *testing.T) {
+	val, err := parseInteger("1.234.567")
+	require.NoError(t, err)
+	assert.Equal(t, 1234567, val)
+
+	// Mesma leitura que parseDecimal para a mesma célula
+	dec, err := parseDecimal("1.234.567")
+	require.NoError(t, err)
+	assert.Equal(t, float64(val), dec)
+
+	// Um único ponto é decimal, não milhar
+	_, err = parseInteger("1.234")
+	assert.Error(t, err)
+	_, err = parseInteger("3,5")
+	assert.Error(t, err)
+	_, err = parseInteger("abc")
+	assert.Error(t, err)
+}
+
+const validationCSV = `Id;Setor;Setor2;Cod.Fazenda;Desc.Fazenda;Quadra;Corte;Área Total;Desc. Textura Solo;Corte Atual;Reforma;Mês Colheita;Restrição;Camalote;Vassoura
+1;N;S;F1;Fazenda;Q;1;1.234,56;Arg;1;2020;Jan;N;S;N
+2;N;S;F2;Fazenda;Q;abc;100;Arg;1;2020;Jan;N;Z;S
+3;N;S;F3;Fazenda;Q;1;100;Arg;1;2020;Jan;N;A;-`
+
+func TestParser_ParseStream_Lenient(t *testing.T) {
+	parser := NewParser(mockUUID())
+	areas := make([]*domain.AreaMonitoramento, 0)
+	result, err := parser.ParseStream(strings.NewReader(validationCSV), "mon-123", ParseOptions{Validation: ValidationLenient}, 10, func(batch []*domain.AreaMonitoramento) error {
+		areas = append(areas, batch...)
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.TotalLinhas)
+	assert.Empty(t, result.Errors)
+	assert.Equal(t, 1234.56, areas[0].AreaTotal)
+	// Valores inválidos viram 0 e códigos desconhecidos são ignorados
+	assert.Equal(t, 0, areas[1].Corte)
+	assert.False(t, areas[1].PragasData.HasPraga("Camalote"))
+	assert.True(t, areas[1].PragasData.HasPraga("Vassoura"))
+}
+
+func TestParser_ParseStream_Strict(t *testing.T) {
+	parser := NewParser(mockUUID())
+	areas := make([]*domain.AreaMonitoramento, 0)
+	result, err := parser.ParseStream(strings.NewReader(validationCSV), "mon-123", ParseOptions{Validation: ValidationStrict}, 10, func(batch []*domain.AreaMonitoramento) error {
+		areas = append(areas, batch...)
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.TotalLinhas)
+	require.Len(t, areas, 2)
+	assert.Equal(t, 1234.56, areas[0].AreaTotal)
+
+	require.Len(t, result.Errors, 1)
+	assert.Equal(t, 3, result.Errors[0].Linha)
+	assert.Contains(t, result.Errors[0].Erro, `coluna 'Corte': número inteiro inválido "abc"`)
+	assert.Contains(t, result.Errors[0].Erro, `coluna 'Camalote': código de praga desconhecido "Z"`)
+	assert.Equal(t, "F2", result.Errors[0].Registro[3])
+}
+
+func TestValidationMode_IsValid(t *testing.T) {
+	assert.True(t, ValidationLenient.IsValid())
+	assert.True(t, ValidationStrict.IsValid())
+	assert.False(t, ValidationMode("").IsValid())
+	assert.False(t, ValidationMode("rigido").IsValid())
+}
+
+const herbicidaCSV = `Id;Setor;Setor2;Cod.Fazenda;Desc.Fazenda;Quadra;Corte;Área Total;Desc. Textura Solo;Corte Atual;Reforma;Mês Colheita;Restrição;Camalote;Vassoura;Herb 1 Camalote;Dose 1 Camalote;Herb 2 camalote;Dose 2 camalote;Herb 1 Vassoura;Dose 1 Vassoura;Herb 1;Dose 1
+1;N;S;F1;Fazenda;Q;1;100;Arg;1;2020;Jan;N;A;N;Glifosato;2,5;Diuron;1.000,5;;;Atrazina;3
+2;N;S;F2;Fazenda;Q;1;100;Arg;1;2020;Jan;N;N;S;;;;;Hexazinona;abc;;
+3;N;S;F3;Fazenda;Q;1;100;Arg;1;2020;Jan;N;N;N;Glifosato;1;;;;;;`
+
+func TestParser_Parse_Herbicidas(t *testing.T) {
+	parser := NewParser(mockUUID())
+	result, err := parser.Parse(strings.NewReader(herbicidaCSV), "mon-123")
+
+	require.NoError(t, err)
+	require.Len(t, result.Areas, 3)
+
+	// Colunas Herb/Dose não são lidas como pragas
+	area1 := result.Areas[0]
+	assert.Len(t, area1.PragasData.Pragas, 1)
+	assert.True(t, area1.PragasData.HasPraga("Camalote"))
+
+	camalote := area1.PragasData.Pragas["Camalote"]
+	require.Len(t, camalote.Aplicacoes, 2)
+	assert.Equal(t, 1, camalote.Aplicacoes[0].Posicao)
+	assert.Equal(t, "Glifosato", camalote.Aplicacoes[0].Herbicida)
+	assert.Equal(t, 2.5, camalote.Aplicacoes[0].Dose)
+	assert.Equal(t, "Diuron", camalote.Aplicacoes[1].Herbicida)
+	assert.Equal(t, 1000.5, camalote.Aplicacoes[1].Dose)
+
+	// Aplicações da área, incluindo a sem praga específica
+	require.Len(t, area1.Aplicacoes, 3)
+	assert.Equal(t, "Camalote", area1.Aplicacoes[1].Praga)
+	assert.Equal(t, "", area1.Aplicacoes[2].Praga)
+	assert.Equal(t, "Atrazina", area1.Aplicacoes[2].Herbicida)
+	assert.Equal(t, 3.0, area1.Aplicacoes[2].Dose)
+
+	// Lenient: dose inválida vira 0 e aplicação para praga ausente fica só na área
+	area2 := result.Areas[1]
+	require.Len(t, area2.PragasData.Pragas["Vassoura"].Aplicacoes, 1)
+	assert.Equal(t, 0.0, area2.PragasData.Pragas["Vassoura"].Aplicacoes[0].Dose)
+	area3 := result.Areas[2]
+	assert.False(t, area3.PragasData.HasPraga("Camalote"))
+	require.Len(t, area3.Aplicacoes, 1)
+	assert.Equal(t, "Camalote", area3.Aplicacoes[0].Praga)
+}
+
+func TestAreaWriter_RoundTrip(t *testing.T) {
+	parser := NewParser(mockUUID())
+	result, err := parser.Parse(strings.NewReader(herbicidaCSV), "mon-123")
+	require.NoError(t, err)
+
+	// Aplicação incluída pela API depois da importação
+	areas := result.Areas
+	require.NoError(t, areas[0].PragasData.AddAplicacao("Camalote", 3, "Imazapir", 1.5))
+
+	layout := NewExportLayout()
+	layout.Add(areas[:2])
+	layout.Add(areas[2:])
+
+	var sb strings.Builder
+	writer, err := NewAreaWriter(&sb, layout)
+	require.NoError(t, err)
+	require.NoError(t, writer.Write(areas))
+	require.NoError(t, writer.Flush())
+
+	header, _, _ := strings.Cut(strings.TrimPrefix(sb.String(), utf8BOM), "\n")
+	assert.Equal(t, "Id;Setor;Setor2;Cod.Fazenda;Desc.Fazenda;Quadra;Corte;Área Total;Desc. Textura Solo;Corte Atual;Reforma;Mês Colheita;Restrição;"+
+		"Camalote;Vassoura;Herb 1;Dose 1;Herb 1 Camalote;Dose 1 Camalote;Herb 2 Camalote;Dose 2 Camalote;Herb 3 Camalote;Dose 3 Camalote;Herb 1 Vassoura;Dose 1 Vassoura", header)
+
+	reparsed, err := NewParser(mockUUID()).Parse(strings.NewReader(sb.String()), "mon-456")
+	require.NoError(t, err)
+	require.Empty(t, reparsed.Errors)
+	require.Len(t, reparsed.Areas, len(areas))
+
+	for i, area := range areas {
+		got := reparsed.Areas[i]
+		assert.Equal(t, area.ExternalKey, got.ExternalKey)
+		assert.Equal(t, area.CodFazenda, got.CodFazenda)
+		assert.Equal(t, area.AreaTotal, got.AreaTotal)
+		assert.Equal(t, area.CorteAtual, got.CorteAtual)
+		assert.Equal(t, area.Restricao, got.Restricao)
+		assert.Equal(t, area.PragasData, got.PragasData)
+	}
+
+	// A aplicação da API também aparece nas aplicações da área reimportada
+	assert.ElementsMatch(t, append(areas[0].Aplicacoes, domain.AplicacaoHerbicidaJson{Posicao: 3, Praga: "Camalote", Herbicida: "Imazapir", Dose: 1.5}), reparsed.Areas[0].Aplicacoes)
+	assert.ElementsMatch(t, areas[2].Aplicacoes, reparsed.Areas[2].Aplicacoes)
+}
+
+func TestParser_ParseStream_HerbicidasStrict(t *testing.T) {
+	parser := NewParser(mockUUID())
+	result, err := parser.ParseStream(strings.NewReader(herbicidaCSV), "mon-123", ParseOptions{Validation: ValidationStrict}, 10, func([]*domain.AreaMonitoramento) error {
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.TotalLinhas)
+	require.Len(t, result.Errors, 2)
+	assert.Contains(t, result.Errors[0].Erro, `coluna 'Dose 1 Vassoura': número inválido "abc"`)
+	assert.Contains(t, result.Errors[1].Erro, `coluna 'Herb 1 Camalote': aplicação para praga ausente na linha`)
+}
+
+func TestParser_Parse_UnnumberedHerbColumns(t *testing.T) {
+	csvContent := `Id;Setor;Setor2;Cod.Fazenda;Desc.Fazenda;Quadra;Corte;Área Total;Desc. Textura Solo;Corte Atual;Reforma;Mês Colheita;Restrição;Camalote;Vassoura;Herb Camalote;Herb Vassoura
+1;N;S;F1;Fazenda;Q;1;100;Arg;1;2020;Jan;N;S;N;Glifosato;`
+
+	for _, mode := range []ValidationMode{ValidationLenient, ValidationStrict} {
+		var areas []*domain.AreaMonitoramento
+		result, err := NewParser(mockUUID()).ParseStream(strings.NewReader(csvContent), "mon-123", ParseOptions{Validation: mode}, 10, func(batch []*domain.AreaMonitoramento) error {
+			areas = append(areas, batch...)
+			return nil
+		})
+
+		require.NoError(t, err, mode)
+		require.Empty(t, result.Errors, mode)
+		assert.Equal(t, []string{"Camalote", "Vassoura"}, result.Pragas, mode)
+		require.Len(t, areas, 1, mode)
+		assert.Len(t, areas[0].PragasData.Pragas, 1, mode)
+		assert.True(t, areas[0].PragasData.HasPraga("Camalote"), mode)
+		assert.Empty(t, areas[0].Aplicacoes, mode)
+	}
+}
+
+func TestParser_Parse_DoseWithoutHerbColumn(t *testing.T) {
+	csvContent := `Id;Setor;Setor2;Cod.Fazenda;Desc.Fazenda;Quadra;Corte;Área Total;Desc. Textura Solo;Corte Atual;Reforma;Mês Colheita;Restrição;Camalote;Dose 1 Camalote
+1;N;S;F1;Fazenda;Q;1;100;Arg;1;2020;Jan;N;S;2`
+
+	_, err := NewParser(mockUUID()).Parse(strings.NewReader(csvContent), "mon-123")
+	assert.ErrorIs(t, err, sharedErrors.ErrInvalidCSV)
+}
+
+const externalKeyCSV = `Id;Setor;Setor2;Cod.Fazenda;Desc.Fazenda;Quadra;Corte;Área Total;Desc. Textura Solo;Corte Atual;Reforma;Mês Colheita;Restrição
+10;N;S;F1;Fazenda;Q1;1;100;Arg;1;2020;Jan;N
+;N;S;F1;Fazenda;Q2;1;100;Arg;1;2020;Jan;N
+10;N;S;F9;Fazenda;Q9;1;100;Arg;1;2020;Jan;N
+;N;S;F1;Fazenda;;1;100;Arg;1;2020;Jan;N`
+
+func TestParser_Parse_ExternalKey(t *testing.T) {
+	result, err := NewParser(mockUUID()).Parse(strings.NewReader(externalKeyCSV), "mon-123")
+
+	require.NoError(t, err)
+	require.Len(t, result.Areas, 4)
+	assert.Equal(t, "10", result.Areas[0].ExternalKey)
+	assert.Equal(t, "F1/Q2", result.Areas[1].ExternalKey)
+	assert.Equal(t, "10", result.Areas[2].ExternalKey)
+	assert.Equal(t, "", result.Areas[3].ExternalKey)
+}
+
+func TestParser_ParseStream_UniqueKeys(t *testing.T) {
+	parse := func(mode ValidationMode) *StreamResult {
+		result, err := NewParser(mockUUID()).ParseStream(strings.NewReader(externalKeyCSV), "mon-123", ParseOptions{Validation: mode, UniqueKeys: true}, 10, func([]*domain.AreaMonitoramento) error {
+			return nil
+		})
+		require.NoError(t, err)
+		return result
+	}
+
+	lenient := parse(ValidationLenient)
+	assert.Equal(t, 3, lenient.TotalLinhas)
+	require.Len(t, lenient.Errors, 1)
+	assert.Equal(t, 4, lenient.Errors[0].Linha)
+	assert.Equal(t, `chave externa "10" repetida (linha 2)`, lenient.Errors[0].Erro)
+
+	// Strict também rejeita a linha sem chave
+	strict := parse(ValidationStrict)
+	assert.Equal(t, 2, strict.TotalLinhas)
+	require.Len(t, strict.Errors, 2)
+	assert.Equal(t, 5, strict.Errors[1].Linha)
+}
+
+// perfilCSV layout próprio de um cliente, em Windows-1252 ("São João – Sede")
+const perfilCSV = "Codigo|Fazenda|Nome Fazenda|Talhao|Setor|Sub Setor|Area (ha)|Capim Colchao|Tiririca|Herbicida Tiririca\n" +
+	"10|F1|S\xe3o Jo\xe3o \x96 Sede|Q1|Norte|N1|150,5|Alto|Sim|Glifosato\n" +
+	"11|F1|S\xe3o Jo\xe3o \x96 Sede|Q2|Norte|N1|80|Nao|Talvez|\n"
+
+var perfilMapping = &ColumnMapping{
+	Separator: '|',
+	Encoding:  EncodingWindows1252,
+	Columns: map[string]string{
+		"Codigo":             "Id",
+		"fazenda":            "cod.fazenda",
+		"Nome Fazenda":       "Desc.Fazenda",
+		"Talhao":             "Quadra",
+		"Sub Setor":          "Setor2",
+		"Area (ha)":          "Área Total",
+		"Herbicida Tiririca": "Herb 1 Tiririca",
+	},
+	Pragas: map[string]string{
+		"Capim Colchao": "Capim Colchão",
+		"Tiririca":      "Tiririca",
+	},
+	Vocabulario: map[string]string{"alto": "A", "sim": "X", "nao": ""},
+}
+
+func TestParser_ParseStream_Mapping(t *testing.T) {
+	var areas []*domain.AreaMonitoramento
+	result, err := NewParser(mockUUID()).ParseStream(strings.NewReader(perfilCSV), "mon-123", ParseOptions{Mapping: perfilMapping}, 10, func(batch []*domain.AreaMonitoramento) error {
+		areas = append(areas, batch...)
+		return nil
+	})
+
+	require.NoError(t, err)
+	require.Len(t, areas, 2)
+	assert.Equal(t, "Codigo", result.Header[0], "header original é mantido para o CSV de erros")
+	assert.Equal(t, '|', result.Separator)
+	assert.Equal(t, map[string]string{
+		"Id":           "Codigo",
+		"Setor":        "Setor",
+		"Setor2":       "Sub Setor",
+		"Cod.Fazenda":  "Fazenda",
+		"Desc.Fazenda": "Nome Fazenda",
+		"Quadra":       "Talhao",
+		"Área Total":   "Area (ha)",
+	}, result.Colunas)
+	assert.ElementsMatch(t, []string{"Capim Colchão", "Tiririca"}, result.Pragas)
+
+	area := areas[0]
+	assert.Equal(t, "10", area.ExternalKey)
+	assert.Equal(t, "F1", area.CodFazenda)
+	assert.Equal(t, "São João – Sede", area.DescFazenda)
+	assert.Equal(t, "Q1", area.Quadra)
+	assert.Equal(t, "N1", area.Setor2)
+	assert.Equal(t, 150.5, area.AreaTotal)
+	assert.Equal(t, "A", area.PragasData.Pragas["Capim Colchão"].Nivel)
+	assert.Equal(t, "X", area.PragasData.Pragas["Tiririca"].Nivel)
+	require.Len(t, area.Aplicacoes, 1)
+	assert.Equal(t, "Tiririca", area.Aplicacoes[0].Praga)
+	assert.Equal(t, "Glifosato", area.Aplicacoes[0].Herbicida)
+
+	// "Talvez" não está no vocabulário do perfil: ignorado no modo lenient
+	assert.Empty(t, areas[1].PragasData.Pragas)
+}
+
+func TestParser_ParseStream_MappingVocabularioStrict(t *testing.T) {
+	result, err := NewParser(mockUUID()).ParseStream(strings.NewReader(perfilCSV), "mon-123", ParseOptions{Validation: ValidationStrict, Mapping: perfilMapping}, 10, func([]*domain.AreaMonitoramento) error {
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.TotalLinhas)
+	require.Len(t, result.Errors, 1)
+	assert.Equal(t, `coluna 'Tiririca': código de praga desconhecido "Talvez"`, result.Errors[0].Erro)
+}
+
+func TestColumnMapping_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		mapping ColumnMapping
+		wantErr string
+	}{
+		{"válido", *perfilMapping, ""},
+		{"encoding", ColumnMapping{Encoding: "utf-16"}, "encoding não suportado"},
+		{"campo desconhecido", ColumnMapping{Columns: map[string]string{"X": "Talhão"}}, "campo de destino desconhecido"},
+		{"campo repetido", ColumnMapping{Columns: map[string]string{"A": "Quadra", "B": "quadra"}}, "mapeadas para o mesmo campo"},
+		{"origem em colunas e pragas", ColumnMapping{Columns: map[string]string{"A": "Quadra"}, Pragas: map[string]string{"a": "Capim"}}, "coluna de origem repetida"},
+		{"praga vazia", ColumnMapping{Pragas: map[string]string{"A": " "}}, "nome da praga vazio"},
+		{"nível", ColumnMapping{Vocabulario: map[string]string{"SIM": "Z"}}, "nível inválido"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.mapping.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestParseSeparator(t *testing.T) {
+	for input, want := range map[string]rune{"": 0, ";": ';', ",": ',', "|": '|', "tab": '\t', "\t": '\t'} {
+		got, err := ParseSeparator(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, want, got, input)
+	}
+
+	for _, input := range []string{"x", ";;", " "} {
+		_, err := ParseSeparator(input)
+		assert.Error(t, err, input)
+	}
+}
+
+func parseFixture(t *testing.T, name string, opts ParseOptions) ([]*domain.AreaMonitoramento, *StreamResult) {
+	t.Helper()
+	file, err := os.Open(filepath.Join("testdata", name))
+	require.NoError(t, err)
+	defer file.Close()
+
+	var areas []*domain.AreaMonitoramento
+	result, err := NewParser(mockUUID()).ParseStream(file, "mon-123", opts, 10, func(batch []*domain.AreaMonitoramento) error {
+		areas = append(areas, batch...)
+		return nil
+	})
+	require.NoError(t, err)
+	return areas, result
+}
+
+func TestParser_ParseStream_Windows1252Detected(t *testing.T) {
+	areas, result := parseFixture(t, "windows1252.csv", ParseOptions{Validation: ValidationStrict})
+
+	assert.Equal(t, EncodingWindows1252, result.Encoding)
+	assert.Empty(t, result.Errors)
+	assert.Contains(t, result.Header, "Área Total")
+	require.Len(t, areas, 2)
+
+	assert.Equal(t, "Fazenda São João", areas[0].DescFazenda)
+	assert.Equal(t, 150.5, areas[0].AreaTotal)
+	assert.Equal(t, "Março", areas[0].MesColheita)
+	assert.Equal(t, "A", areas[0].PragasData.Pragas["Colonião"].Nivel)
+	assert.Equal(t, "X", areas[0].PragasData.Pragas["Capim Colchão"].Nivel)
+
+	// Aspas e travessão existem só no Windows-1252 (0x93, 0x94, 0x96)
+	assert.Equal(t, "Fazenda “Boa Vista” – Sede", areas[1].DescFazenda)
+	assert.Equal(t, 1200.75, areas[1].AreaTotal)
+	assert.Equal(t, "Área de preservação", areas[1].Restricao)
+}
+
+func TestParser_ParseStream_Latin1(t *testing.T) {
+	for _, encoding := range []string{"", "latin1", EncodingLatin1} {
+		t.Run(encoding, func(t *testing.T) {
+			areas, result := parseFixture(t, "latin1.csv", ParseOptions{Validation: ValidationStrict, Encoding: encoding})
+
+			assert.Empty(t, result.Errors)
+			require.Len(t, areas, 2)
+			assert.Equal(t, "Fazenda Araçá", areas[1].DescFazenda)
+			assert.Equal(t, "M", areas[1].PragasData.Pragas["Colonião"].Nivel)
+			assert.Equal(t, "Não", areas[1].Restricao)
+		})
+	}
+}
+
+func TestParser_ParseStream_EncodingFromMapping(t *testing.T) {
+	// Declarado no upload tem prioridade sobre o perfil
+	_, result := parseFixture(t, "latin1.csv", ParseOptions{Mapping: &ColumnMapping{Encoding: EncodingWindows1252}})
+	assert.Equal(t, EncodingWindows1252, result.Encoding)
+
+	_, result = parseFixture(t, "latin1.csv", ParseOptions{Encoding: EncodingLatin1, Mapping: &ColumnMapping{Encoding: EncodingWindows1252}})
+	assert.Equal(t, EncodingLatin1, result.Encoding)
+}
+
+func TestParser_ParseStream_Windows1252AfterPeekWindow(t *testing.T) {
+	// Acentos só depois dos primeiros 64KB: a detecção acontece no primeiro byte não ASCII
+	var content bytes.Buffer
+	content.WriteString("Id;Setor;Setor2;Cod.Fazenda;Desc.Fazenda;Quadra;Corte\n")
+	for i := 0; content.Len() < 2*peekSize; i++ {
+		fmt.Fprintf(&content, "%d;Norte;Sub;FAZ%d;Fazenda;Q%d;1\n", i, i, i)
+	}
+	content.WriteString("999;Norte;Sub;FAZ999;Fazenda S\xe3o Jos\xe9;Q999;1\n")
+
+	var last *domain.AreaMonitoramento
+	result, err := NewParser(mockUUID()).ParseStream(&content, "mon-123", ParseOptions{}, 500, func(batch []*domain.AreaMonitoramento) error {
+		last = batch[len(batch)-1]
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, EncodingWindows1252, result.Encoding)
+	assert.Equal(t, "Fazenda São José", last.DescFazenda)
+}
+
+func TestParser_ParseStream_UTF8Detected(t *testing.T) {
+	csvContent := "Id;Setor;Setor2;Cod.Fazenda;Desc.Fazenda;Quadra;Área Total\n1;Norte;Sub;F1;Fazenda São João;Q1;10\n"
+
+	var areas []*domain.AreaMonitoramento
+	result, err := NewParser(mockUUID()).ParseStream(strings.NewReader(csvContent), "mon-123", ParseOptions{}, 10, func(batch []*domain.AreaMonitoramento) error {
+		areas = append(areas, batch...)
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, EncodingUTF8, result.Encoding)
+	require.Len(t, areas, 1)
+	assert.Equal(t, "Fazenda São João", areas[0].DescFazenda)
+	assert.Equal(t, 10.0, areas[0].AreaTotal)
+}
+
+func TestParser_ParseStream_InvalidEncoding(t *testing.T) {
+	_, err := NewParser(mockUUID()).ParseStream(strings.NewReader("Id;Setor"), "mon-123", ParseOptions{Encoding: "utf-16"}, 10, func([]*domain.AreaMonitoramento) error {
+		return nil
+	})
+
+	assert.ErrorIs(t, err, sharedErrors.ErrInvalidCSV)
+}
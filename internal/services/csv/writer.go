@@ -0,0 +1,210 @@
+package csv
+
+import (
+	"encoding/csv"
+	"io"
+	"sort"
+	"strconv"
+
+	"agro-monitoring/internal/modules/area/domain"
+)
+
+// utf8BOM faz o Excel abrir o arquivo como UTF-8; o parser o remove na reimportação
+const utf8BOM = "\xef\xbb\xbf"
+
+// WriteErrors gera o CSV de erros: "Linha" e "Erro" seguidos das colunas originais.
+// As colunas extras ficam antes do cabeçalho original para não serem lidas como pragas,
+// então o arquivo corrigido pode ser reenviado diretamente.
+func WriteErrors(w io.Writer, header []string, errs []ParseError) error {
+	if _, err := io.WriteString(w, utf8BOM); err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(w)
+	writer.Comma = ';'
+
+	if err := writer.Write(append([]string{"Linha", "Erro"}, header...)); err != nil {
+		return err
+	}
+
+	for _, e := range errs {
+		row := make([]string, 0, len(header)+2)
+		row = append(row, strconv.Itoa(e.Linha), e.Erro)
+		row = append(row, e.Registro...)
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// ExportLayout colunas de pragas e de aplicações usadas pelas áreas exportadas.
+// Registre todas as áreas com Add antes de criar o AreaWriter.
+type ExportLayout struct {
+	pragas     map[string]bool
+	aplicacoes map[exportAplicacao]bool
+}
+
+// exportAplicacao par de colunas "Herb N [Praga]" / "Dose N [Praga]"
+type exportAplicacao struct {
+	praga   string
+	posicao int
+}
+
+// NewExportLayout cria um layout vazio
+func NewExportLayout() *ExportLayout {
+	return &ExportLayout{
+		pragas:     make(map[string]bool),
+		aplicacoes: make(map[exportAplicacao]bool),
+	}
+}
+
+// Add registra as pragas e as aplicações das áreas
+func (l *ExportLayout) Add(areas []*domain.AreaMonitoramento) {
+	for _, area := range areas {
+		for nome, info := range area.PragasData.Pragas {
+			if !info.Presente {
+				continue
+			}
+			l.pragas[nome] = true
+			for _, app := range info.Aplicacoes {
+				l.aplicacoes[exportAplicacao{praga: nome, posicao: app.Posicao}] = true
+			}
+		}
+		for _, app := range area.Aplicacoes {
+			l.aplicacoes[exportAplicacao{praga: app.Praga, posicao: app.Posicao}] = true
+		}
+	}
+}
+
+// AreaWriter escreve áreas no layout padrão aceito pelo Parser: campos fixos, uma coluna
+// por praga com o código do nível e os pares de colunas de herbicida e dose
+type AreaWriter struct {
+	writer     *csv.Writer
+	pragas     []string
+	aplicacoes []exportAplicacao
+}
+
+// NewAreaWriter escreve o cabeçalho do layout. Pragas em ordem alfabética; as aplicações
+// da área vêm antes das aplicações por praga, cada grupo em ordem de posição.
+func NewAreaWriter(w io.Writer, layout *ExportLayout) (*AreaWriter, error) {
+	pragas := make([]string, 0, len(layout.pragas))
+	for nome := range layout.pragas {
+		pragas = append(pragas, nome)
+	}
+	sort.Strings(pragas)
+
+	aplicacoes := make([]exportAplicacao, 0, len(layout.aplicacoes))
+	for app := range layout.aplicacoes {
+		aplicacoes = append(aplicacoes, app)
+	}
+	sort.Slice(aplicacoes, func(i, j int) bool {
+		if aplicacoes[i].praga != aplicacoes[j].praga {
+			return aplicacoes[i].praga < aplicacoes[j].praga
+		}
+		return aplicacoes[i].posicao < aplicacoes[j].posicao
+	})
+
+	if _, err := io.WriteString(w, utf8BOM); err != nil {
+		return nil, err
+	}
+
+	writer := csv.NewWriter(w)
+	writer.Comma = ';'
+
+	header := make([]string, 0, len(camposFixos)+len(pragas)+2*len(aplicacoes))
+	header = append(header, camposFixos...)
+	header = append(header, pragas...)
+	for _, app := range aplicacoes {
+		suffix := strconv.Itoa(app.posicao)
+		if app.praga != "" {
+			suffix += " " + app.praga
+		}
+		header = append(header, "Herb "+suffix, "Dose "+suffix)
+	}
+	if err := writer.Write(header); err != nil {
+		return nil, err
+	}
+
+	return &AreaWriter{writer: writer, pragas: pragas, aplicacoes: aplicacoes}, nil
+}
+
+// Write escreve uma linha por área
+func (aw *AreaWriter) Write(areas []*domain.AreaMonitoramento) error {
+	for _, area := range areas {
+		row := []string{
+			area.ExternalKey,
+			area.Setor,
+			area.Setor2,
+			area.CodFazenda,
+			area.DescFazenda,
+			area.Quadra,
+			strconv.Itoa(area.Corte),
+			formatDecimal(area.AreaTotal),
+			area.DescTexturaSolo,
+			strconv.Itoa(area.CorteAtual),
+			area.Reforma,
+			area.MesColheita,
+			area.Restricao,
+		}
+
+		for _, nome := range aw.pragas {
+			row = append(row, nivelCode(area.PragasData.Pragas[nome]))
+		}
+
+		for _, col := range aw.aplicacoes {
+			herbicida, dose := "", ""
+			if app, ok := findAplicacao(area, col); ok {
+				herbicida, dose = app.Herbicida, formatDecimal(app.Dose)
+			}
+			row = append(row, herbicida, dose)
+		}
+
+		if err := aw.writer.Write(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Flush grava o conteúdo pendente
+func (aw *AreaWriter) Flush() error {
+	aw.writer.Flush()
+	return aw.writer.Error()
+}
+
+// findAplicacao aplicação da área na coluna. As aplicações por praga incluídas pela API
+// ficam só em PragasData e têm prioridade sobre as lidas do arquivo
+func findAplicacao(area *domain.AreaMonitoramento, col exportAplicacao) (domain.AplicacaoHerbicidaJson, bool) {
+	if col.praga != "" {
+		for _, app := range area.PragasData.Pragas[col.praga].Aplicacoes {
+			if app.Posicao == col.posicao {
+				return app, true
+			}
+		}
+	}
+	for _, app := range area.Aplicacoes {
+		if app.Praga == col.praga && app.Posicao == col.posicao {
+			return app, true
+		}
+	}
+	return domain.AplicacaoHerbicidaJson{}, false
+}
+
+// nivelCode código da célula de praga: o nível (A, B, M) ou X para presença sem nível
+func nivelCode(info domain.PragaInfo) string {
+	switch {
+	case !info.Presente:
+		return ""
+	case info.Nivel == "":
+		return "X"
+	}
+	return info.Nivel
+}
+
+// formatDecimal usa ponto decimal, que o parser lê como decimal quando não há vírgula
+func formatDecimal(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -2,6 +2,7 @@ package queue
 
 import (
 	"context"
+	"time"
 
 	"agro-monitoring/internal/modules/jobs/domain"
 )
@@ -10,10 +11,18 @@ const (
 	QueueDefault = "default"
 )
 
+// VisibilityTimeout tempo sem heartbeat após o qual os jobs em processamento de um worker
+// são considerados abandonados e voltam para a fila
+const VisibilityTimeout = time.Minute
+
+// HeartbeatInterval intervalo em que o worker deve renovar o lease com Heartbeat
+const HeartbeatInterval = VisibilityTimeout / 4
+
 // EnqueueOptions opções para enfileirar
 type EnqueueOptions struct {
 	QueueName string
-	Delay     int
+	// Delay adia a entrega do job; ele fica agendado até PromoteDelayed movê-lo para a fila
+	Delay time.Duration
 }
 
 // Job a ser processado
@@ -24,9 +33,30 @@ type Job struct {
 	JobEntity *domain.Job
 }
 
-// Service define a interface do serviço de fila
+// Service define a interface do serviço de fila. A entrega é at-least-once: o job retirado
+// por Dequeue fica na lista de processamento do worker até Ack ou Nack e volta para a fila
+// se o worker parar de enviar heartbeats.
 type Service interface {
 	Enqueue(ctx context.Context, job *Job, opts *EnqueueOptions) error
-	Dequeue(ctx context.Context, queueName string) (*Job, error)
+	// Dequeue aguarda um job em qualquer uma das filas informadas
+	Dequeue(ctx context.Context, queueNames ...string) (*Job, error)
+	// Ack confirma o processamento e remove o job da lista de processamento
+	Ack(ctx context.Context, job *Job) error
+	// Nack devolve o job para o início da fila
+	Nack(ctx context.Context, job *Job) error
+	// Heartbeat renova o lease do worker por VisibilityTimeout
+	Heartbeat(ctx context.Context) error
+	// RequeueExpired devolve para as filas os jobs de workers com lease expirado e os retorna
+	RequeueExpired(ctx context.Context, queueNames ...string) ([]*Job, error)
+	// QueuedIDs retorna os IDs dos jobs agendados, aguardando ou em processamento nas filas
+	QueuedIDs(ctx context.Context, queueNames ...string) (map[string]bool, error)
+	// PromoteDelayed move para as filas os jobs agendados cujo Delay já passou
+	PromoteDelayed(ctx context.Context, queueNames ...string) (int, error)
+	// DeadLetter guarda o job que esgotou as tentativas na dead-letter queue
+	DeadLetter(ctx context.Context, job *Job) error
+	// DeadJobs lista a dead-letter queue, do mais recente ao mais antigo
+	DeadJobs(ctx context.Context, offset, limit int) ([]*Job, int, error)
+	// RemoveDead retira o job da dead-letter queue e o retorna
+	RemoveDead(ctx context.Context, id string) (*Job, error)
 	Close() error
 }
@@ -2,26 +2,89 @@ package queue
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"os"
+	"strconv"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 
 	"agro-monitoring/internal/modules/jobs/domain"
+	sharedErrors "agro-monitoring/internal/shared/errors"
 )
 
+const (
+	// leasesKey sorted set com o prazo do lease (score, unix ms) de cada worker
+	leasesKey = "queue:leases"
+	// deadLetterKey lista dos jobs que esgotaram as tentativas (de todas as filas)
+	deadLetterKey = "queue:dead"
+	// promoteBatch máximo de jobs agendados movidos por fila a cada PromoteDelayed
+	promoteBatch = 100
+)
+
+// requeueScript devolve a entrada para o início da fila só se ela ainda estava na lista de
+// processamento (evita duplicar um job que o reaper já devolveu)
+var requeueScript = redis.NewScript(`
+if redis.call('LREM', KEYS[1], 1, ARGV[1]) == 1 then
+	redis.call('RPUSH', KEYS[2], ARGV[1])
+	return 1
+end
+return 0
+`)
+
+// releaseLeaseScript remove o lease só se o worker não o renovou enquanto os jobs eram devolvidos
+var releaseLeaseScript = redis.NewScript(`
+if tonumber(redis.call('ZSCORE', KEYS[1], ARGV[1]) or '0') <= tonumber(ARGV[2]) then
+	return redis.call('ZREM', KEYS[1], ARGV[1])
+end
+return 0
+`)
+
+// promoteScript move para a fila os jobs agendados com prazo vencido (score <= agora)
+var promoteScript = redis.NewScript(`
+local entries = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, tonumber(ARGV[2]))
+for _, entry in ipairs(entries) do
+	redis.call('ZREM', KEYS[1], entry)
+	redis.call('LPUSH', KEYS[2], entry)
+end
+return #entries
+`)
+
 type RedisQueueService struct {
 	client *redis.Client
+	// consumer identifica o worker; cada worker tem uma lista de processamento por fila
+	consumer string
 }
 
 // NewRedisQueueService cria um novo serviço de fila com Redis
 func NewRedisQueueService(opts *redis.Options) Service {
 	rdb := redis.NewClient(opts)
-	return &RedisQueueService{client: rdb}
+	return &RedisQueueService{client: rdb, consumer: newConsumerID()}
+}
+
+// newConsumerID host, pid e um sufixo aleatório (o pid se repete entre containers)
+func newConsumerID() string {
+	host, _ := os.Hostname()
+	suffix := make([]byte, 4)
+	rand.Read(suffix)
+	return fmt.Sprintf("%s-%d-%s", host, os.Getpid(), hex.EncodeToString(suffix))
+}
+
+// processingKey lista dos jobs da fila em processamento pelo worker
+func processingKey(queueName, consumer string) string {
+	return queueName + ":processing:" + consumer
 }
 
-// Enqueue adiciona um job à fila
+// delayedKey sorted set dos jobs agendados da fila (score = unix ms da entrega)
+func delayedKey(queueName string) string {
+	return queueName + ":delayed"
+}
+
+// Enqueue adiciona um job à fila, ou o agenda se opts.Delay > 0
 func (s *RedisQueueService) Enqueue(ctx context.Context, job *Job, opts *EnqueueOptions) error {
 	queueName := QueueDefault
 	if opts != nil && opts.QueueName != "" {
@@ -33,14 +96,39 @@ func (s *RedisQueueService) Enqueue(ctx context.Context, job *Job, opts *Enqueue
 		return err
 	}
 
+	if opts != nil && opts.Delay > 0 {
+		readyAt := time.Now().Add(opts.Delay).UnixMilli()
+		return s.client.ZAdd(ctx, delayedKey(queueName), redis.Z{Score: float64(readyAt), Member: payload}).Err()
+	}
+
 	return s.client.LPush(ctx, queueName, payload).Err()
 }
 
-// Dequeue busca um job da fila de forma bloqueante
-func (s *RedisQueueService) Dequeue(ctx context.Context, queueName string) (*Job, error) {
-	// BRPOP bloqueia até que um item esteja disponível ou o timeout ocorra
-	// Timeout de 1 segundo para permitir verificação do context cancelado
-	result, err := s.client.BRPop(ctx, time.Second, queueName).Result()
+// Dequeue move um job para a lista de processamento do worker (na ordem de prioridade informada)
+func (s *RedisQueueService) Dequeue(ctx context.Context, queueNames ...string) (*Job, error) {
+	if len(queueNames) == 0 {
+		return nil, errors.New("nenhuma fila informada")
+	}
+
+	// O lease é renovado antes de mover: um job nunca fica na lista de processamento sem lease
+	if err := s.Heartbeat(ctx); err != nil {
+		return nil, err
+	}
+
+	for _, queueName := range queueNames {
+		entry, err := s.client.LMove(ctx, queueName, processingKey(queueName, s.consumer), "RIGHT", "LEFT").Result()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return s.decode(ctx, queueName, entry)
+	}
+
+	// Filas vazias: BLMOVE aceita uma única origem, então bloqueia na fila mais prioritária.
+	// Timeout de 1 segundo para permitir verificação do context cancelado e das demais filas
+	entry, err := s.client.BLMove(ctx, queueNames[0], processingKey(queueNames[0], s.consumer), "RIGHT", "LEFT", time.Second).Result()
 	if err != nil {
 		// Timeout sem job na fila - retorna nil sem erro
 		if errors.Is(err, redis.Nil) {
@@ -48,25 +136,195 @@ func (s *RedisQueueService) Dequeue(ctx context.Context, queueName string) (*Job
 		}
 		return nil, err
 	}
+	return s.decode(ctx, queueNames[0], entry)
+}
 
-	// BRPop retorna um array com [nome_da_fila, valor]
-	if len(result) != 2 {
-		return nil, redis.Nil
-	}
-
+// decode monta o Job da entrada; entradas inválidas são descartadas da lista de processamento
+func (s *RedisQueueService) decode(ctx context.Context, queueName, entry string) (*Job, error) {
 	var jobEntity domain.Job
-	if err := json.Unmarshal([]byte(result[1]), &jobEntity); err != nil {
-		return nil, err
+	if err := json.Unmarshal([]byte(entry), &jobEntity); err != nil {
+		s.client.LRem(ctx, processingKey(queueName, s.consumer), 1, entry)
+		return nil, fmt.Errorf("entrada inválida na fila %s: %w", queueName, err)
 	}
 
-	job := &Job{
+	return &Job{
 		ID:        jobEntity.ID,
-		Queue:     result[0], // O nome da fila da qual o job foi retirado
-		Payload:   []byte(result[1]),
+		Queue:     queueName,
+		Payload:   []byte(entry),
 		JobEntity: &jobEntity,
+	}, nil
+}
+
+// Ack remove o job da lista de processamento
+func (s *RedisQueueService) Ack(ctx context.Context, job *Job) error {
+	return s.client.LRem(ctx, processingKey(job.Queue, s.consumer), 1, job.Payload).Err()
+}
+
+// Nack devolve o job para o início da fila (é o próximo a ser retirado)
+func (s *RedisQueueService) Nack(ctx context.Context, job *Job) error {
+	keys := []string{processingKey(job.Queue, s.consumer), job.Queue}
+	return requeueScript.Run(ctx, s.client, keys, job.Payload).Err()
+}
+
+// Heartbeat renova o lease do worker
+func (s *RedisQueueService) Heartbeat(ctx context.Context) error {
+	deadline := time.Now().Add(VisibilityTimeout).UnixMilli()
+	return s.client.ZAdd(ctx, leasesKey, redis.Z{Score: float64(deadline), Member: s.consumer}).Err()
+}
+
+// RequeueExpired devolve os jobs dos workers sem heartbeat para o início das filas
+func (s *RedisQueueService) RequeueExpired(ctx context.Context, queueNames ...string) ([]*Job, error) {
+	now := strconv.FormatInt(time.Now().UnixMilli(), 10)
+	consumers, err := s.client.ZRangeByScore(ctx, leasesKey, &redis.ZRangeBy{Min: "-inf", Max: now}).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	requeued := make([]*Job, 0)
+	for _, consumer := range consumers {
+		// O próprio worker está vivo; o lease só expirou por atraso no heartbeat
+		if consumer == s.consumer {
+			continue
+		}
+
+		for _, queueName := range queueNames {
+			for {
+				// Do mais recente ao mais antigo: o mais antigo termina no início da fila
+				entry, err := s.client.LMove(ctx, processingKey(queueName, consumer), queueName, "LEFT", "RIGHT").Result()
+				if errors.Is(err, redis.Nil) {
+					break
+				}
+				if err != nil {
+					return requeued, err
+				}
+
+				var jobEntity domain.Job
+				if err := json.Unmarshal([]byte(entry), &jobEntity); err != nil {
+					continue
+				}
+				requeued = append(requeued, &Job{ID: jobEntity.ID, Queue: queueName, Payload: []byte(entry), JobEntity: &jobEntity})
+			}
+		}
+
+		if err := releaseLeaseScript.Run(ctx, s.client, []string{leasesKey}, consumer, now).Err(); err != nil {
+			return requeued, err
+		}
+	}
+
+	return requeued, nil
+}
+
+// QueuedIDs lê os agendados, as filas e as listas de processamento de todos os workers com lease
+func (s *RedisQueueService) QueuedIDs(ctx context.Context, queueNames ...string) (map[string]bool, error) {
+	consumers, err := s.client.ZRange(ctx, leasesKey, 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make(map[string]bool)
+	addIDs := func(entries []string) {
+		for _, entry := range entries {
+			var ref struct{ ID string }
+			if json.Unmarshal([]byte(entry), &ref) == nil {
+				ids[ref.ID] = true
+			}
+		}
+	}
+
+	for _, queueName := range queueNames {
+		delayed, err := s.client.ZRange(ctx, delayedKey(queueName), 0, -1).Result()
+		if err != nil {
+			return nil, err
+		}
+		addIDs(delayed)
+
+		keys := []string{queueName}
+		for _, consumer := range consumers {
+			keys = append(keys, processingKey(queueName, consumer))
+		}
+
+		for _, key := range keys {
+			entries, err := s.client.LRange(ctx, key, 0, -1).Result()
+			if err != nil {
+				return nil, err
+			}
+			addIDs(entries)
+		}
+	}
+	return ids, nil
+}
+
+// PromoteDelayed move os jobs agendados vencidos para o fim da fila (em lotes de promoteBatch)
+func (s *RedisQueueService) PromoteDelayed(ctx context.Context, queueNames ...string) (int, error) {
+	now := strconv.FormatInt(time.Now().UnixMilli(), 10)
+
+	promoted := 0
+	for _, queueName := range queueNames {
+		n, err := promoteScript.Run(ctx, s.client, []string{delayedKey(queueName), queueName}, now, promoteBatch).Int()
+		if err != nil {
+			return promoted, err
+		}
+		promoted += n
+	}
+	return promoted, nil
+}
+
+// DeadLetter adiciona o job ao início da dead-letter queue
+func (s *RedisQueueService) DeadLetter(ctx context.Context, job *Job) error {
+	payload, err := json.Marshal(job.JobEntity)
+	if err != nil {
+		return err
+	}
+	return s.client.LPush(ctx, deadLetterKey, payload).Err()
+}
+
+// DeadJobs lista uma página da dead-letter queue
+func (s *RedisQueueService) DeadJobs(ctx context.Context, offset, limit int) ([]*Job, int, error) {
+	total, err := s.client.LLen(ctx, deadLetterKey).Result()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	entries, err := s.client.LRange(ctx, deadLetterKey, int64(offset), int64(offset+limit-1)).Result()
+	if err != nil {
+		return nil, 0, err
 	}
 
-	return job, nil
+	jobs := make([]*Job, 0, len(entries))
+	for _, entry := range entries {
+		var jobEntity domain.Job
+		if err := json.Unmarshal([]byte(entry), &jobEntity); err != nil {
+			continue
+		}
+		jobs = append(jobs, &Job{ID: jobEntity.ID, Payload: []byte(entry), JobEntity: &jobEntity})
+	}
+	return jobs, int(total), nil
+}
+
+// RemoveDead procura o job pelo ID e o remove da dead-letter queue.
+// Retorna ErrJobNotFound se ele não está (ou já foi retirado por outra requisição)
+func (s *RedisQueueService) RemoveDead(ctx context.Context, id string) (*Job, error) {
+	entries, err := s.client.LRange(ctx, deadLetterKey, 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		var jobEntity domain.Job
+		if err := json.Unmarshal([]byte(entry), &jobEntity); err != nil || jobEntity.ID != id {
+			continue
+		}
+
+		removed, err := s.client.LRem(ctx, deadLetterKey, 1, entry).Result()
+		if err != nil {
+			return nil, err
+		}
+		if removed == 0 {
+			break
+		}
+		return &Job{ID: jobEntity.ID, Payload: []byte(entry), JobEntity: &jobEntity}, nil
+	}
+	return nil, sharedErrors.ErrJobNotFound
 }
 
 // Close fecha a conexão com o Redis
@@ -0,0 +1,39 @@
+// Package storage guarda arquivos (blobs) por chave, em disco ou em um serviço compatível com S3.
+// As chaves usam "/" como separador e começam pelo client_id, ex.: "<client_id>/<monitoramento_id>.csv".
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	sharedErrors "agro-monitoring/internal/shared/errors"
+)
+
+// Service define a interface do armazenamento de arquivos
+type Service interface {
+	// Put grava o conteúdo na chave, substituindo o arquivo existente; size é o tamanho em bytes
+	Put(ctx context.Context, key string, r io.Reader, size int64) error
+	// Get abre o arquivo; retorna ErrFileNotFound se a chave não existe
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete remove o arquivo; remover uma chave inexistente não é erro
+	Delete(ctx context.Context, key string) error
+	// List retorna as chaves com o prefixo informado, em ordem
+	List(ctx context.Context, prefix string) ([]string, error)
+}
+
+// Key monta uma chave a partir das partes, ex.: Key(clientID, "arquivo.csv")
+func Key(parts ...string) string {
+	return strings.Join(parts, "/")
+}
+
+// validateKey rejeita chaves vazias, absolutas ou que saem do diretório base
+func validateKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") || path.Clean(key) != key ||
+		key == ".." || strings.HasPrefix(key, "../") {
+		return fmt.Errorf("%w: chave inválida %q", sharedErrors.ErrInvalidStorageKey, key)
+	}
+	return nil
+}
@@ -0,0 +1,337 @@
+// Package xlsx lê planilhas Excel (.xlsx) linha a linha, sem dependências externas.
+// Suporta valores de célula (texto, número, booleano e resultado de fórmula);
+// estilos e formatação (ex.: datas) não são interpretados.
+package xlsx
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+
+	sharedErrors "agro-monitoring/internal/shared/errors"
+)
+
+// Workbook planilha aberta: abas e strings compartilhadas
+type Workbook struct {
+	zip           *zip.Reader
+	sheets        []sheetRef
+	sharedStrings []string
+}
+
+type sheetRef struct {
+	name string
+	path string
+}
+
+// Open abre o arquivo .xlsx e carrega a lista de abas e as strings compartilhadas
+func Open(r io.ReaderAt, size int64) (*Workbook, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("%w: arquivo não é um .xlsx: %v", sharedErrors.ErrInvalidSpreadsheet, err)
+	}
+
+	wb := &Workbook{zip: zr}
+	if err := wb.loadSheets(); err != nil {
+		return nil, err
+	}
+	if err := wb.loadSharedStrings(); err != nil {
+		return nil, err
+	}
+	return wb, nil
+}
+
+// SheetNames nomes das abas na ordem da planilha
+func (wb *Workbook) SheetNames() []string {
+	names := make([]string, len(wb.sheets))
+	for i, s := range wb.sheets {
+		names[i] = s.name
+	}
+	return names
+}
+
+// OpenSheet abre a aba pelo nome (sem diferenciar maiúsculas); vazio abre a primeira aba
+func (wb *Workbook) OpenSheet(name string) (*SheetReader, error) {
+	if len(wb.sheets) == 0 {
+		return nil, fmt.Errorf("%w: planilha sem abas", sharedErrors.ErrInvalidSpreadsheet)
+	}
+
+	ref := wb.sheets[0]
+	if name != "" {
+		found := false
+		for _, s := range wb.sheets {
+			if strings.EqualFold(s.name, strings.TrimSpace(name)) {
+				ref, found = s, true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("%w: aba %q não encontrada", sharedErrors.ErrInvalidSpreadsheet, name)
+		}
+	}
+
+	f := wb.file(ref.path)
+	if f == nil {
+		return nil, fmt.Errorf("%w: aba %q sem conteúdo", sharedErrors.ErrInvalidSpreadsheet, ref.name)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("%w: erro ao abrir aba %q: %v", sharedErrors.ErrInvalidSpreadsheet, ref.name, err)
+	}
+
+	return &SheetReader{
+		rc:            rc,
+		decoder:       xml.NewDecoder(rc),
+		sharedStrings: wb.sharedStrings,
+	}, nil
+}
+
+func (wb *Workbook) file(name string) *zip.File {
+	for _, f := range wb.zip.File {
+		if f.Name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+func (wb *Workbook) decodeFile(name string, v interface{}) (bool, error) {
+	f := wb.file(name)
+	if f == nil {
+		return false, nil
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return true, err
+	}
+	defer rc.Close()
+	return true, xml.NewDecoder(rc).Decode(v)
+}
+
+func (wb *Workbook) loadSheets() error {
+	var workbook struct {
+		Sheets []struct {
+			Name string `xml:"name,attr"`
+			RID  string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	found, err := wb.decodeFile("xl/workbook.xml", &workbook)
+	if !found || err != nil {
+		return fmt.Errorf("%w: xl/workbook.xml ausente ou inválido", sharedErrors.ErrInvalidSpreadsheet)
+	}
+
+	var rels struct {
+		Relationships []struct {
+			ID     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+	if found, err := wb.decodeFile("xl/_rels/workbook.xml.rels", &rels); !found || err != nil {
+		return fmt.Errorf("%w: xl/_rels/workbook.xml.rels ausente ou inválido", sharedErrors.ErrInvalidSpreadsheet)
+	}
+
+	targets := make(map[string]string, len(rels.Relationships))
+	for _, rel := range rels.Relationships {
+		targets[rel.ID] = rel.Target
+	}
+
+	for _, s := range workbook.Sheets {
+		target, ok := targets[s.RID]
+		if !ok {
+			return fmt.Errorf("%w: aba %q sem relacionamento", sharedErrors.ErrInvalidSpreadsheet, s.Name)
+		}
+		// Target é relativo a xl/ ou absoluto a partir da raiz do pacote
+		if strings.HasPrefix(target, "/") {
+			target = strings.TrimPrefix(target, "/")
+		} else {
+			target = path.Join("xl", target)
+		}
+		wb.sheets = append(wb.sheets, sheetRef{name: s.Name, path: target})
+	}
+	return nil
+}
+
+func (wb *Workbook) loadSharedStrings() error {
+	var sst struct {
+		Items []richText `xml:"si"`
+	}
+	found, err := wb.decodeFile("xl/sharedStrings.xml", &sst)
+	if err != nil {
+		return fmt.Errorf("%w: xl/sharedStrings.xml inválido: %v", sharedErrors.ErrInvalidSpreadsheet, err)
+	}
+	if !found {
+		return nil
+	}
+
+	wb.sharedStrings = make([]string, len(sst.Items))
+	for i, item := range sst.Items {
+		wb.sharedStrings[i] = item.String()
+	}
+	return nil
+}
+
+// richText texto simples (<t>) ou com formatação (<r><t>); a transcrição fonética (<rPh>) é ignorada
+type richText struct {
+	T    string `xml:"t"`
+	Runs []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (rt richText) String() string {
+	if len(rt.Runs) == 0 {
+		return rt.T
+	}
+	var b strings.Builder
+	b.WriteString(rt.T)
+	for _, r := range rt.Runs {
+		b.WriteString(r.T)
+	}
+	return b.String()
+}
+
+// SheetReader lê as linhas de uma aba; linhas vazias omitidas no arquivo são puladas
+type SheetReader struct {
+	rc            io.ReadCloser
+	decoder       *xml.Decoder
+	sharedStrings []string
+	line          int
+	numeric       []bool // células numéricas da última linha lida
+}
+
+type xmlCell struct {
+	Ref   string   `xml:"r,attr"`
+	Type  string   `xml:"t,attr"`
+	Value string   `xml:"v"`
+	Rich  richText `xml:"is"`
+}
+
+// Read retorna os valores da próxima linha, com as células ausentes preenchidas com vazio
+func (s *SheetReader) Read() ([]string, error) {
+	for {
+		tok, err := s.decoder.Token()
+		if err == io.EOF {
+			return nil, io.EOF
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: erro ao ler aba: %v", sharedErrors.ErrInvalidSpreadsheet, err)
+		}
+
+		start, ok := tok.(xml.StartElement)
+		if !ok || start.Name.Local != "row" {
+			continue
+		}
+
+		var row struct {
+			Ref   string    `xml:"r,attr"`
+			Cells []xmlCell `xml:"c"`
+		}
+		if err := s.decoder.DecodeElement(&row, &start); err != nil {
+			return nil, fmt.Errorf("%w: erro ao ler linha: %v", sharedErrors.ErrInvalidSpreadsheet, err)
+		}
+
+		if n, err := strconv.Atoi(row.Ref); err == nil {
+			s.line = n
+		} else {
+			s.line++
+		}
+
+		return s.values(row.Cells)
+	}
+}
+
+// Line número da última linha lida, como exibido no Excel
+func (s *SheetReader) Line() int {
+	return s.line
+}
+
+// IsNumeric indica se a coluna col da última linha lida é uma célula numérica. O valor
+// dessas células usa ponto decimal ("0.125"), sem o formato regional das células de texto
+func (s *SheetReader) IsNumeric(col int) bool {
+	return col >= 0 && col < len(s.numeric) && s.numeric[col]
+}
+
+// Close libera a aba
+func (s *SheetReader) Close() error {
+	return s.rc.Close()
+}
+
+func (s *SheetReader) values(cells []xmlCell) ([]string, error) {
+	record := make([]string, 0, len(cells))
+	s.numeric = s.numeric[:0]
+	for _, c := range cells {
+		col := len(record)
+		if c.Ref != "" {
+			idx, err := columnIndex(c.Ref)
+			if err != nil {
+				return nil, fmt.Errorf("%w: linha %d: %v", sharedErrors.ErrInvalidSpreadsheet, s.line, err)
+			}
+			col = idx
+		}
+		for len(record) <= col {
+			record = append(record, "")
+			s.numeric = append(s.numeric, false)
+		}
+
+		value, err := s.cellValue(c)
+		if err != nil {
+			return nil, fmt.Errorf("%w: célula %s: %v", sharedErrors.ErrInvalidSpreadsheet, c.Ref, err)
+		}
+		record[col] = value
+		s.numeric[col] = isNumericCell(c)
+	}
+	return record, nil
+}
+
+func (s *SheetReader) cellValue(c xmlCell) (string, error) {
+	switch c.Type {
+	case "s":
+		idx, err := strconv.Atoi(strings.TrimSpace(c.Value))
+		if err != nil || idx < 0 || idx >= len(s.sharedStrings) {
+			return "", fmt.Errorf("índice de string compartilhada inválido %q", c.Value)
+		}
+		return s.sharedStrings[idx], nil
+	case "inlineStr":
+		return c.Rich.String(), nil
+	case "b":
+		if c.Value == "1" {
+			return "TRUE", nil
+		}
+		return "FALSE", nil
+	case "str", "e":
+		return c.Value, nil
+	}
+
+	// Números: notação científica é convertida para decimal ("1E-3" -> "0.001")
+	if strings.ContainsAny(c.Value, "eE") {
+		if f, err := strconv.ParseFloat(c.Value, 64); err == nil {
+			return strconv.FormatFloat(f, 'f', -1, 64), nil
+		}
+	}
+	return c.Value, nil
+}
+
+// isNumericCell células sem tipo ou com t="n" guardam números
+func isNumericCell(c xmlCell) bool {
+	return (c.Type == "" || c.Type == "n") && c.Value != ""
+}
+
+// columnIndex converte a referência da célula ("C12") no índice da coluna (2)
+func columnIndex(ref string) (int, error) {
+	idx := 0
+	n := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		idx = idx*26 + int(r-'A'+1)
+		n++
+	}
+	if n == 0 || n > 3 {
+		return 0, fmt.Errorf("referência de célula inválida %q", ref)
+	}
+	return idx - 1, nil
+}
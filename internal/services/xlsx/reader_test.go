@@ -0,0 +1,153 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	sharedErrors "agro-monitoring/internal/shared/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// buildXLSX monta um .xlsx mínimo com as abas (nome -> XML de <sheetData>) e as strings compartilhadas
+func buildXLSX(t *testing.T, sharedStrings []string, sheets ...[2]string) *bytes.Reader {
+	t.Helper()
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	write := func(name, content string) {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		_, err = io.WriteString(w, content)
+		require.NoError(t, err)
+	}
+
+	var sheetsXML, relsXML strings.Builder
+	for i, sheet := range sheets {
+		fmt.Fprintf(&sheetsXML, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, sheet[0], i+1, i+1)
+		fmt.Fprintf(&relsXML, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, i+1, i+1)
+		write(fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1),
+			`<?xml version="1.0" encoding="UTF-8"?><worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`+sheet[1]+`</sheetData></worksheet>`)
+	}
+
+	write("xl/workbook.xml", `<?xml version="1.0" encoding="UTF-8"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>`+sheetsXML.String()+`</sheets></workbook>`)
+	write("xl/_rels/workbook.xml.rels", `<?xml version="1.0" encoding="UTF-8"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`+relsXML.String()+`</Relationships>`)
+
+	if len(sharedStrings) > 0 {
+		var sst strings.Builder
+		for _, s := range sharedStrings {
+			fmt.Fprintf(&sst, "<si><t>%s</t></si>", s)
+		}
+		write("xl/sharedStrings.xml", `<?xml version="1.0" encoding="UTF-8"?>
+<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`+sst.String()+`</sst>`)
+	}
+
+	require.NoError(t, zw.Close())
+	return bytes.NewReader(buf.Bytes())
+}
+
+func readAll(t *testing.T, s *SheetReader) ([][]string, []int) {
+	t.Helper()
+	var rows [][]string
+	var lines []int
+	for {
+		row, err := s.Read()
+		if err == io.EOF {
+			return rows, lines
+		}
+		require.NoError(t, err)
+		rows = append(rows, row)
+		lines = append(lines, s.Line())
+	}
+}
+
+func TestWorkbook_OpenSheet(t *testing.T) {
+	file := buildXLSX(t, []string{"Quadra", "Área Total", "Q1"},
+		[2]string{"Plan1", `
+			<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c><c r="C1" t="inlineStr"><is><r><t>Capim </t></r><r><t>Colchão</t></r></is></c></row>
+			<row r="2"><c r="A2" t="s"><v>2</v></c><c r="B2"><v>150.5</v></c><c r="C2" t="b"><v>1</v></c></row>
+			<row r="5"><c r="B5"><v>1E-3</v></c><c r="C5" t="str"><v>X</v></c></row>`},
+		[2]string{"Resumo", `<row r="1"><c r="A1" t="inlineStr"><is><t>Total</t></is></c></row>`},
+	)
+
+	wb, err := Open(file, file.Size())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Plan1", "Resumo"}, wb.SheetNames())
+
+	sheet, err := wb.OpenSheet("")
+	require.NoError(t, err)
+	defer sheet.Close()
+
+	rows, lines := readAll(t, sheet)
+	assert.Equal(t, [][]string{
+		{"Quadra", "Área Total", "Capim Colchão"},
+		{"Q1", "150.5", "TRUE"},
+		{"", "0.001", "X"},
+	}, rows)
+	// Linhas vazias omitidas no arquivo mantêm a numeração do Excel
+	assert.Equal(t, []int{1, 2, 5}, lines)
+
+	resumo, err := wb.OpenSheet("resumo")
+	require.NoError(t, err)
+	defer resumo.Close()
+	rows, _ = readAll(t, resumo)
+	assert.Equal(t, [][]string{{"Total"}}, rows)
+}
+
+func TestSheetReader_IsNumeric(t *testing.T) {
+	file := buildXLSX(t, []string{"0,125"},
+		[2]string{"Plan1", `<row r="1"><c r="A1"><v>0.125</v></c><c r="B1" t="s"><v>0</v></c><c r="D1" t="n"><v>2</v></c><c r="E1" t="str"><v>3</v></c></row>`},
+	)
+
+	wb, err := Open(file, file.Size())
+	require.NoError(t, err)
+	sheet, err := wb.OpenSheet("")
+	require.NoError(t, err)
+	defer sheet.Close()
+
+	row, err := sheet.Read()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"0.125", "0,125", "", "2", "3"}, row)
+
+	// Só as células numéricas; texto, célula ausente e resultado de fórmula em texto não
+	for col, expected := range []bool{true, false, false, true, false, false} {
+		assert.Equal(t, expected, sheet.IsNumeric(col), "coluna %d", col)
+	}
+}
+
+func TestWorkbook_OpenSheet_NotFound(t *testing.T) {
+	file := buildXLSX(t, nil, [2]string{"Plan1", ""})
+
+	wb, err := Open(file, file.Size())
+	require.NoError(t, err)
+
+	_, err = wb.OpenSheet("Dados")
+	assert.ErrorIs(t, err, sharedErrors.ErrInvalidSpreadsheet)
+	assert.Contains(t, err.Error(), `aba "Dados" não encontrada`)
+}
+
+func TestOpen_NotXLSX(t *testing.T) {
+	data := strings.NewReader("Id;Setor\n1;Norte")
+
+	_, err := Open(data, data.Size())
+	assert.ErrorIs(t, err, sharedErrors.ErrInvalidSpreadsheet)
+}
+
+func TestColumnIndex(t *testing.T) {
+	tests := map[string]int{"A1": 0, "C12": 2, "Z3": 25, "AA1": 26, "AB10": 27}
+	for ref, expected := range tests {
+		idx, err := columnIndex(ref)
+		require.NoError(t, err, ref)
+		assert.Equal(t, expected, idx, ref)
+	}
+
+	_, err := columnIndex("12")
+	assert.Error(t, err)
+}
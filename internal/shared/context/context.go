@@ -3,6 +3,7 @@ package context
 import (
 	"context"
 
+	sharedErrors "agro-monitoring/internal/shared/errors"
 	"agro-monitoring/internal/shared/middleware"
 )
 
@@ -17,3 +18,23 @@ func GetUserID(ctx context.Context) (string, bool) {
 	userID, ok := ctx.Value(middleware.UserIDKey).(string)
 	return userID, ok
 }
+
+// RequireClientID extrai o client_id do context, retornando erro se ausente
+func RequireClientID(ctx context.Context) (string, error) {
+	clientID, ok := GetClientID(ctx)
+	if !ok || clientID == "" {
+		return "", sharedErrors.ErrTenantRequired
+	}
+	return clientID, nil
+}
+
+// WithTenant retorna um context com client_id e user_id (usado fora do HTTP, ex: worker)
+func WithTenant(ctx context.Context, clientID, userID string) context.Context {
+	if clientID != "" {
+		ctx = context.WithValue(ctx, middleware.ClientIDKey, clientID)
+	}
+	if userID != "" {
+		ctx = context.WithValue(ctx, middleware.UserIDKey, userID)
+	}
+	return ctx
+}
@@ -0,0 +1,54 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	sharedContext "agro-monitoring/internal/shared/context"
+)
+
+// TenantDB executa operações em transações com app.client_id configurado,
+// que é a chave das políticas de row-level security (migration 009)
+type TenantDB struct {
+	db *sql.DB
+}
+
+// NewTenantDB cria um novo TenantDB
+func NewTenantDB(db *sql.DB) *TenantDB {
+	return &TenantDB{db: db}
+}
+
+// DB retorna a conexão sem escopo de tenant (tabelas sem RLS, ex: clients)
+func (t *TenantDB) DB() *sql.DB {
+	return t.db
+}
+
+// InTenant executa fn numa transação com app.client_id = clientID
+func (t *TenantDB) InTenant(ctx context.Context, clientID string, fn func(tx *sql.Tx) error) error {
+	tx, err := t.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// is_local = true: o valor vale só até o fim da transação
+	if _, err := tx.ExecContext(ctx, `SELECT set_config('app.client_id', $1, true)`, clientID); err != nil {
+		return fmt.Errorf("erro ao definir tenant da transação: %w", err)
+	}
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// InContextTenant executa fn numa transação com o client_id do context
+func (t *TenantDB) InContextTenant(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	clientID, err := sharedContext.RequireClientID(ctx)
+	if err != nil {
+		return err
+	}
+	return t.InTenant(ctx, clientID, fn)
+}
@@ -4,13 +4,28 @@ import "errors"
 
 var (
 	ErrMonitoramentoNotFound     = errors.New("monitoramento não encontrado")
+	ErrDuplicateUpload           = errors.New("arquivo já importado")
+	ErrMonitoramentoProcessing   = errors.New("monitoramento em processamento")
+	ErrUploadFileNotFound        = errors.New("arquivo original do upload não encontrado")
+	ErrFileNotFound              = errors.New("arquivo não encontrado")
+	ErrInvalidStorageKey         = errors.New("chave de arquivo inválida")
 	ErrAreaMonitoramentoNotFound = errors.New("área de monitoramento não encontrada")
 	ErrJobNotFound               = errors.New("job não encontrado")
+	ErrJobNotControllable        = errors.New("tipo de job não pode ser cancelado nem pausado")
+	ErrInvalidJobTransition      = errors.New("operação não permitida no status atual do job")
+	ErrInvalidJobFilter          = errors.New("filtro de jobs inválido")
 	ErrInvalidCSV                = errors.New("arquivo CSV inválido")
 	ErrEmptyCSV                  = errors.New("arquivo CSV vazio")
+	ErrInvalidSpreadsheet        = errors.New("planilha XLSX inválida")
 	ErrInvalidStatus             = errors.New("status inválido")
 	ErrPragaNotFound             = errors.New("praga não encontrada")
 	ErrInvalidPragaData          = errors.New("dados de praga inválidos")
+	ErrInvalidValidationMode     = errors.New("modo de validação inválido")
+	ErrInvalidImportMode         = errors.New("modo de importação inválido")
+	ErrInvalidEncoding           = errors.New("encoding não suportado")
+	ErrImportProfileNotFound     = errors.New("perfil de importação não encontrado")
+	ErrInvalidImportProfile      = errors.New("perfil de importação inválido")
+	ErrDuplicateImportProfile    = errors.New("já existe um perfil de importação com este nome")
 
 	// Clients
 	ErrClientNotFound         = errors.New("client não encontrado")
@@ -18,4 +33,18 @@ var (
 	ErrClientUserLimitReached = errors.New("limite de usuários atingido")
 	ErrDuplicateEmail         = errors.New("email já cadastrado para este client")
 	ErrInvalidSlug            = errors.New("slug inválido")
+	ErrInvalidMaxUsers        = errors.New("max_users deve ser maior que zero")
+	ErrMaxUsersBelowActive    = errors.New("max_users menor que o número de usuários ativos")
+	ErrClientUserNotFound     = errors.New("usuário do client não encontrado")
+	ErrInvalidRole            = errors.New("papel inválido")
+	ErrCannotModifySelf       = errors.New("não é possível alterar o próprio usuário")
+	ErrInvalidInvite          = errors.New("convite inválido ou expirado")
+
+	// Schedules
+	ErrScheduleNotFound  = errors.New("agendamento não encontrado")
+	ErrInvalidSchedule   = errors.New("agendamento inválido")
+	ErrDuplicateSchedule = errors.New("já existe um agendamento com este nome")
+
+	// Tenancy
+	ErrTenantRequired = errors.New("client não identificado no contexto")
 )
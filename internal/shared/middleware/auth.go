@@ -20,8 +20,10 @@ const ClaimsKey contextKey = "userClaims"
 
 // Authenticator holds the OIDC provider and verifier.
 type Authenticator struct {
-	provider *oidc.Provider
-	verifier *oidc.IDTokenVerifier
+	provider  *oidc.Provider
+	verifier  *oidc.IDTokenVerifier
+	clientID  string
+	adminRole string
 }
 
 // NewAuthenticator creates a new Authenticator.
@@ -34,8 +36,10 @@ func NewAuthenticator(env *config.Env) (*Authenticator, error) {
 	verifier := provider.Verifier(&oidc.Config{ClientID: env.KeycloakClientID})
 
 	return &Authenticator{
-		provider: provider,
-		verifier: verifier,
+		provider:  provider,
+		verifier:  verifier,
+		clientID:  env.KeycloakClientID,
+		adminRole: env.KeycloakAdminRole,
 	}, nil
 }
 
@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+)
+
+// RolesFromClaims extrai as roles do token: realm_access.roles e resource_access.<clientID>.roles
+func RolesFromClaims(claims map[string]interface{}, clientID string) []string {
+	var roles []string
+
+	if realmAccess, ok := claims["realm_access"].(map[string]interface{}); ok {
+		roles = append(roles, toStrings(realmAccess["roles"])...)
+	}
+
+	if resourceAccess, ok := claims["resource_access"].(map[string]interface{}); ok {
+		if clientAccess, ok := resourceAccess[clientID].(map[string]interface{}); ok {
+			roles = append(roles, toStrings(clientAccess["roles"])...)
+		}
+	}
+
+	return roles
+}
+
+// RequireRole exige que o token tenha ao menos uma das roles (realm ou do client informado)
+func RequireRole(clientID string, roles ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			claims, ok := r.Context().Value(ClaimsKey).(map[string]interface{})
+			if !ok {
+				respondError(w, http.StatusUnauthorized, "No claims found")
+				return
+			}
+
+			if !hasAnyRole(RolesFromClaims(claims, clientID), roles) {
+				respondError(w, http.StatusForbidden, "Insufficient role")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireRole exige uma das roles, buscando client roles no client da API
+func (a *Authenticator) RequireRole(roles ...string) func(http.Handler) http.Handler {
+	return RequireRole(a.clientID, roles...)
+}
+
+// RequireAdminRole exige a role de administrador da plataforma (KEYCLOAK_ADMIN_ROLE)
+func (a *Authenticator) RequireAdminRole(next http.Handler) http.Handler {
+	return RequireRole(a.clientID, a.adminRole)(next)
+}
+
+// TenantRoleResolver resolve o papel do usuário dentro do seu client (ClientUser.Role)
+type TenantRoleResolver interface {
+	GetTenantRole(ctx context.Context, clientID, userID string) (string, error)
+}
+
+// RequireTenantRole exige que o usuário tenha um dos papéis no client do token
+func RequireTenantRole(resolver TenantRoleResolver, roles ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			clientID, _ := r.Context().Value(ClientIDKey).(string)
+			userID, _ := r.Context().Value(UserIDKey).(string)
+			if clientID == "" || userID == "" {
+				respondError(w, http.StatusForbidden, "No client association")
+				return
+			}
+
+			role, err := resolver.GetTenantRole(r.Context(), clientID, userID)
+			if err != nil {
+				respondError(w, http.StatusInternalServerError, "Failed to resolve role")
+				return
+			}
+
+			if !hasAnyRole([]string{role}, roles) {
+				respondError(w, http.StatusForbidden, "Insufficient role")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func hasAnyRole(have, want []string) bool {
+	for _, h := range have {
+		for _, w := range want {
+			if h != "" && h == w {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func toStrings(value interface{}) []string {
+	items, ok := value.([]interface{})
+	if !ok {
+		return nil
+	}
+
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			result = append(result, s)
+		}
+	}
+	return result
+}
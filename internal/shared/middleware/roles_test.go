@@ -0,0 +1,115 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func keycloakClaims() map[string]interface{} {
+	return map[string]interface{}{
+		"sub": "user-1",
+		"realm_access": map[string]interface{}{
+			"roles": []interface{}{"user", "offline_access"},
+		},
+		"resource_access": map[string]interface{}{
+			"agro-api": map[string]interface{}{
+				"roles": []interface{}{"platform-admin"},
+			},
+			"outro-client": map[string]interface{}{
+				"roles": []interface{}{"admin"},
+			},
+		},
+	}
+}
+
+func serveWithClaims(h http.Handler, claims map[string]interface{}) int {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if claims != nil {
+		req = req.WithContext(context.WithValue(req.Context(), ClaimsKey, claims))
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+var okHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+})
+
+func TestRolesFromClaims(t *testing.T) {
+	roles := RolesFromClaims(keycloakClaims(), "agro-api")
+
+	assert.ElementsMatch(t, []string{"user", "offline_access", "platform-admin"}, roles)
+}
+
+func TestRolesFromClaims_NoRoles(t *testing.T) {
+	roles := RolesFromClaims(map[string]interface{}{"sub": "user-1"}, "agro-api")
+
+	assert.Empty(t, roles)
+}
+
+func TestRequireRole(t *testing.T) {
+	tests := []struct {
+		name     string
+		roles    []string
+		claims   map[string]interface{}
+		expected int
+	}{
+		{"realm role", []string{"user"}, keycloakClaims(), http.StatusOK},
+		{"client role", []string{"platform-admin"}, keycloakClaims(), http.StatusOK},
+		{"role de outro client", []string{"admin"}, keycloakClaims(), http.StatusForbidden},
+		{"sem claims", []string{"user"}, nil, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := RequireRole("agro-api", tt.roles...)(okHandler)
+			assert.Equal(t, tt.expected, serveWithClaims(h, tt.claims))
+		})
+	}
+}
+
+type fakeRoleResolver struct {
+	role string
+	err  error
+}
+
+func (f fakeRoleResolver) GetTenantRole(ctx context.Context, clientID, userID string) (string, error) {
+	return f.role, f.err
+}
+
+func TestRequireTenantRole(t *testing.T) {
+	tests := []struct {
+		name     string
+		resolver fakeRoleResolver
+		clientID string
+		expected int
+	}{
+		{"admin do tenant", fakeRoleResolver{role: "admin"}, "client-1", http.StatusOK},
+		{"usuário comum", fakeRoleResolver{role: "user"}, "client-1", http.StatusForbidden},
+		{"não é membro", fakeRoleResolver{role: ""}, "client-1", http.StatusForbidden},
+		{"sem client", fakeRoleResolver{role: "admin"}, "", http.StatusForbidden},
+		{"erro ao resolver", fakeRoleResolver{err: errors.New("db")}, "client-1", http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := RequireTenantRole(tt.resolver, "admin")(okHandler)
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			ctx := context.WithValue(req.Context(), UserIDKey, "user-1")
+			if tt.clientID != "" {
+				ctx = context.WithValue(ctx, ClientIDKey, tt.clientID)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req.WithContext(ctx))
+
+			assert.Equal(t, tt.expected, rec.Code)
+		})
+	}
+}
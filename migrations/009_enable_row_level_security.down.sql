@@ -0,0 +1,15 @@
+DROP POLICY IF EXISTS tenant_isolation ON client_users;
+ALTER TABLE client_users NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE client_users DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS tenant_isolation ON jobs;
+ALTER TABLE jobs NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE jobs DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS tenant_isolation ON areas_monitoramento;
+ALTER TABLE areas_monitoramento NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE areas_monitoramento DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS tenant_isolation ON monitoramentos;
+ALTER TABLE monitoramentos NO FORCE ROW LEVEL SECURITY;
+ALTER TABLE monitoramentos DISABLE ROW LEVEL SECURITY;
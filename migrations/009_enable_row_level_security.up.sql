@@ -0,0 +1,29 @@
+-- Row-level security por tenant (segunda linha de defesa além do filtro por client_id)
+-- As policies usam current_setting('app.client_id', true), definido por transação
+-- pelo TenantDB. Sem o setting, current_setting retorna NULL e nenhuma linha é visível.
+-- FORCE aplica as policies também ao dono das tabelas; superusers continuam ignorando RLS,
+-- então a aplicação deve conectar com um usuário comum em produção.
+
+ALTER TABLE monitoramentos ENABLE ROW LEVEL SECURITY;
+ALTER TABLE monitoramentos FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON monitoramentos
+    USING (client_id::text = current_setting('app.client_id', true))
+    WITH CHECK (client_id::text = current_setting('app.client_id', true));
+
+ALTER TABLE areas_monitoramento ENABLE ROW LEVEL SECURITY;
+ALTER TABLE areas_monitoramento FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON areas_monitoramento
+    USING (client_id::text = current_setting('app.client_id', true))
+    WITH CHECK (client_id::text = current_setting('app.client_id', true));
+
+ALTER TABLE jobs ENABLE ROW LEVEL SECURITY;
+ALTER TABLE jobs FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON jobs
+    USING (client_id::text = current_setting('app.client_id', true))
+    WITH CHECK (client_id::text = current_setting('app.client_id', true));
+
+ALTER TABLE client_users ENABLE ROW LEVEL SECURITY;
+ALTER TABLE client_users FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON client_users
+    USING (client_id::text = current_setting('app.client_id', true))
+    WITH CHECK (client_id::text = current_setting('app.client_id', true));
@@ -0,0 +1,4 @@
+-- Soft-delete de clients: os dados ficam retidos durante a carência antes do purge
+ALTER TABLE clients ADD COLUMN deleted_at TIMESTAMP;
+
+CREATE INDEX idx_clients_deleted_at ON clients(deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,52 @@
+CREATE TABLE client_invites (
+    id              UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    client_id       UUID NOT NULL REFERENCES clients(id) ON DELETE CASCADE,
+    email           VARCHAR(255) NOT NULL,
+    role            VARCHAR(50) NOT NULL DEFAULT 'user' CHECK (role IN ('user', 'admin')),
+    token           VARCHAR(100) NOT NULL UNIQUE,
+    invited_by      VARCHAR(100) NOT NULL,
+    expires_at      TIMESTAMP NOT NULL,
+    accepted_at     TIMESTAMP,
+    created_at      TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_client_invites_client_id ON client_invites(client_id);
+
+ALTER TABLE client_invites ENABLE ROW LEVEL SECURITY;
+ALTER TABLE client_invites FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON client_invites
+    USING (client_id::text = current_setting('app.client_id', true))
+    WITH CHECK (client_id::text = current_setting('app.client_id', true));
+
+-- Subqueries evitam o produto cartesiano dos JOINs e permitem contar usuários por papel/status
+DROP VIEW IF EXISTS client_stats;
+CREATE VIEW client_stats AS
+SELECT
+    c.id,
+    c.name,
+    c.slug,
+    c.max_users,
+    COALESCE(u.current_users, 0) AS current_users,
+    COALESCE(u.admin_users, 0) AS admin_users,
+    COALESCE(u.inactive_users, 0) AS inactive_users,
+    COALESCE(i.pending_invites, 0) AS pending_invites,
+    c.max_users - COALESCE(u.current_users, 0) AS available_slots,
+    (SELECT COUNT(*) FROM monitoramentos m WHERE m.client_id = c.id) AS total_monitoramentos,
+    (SELECT COUNT(*) FROM areas_monitoramento a WHERE a.client_id = c.id) AS total_areas,
+    c.active,
+    c.created_at
+FROM clients c
+LEFT JOIN (
+    SELECT client_id,
+           COUNT(*) FILTER (WHERE active) AS current_users,
+           COUNT(*) FILTER (WHERE active AND role = 'admin') AS admin_users,
+           COUNT(*) FILTER (WHERE NOT active) AS inactive_users
+    FROM client_users
+    GROUP BY client_id
+) u ON u.client_id = c.id
+LEFT JOIN (
+    SELECT client_id, COUNT(*) AS pending_invites
+    FROM client_invites
+    WHERE accepted_at IS NULL AND expires_at > NOW()
+    GROUP BY client_id
+) i ON i.client_id = c.id;
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS monitoramento_erros;
+
+ALTER TABLE monitoramentos
+    DROP COLUMN IF EXISTS cabecalho,
+    DROP COLUMN IF EXISTS total_erros;
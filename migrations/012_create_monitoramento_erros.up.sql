@@ -0,0 +1,22 @@
+-- Erros de parsing por linha, com o registro original para gerar o CSV de correção
+ALTER TABLE monitoramentos
+    ADD COLUMN total_erros INT NOT NULL DEFAULT 0,
+    ADD COLUMN cabecalho JSONB NOT NULL DEFAULT '[]';
+
+CREATE TABLE monitoramento_erros (
+    id                 BIGSERIAL PRIMARY KEY,
+    monitoramento_id   UUID NOT NULL REFERENCES monitoramentos(id) ON DELETE CASCADE,
+    client_id          UUID NOT NULL REFERENCES clients(id) ON DELETE CASCADE,
+    linha              INT NOT NULL,
+    erro               TEXT NOT NULL,
+    registro           JSONB NOT NULL DEFAULT '[]',
+    created_at         TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_monitoramento_erros_monitoramento ON monitoramento_erros(monitoramento_id, linha);
+
+ALTER TABLE monitoramento_erros ENABLE ROW LEVEL SECURITY;
+ALTER TABLE monitoramento_erros FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON monitoramento_erros
+    USING (client_id::text = current_setting('app.client_id', true))
+    WITH CHECK (client_id::text = current_setting('app.client_id', true));
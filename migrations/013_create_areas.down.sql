@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_areas_monitoramento_area_id;
+DROP INDEX IF EXISTS idx_areas_monitoramento_external_key;
+
+ALTER TABLE areas_monitoramento
+    DROP COLUMN IF EXISTS area_id,
+    DROP COLUMN IF EXISTS external_key;
+
+DROP TABLE IF EXISTS areas;
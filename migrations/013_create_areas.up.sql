@@ -0,0 +1,26 @@
+-- Área física (talhão) identificada por uma chave externa estável por tenant:
+-- o "Id" do CSV ou, na falta dele, "Cod.Fazenda/Quadra".
+-- Cada linha de areas_monitoramento passa a ser uma observação dessa área.
+CREATE TABLE areas (
+    id             UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    client_id      UUID NOT NULL REFERENCES clients(id) ON DELETE CASCADE,
+    external_key   VARCHAR(255) NOT NULL,
+    cod_fazenda    VARCHAR(50),
+    quadra         VARCHAR(50),
+    created_at     TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at     TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (client_id, external_key)
+);
+
+ALTER TABLE areas ENABLE ROW LEVEL SECURITY;
+ALTER TABLE areas FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON areas
+    USING (client_id::text = current_setting('app.client_id', true))
+    WITH CHECK (client_id::text = current_setting('app.client_id', true));
+
+ALTER TABLE areas_monitoramento
+    ADD COLUMN external_key VARCHAR(255),
+    ADD COLUMN area_id UUID REFERENCES areas(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_areas_monitoramento_external_key ON areas_monitoramento(client_id, external_key);
+CREATE INDEX idx_areas_monitoramento_area_id ON areas_monitoramento(area_id, created_at);
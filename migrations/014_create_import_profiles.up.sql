@@ -0,0 +1,20 @@
+-- Perfis de importação: mapeamento de colunas, separador, encoding e vocabulário de presença por client
+CREATE TABLE import_profiles (
+    id           UUID PRIMARY KEY,
+    client_id    UUID NOT NULL REFERENCES clients(id) ON DELETE CASCADE,
+    nome         VARCHAR(100) NOT NULL,
+    separador    VARCHAR(10) NOT NULL DEFAULT '',
+    encoding     VARCHAR(20) NOT NULL DEFAULT '',
+    colunas      JSONB NOT NULL DEFAULT '{}',
+    pragas       JSONB NOT NULL DEFAULT '{}',
+    vocabulario  JSONB NOT NULL DEFAULT '{}',
+    created_at   TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at   TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (client_id, nome)
+);
+
+ALTER TABLE import_profiles ENABLE ROW LEVEL SECURITY;
+ALTER TABLE import_profiles FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON import_profiles
+    USING (client_id::text = current_setting('app.client_id', true))
+    WITH CHECK (client_id::text = current_setting('app.client_id', true));
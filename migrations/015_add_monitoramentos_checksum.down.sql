@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_monitoramentos_checksum;
+
+ALTER TABLE monitoramentos DROP COLUMN IF EXISTS checksum;
@@ -0,0 +1,4 @@
+-- Checksum SHA-256 do arquivo enviado, para recusar uploads repetidos do mesmo tenant
+ALTER TABLE monitoramentos ADD COLUMN checksum VARCHAR(64);
+
+CREATE INDEX idx_monitoramentos_checksum ON monitoramentos(client_id, checksum) WHERE checksum IS NOT NULL;
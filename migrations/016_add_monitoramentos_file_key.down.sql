@@ -0,0 +1 @@
+ALTER TABLE monitoramentos DROP COLUMN IF EXISTS file_key;
@@ -0,0 +1,7 @@
+-- Chave do arquivo original no storage, para auditoria, reprocessamento e download
+ALTER TABLE monitoramentos ADD COLUMN file_key VARCHAR(512);
+
+-- Uploads anteriores foram gravados em <client_id>/<id><extensão do nome do arquivo>
+UPDATE monitoramentos
+SET file_key = client_id::text || '/' || id::text || COALESCE(substring(nome_arquivo from '\.[^./]*$'), '')
+WHERE client_id IS NOT NULL;
@@ -0,0 +1,2 @@
+ALTER TABLE jobs DROP COLUMN IF EXISTS max_attempts;
+ALTER TABLE jobs DROP COLUMN IF EXISTS attempts;
@@ -0,0 +1,3 @@
+-- Tentativas de processamento: falhas temporárias são reenfileiradas com backoff até max_attempts
+ALTER TABLE jobs ADD COLUMN attempts INT NOT NULL DEFAULT 0;
+ALTER TABLE jobs ADD COLUMN max_attempts INT NOT NULL DEFAULT 3;
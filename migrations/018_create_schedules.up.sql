@@ -0,0 +1,25 @@
+-- Agendamentos recorrentes da plataforma (cron de 5 campos, horários em UTC), executados pelo worker.
+-- Não pertencem a um tenant: sem client_id e sem RLS, como clients
+CREATE TABLE schedules (
+    id           UUID PRIMARY KEY,
+    name         VARCHAR(100) NOT NULL UNIQUE,
+    task         VARCHAR(100) NOT NULL,
+    cron         VARCHAR(100) NOT NULL,
+    payload      JSONB NOT NULL DEFAULT '{}',
+    enabled      BOOLEAN NOT NULL DEFAULT TRUE,
+    next_run_at  TIMESTAMP NOT NULL,
+    last_run_at  TIMESTAMP,
+    last_error   TEXT,
+    created_at   TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at   TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_schedules_next_run_at ON schedules(next_run_at) WHERE enabled;
+
+-- Agendamentos padrão; a primeira execução acontece no primeiro ciclo do worker.
+-- purge_deleted_clients apaga dados definitivamente e fica desabilitado até o admin habilitá-lo
+INSERT INTO schedules (id, name, task, cron, payload, enabled, next_run_at) VALUES
+    (uuid_generate_v4(), 'snapshot-client-stats', 'snapshot_client_stats', '0 3 * * *', '{}', TRUE, NOW() AT TIME ZONE 'UTC'),
+    (uuid_generate_v4(), 'generate-stats-reports', 'generate_stats_reports', '30 3 * * 1', '{"days": 7}', TRUE, NOW() AT TIME ZONE 'UTC'),
+    (uuid_generate_v4(), 'purge-deleted-clients', 'purge_deleted_clients', '0 4 * * *', '{}', FALSE, NOW() AT TIME ZONE 'UTC'),
+    (uuid_generate_v4(), 'cleanup-jobs', 'cleanup_jobs', '30 4 * * *', '{"stale_after_hours": 24, "older_than_days": 30}', TRUE, NOW() AT TIME ZONE 'UTC');
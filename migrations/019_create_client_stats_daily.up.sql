@@ -0,0 +1,20 @@
+-- Estatísticas diárias dos clients, gravadas pelo agendamento snapshot_client_stats
+-- (a view client_stats só mostra o momento atual)
+CREATE TABLE client_stats_daily (
+    client_id             UUID NOT NULL REFERENCES clients(id) ON DELETE CASCADE,
+    day                   DATE NOT NULL,
+    current_users         INT NOT NULL DEFAULT 0,
+    admin_users           INT NOT NULL DEFAULT 0,
+    inactive_users        INT NOT NULL DEFAULT 0,
+    pending_invites       INT NOT NULL DEFAULT 0,
+    total_monitoramentos  INT NOT NULL DEFAULT 0,
+    total_areas           INT NOT NULL DEFAULT 0,
+    created_at            TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (client_id, day)
+);
+
+ALTER TABLE client_stats_daily ENABLE ROW LEVEL SECURITY;
+ALTER TABLE client_stats_daily FORCE ROW LEVEL SECURITY;
+CREATE POLICY tenant_isolation ON client_stats_daily
+    USING (client_id::text = current_setting('app.client_id', true))
+    WITH CHECK (client_id::text = current_setting('app.client_id', true));
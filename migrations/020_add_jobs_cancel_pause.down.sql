@@ -0,0 +1,7 @@
+ALTER TABLE jobs DROP COLUMN IF EXISTS stop_requested;
+
+UPDATE jobs SET status = 'failed', completed_at = COALESCE(completed_at, NOW()) WHERE status IN ('cancelled', 'paused');
+
+ALTER TABLE jobs DROP CONSTRAINT IF EXISTS jobs_status_check;
+ALTER TABLE jobs ADD CONSTRAINT jobs_status_check
+    CHECK (status IN ('pending', 'processing', 'completed', 'failed'));
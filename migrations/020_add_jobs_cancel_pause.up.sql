@@ -0,0 +1,7 @@
+-- Cancelamento e pausa de jobs: status cancelled (final) e paused (retomável)
+ALTER TABLE jobs DROP CONSTRAINT IF EXISTS jobs_status_check;
+ALTER TABLE jobs ADD CONSTRAINT jobs_status_check
+    CHECK (status IN ('pending', 'processing', 'completed', 'failed', 'cancelled', 'paused'));
+
+-- Pedido do usuário para um job em processing; o worker o atende entre os lotes de itens
+ALTER TABLE jobs ADD COLUMN stop_requested VARCHAR(20) CHECK (stop_requested IN ('cancelled', 'paused'));